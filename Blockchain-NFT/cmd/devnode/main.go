@@ -20,6 +20,7 @@ import (
 	configpkg "storytelling-blockchain/internal/config"
 	"storytelling-blockchain/internal/network"
 	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/scheduler"
 	"storytelling-blockchain/internal/storage"
 	"storytelling-blockchain/internal/supabase"
 	"storytelling-blockchain/internal/wallet"
@@ -35,16 +36,18 @@ func (devTokenVerifier) VerifyToken(_ context.Context, token string) (string, er
 }
 
 type config struct {
-	NodeID         string
-	ClusterNodes   []string
-	HTTPAddr       string
-	AllowedOrigins []string
-	Passphrase     string
-	FaultTolerance int
-	SeedUsers      []string
-	DataDir        string
-	Supabase       supabaseSettings
-	IPFSEndpoint   string
+	NodeID          string
+	ClusterNodes    []string
+	HTTPAddr        string
+	AllowedOrigins  []string
+	Passphrase      string
+	FaultTolerance  int
+	SeedUsers       []string
+	DataDir         string
+	GenesisPath     string
+	Supabase        supabaseSettings
+	IPFSEndpoint    string
+	MintProposalTTL time.Duration
 }
 
 type supabaseSettings struct {
@@ -72,8 +75,24 @@ func main() {
 		"faultTolerance", cfg.FaultTolerance,
 		"storage", cfg.DataDir,
 		"ipfs", cfg.IPFSEndpoint,
+		"genesis", cfg.GenesisPath,
 	)
 
+	genesis := blockchain.DefaultGenesis()
+	if cfg.GenesisPath != "" {
+		loaded, genesisErr := blockchain.LoadGenesis(cfg.GenesisPath)
+		if genesisErr != nil {
+			fail(logger, "genesis load failed", genesisErr)
+		}
+		genesis = loaded
+	} else {
+		logger.Warn("genesis file not configured, using built-in development genesis")
+	}
+
+	if len(genesis.Validators) > 0 && !isGenesisValidator(genesis, cfg.NodeID) {
+		logger.Warn("node is not part of the genesis validator set", "node", cfg.NodeID, "chain", genesis.ChainID)
+	}
+
 	stateStore, err := storage.NewBadgerStorage(storage.BadgerConfig{Path: cfg.DataDir})
 	if err != nil {
 		fail(logger, "state store init failed", err)
@@ -84,10 +103,14 @@ func main() {
 		}
 	}()
 
-	chain, err := blockchain.LoadBlockchain(stateStore)
+	chain, err := blockchain.LoadBlockchainWithGenesis(stateStore, genesis)
 	if err != nil {
+		if errors.Is(err, blockchain.ErrGenesisMismatch) {
+			fail(logger, "stored chain does not match configured genesis; refusing to boot", err)
+		}
 		fail(logger, "blockchain load failed", err)
 	}
+	logger.Info("chain loaded", "chain", genesis.ChainID, "genesis", chain.Blocks()[0].Hash, "height", chain.LatestBlock().Index)
 
 	var ipfsClient storage.IPFSClient
 	if cfg.IPFSEndpoint != "" {
@@ -154,13 +177,14 @@ func main() {
 	})
 
 	apiServer, err := api.New(api.Config{
-		Chain:          chain,
-		WalletManager:  manager,
-		Middleware:     middleware,
-		Observer:       bus,
-		ConsensusNode:  cfg.NodeID,
-		ConsensusNodes: cfg.ClusterNodes,
-		IPFS:           ipfsClient,
+		Chain:           chain,
+		WalletManager:   manager,
+		Middleware:      middleware,
+		Observer:        bus,
+		ConsensusNode:   cfg.NodeID,
+		ConsensusNodes:  cfg.ClusterNodes,
+		IPFS:            ipfsClient,
+		MintProposalTTL: cfg.MintProposalTTL,
 	})
 	if err != nil {
 		fail(logger, "api init failed", err)
@@ -186,6 +210,19 @@ func main() {
 	}
 	defer service.Stop()
 
+	mintScheduler, err := scheduler.NewMintScheduler(scheduler.MintSchedulerConfig{
+		Chain:    chain,
+		Observer: bus,
+		Proposer: service,
+		IPFS:     ipfsClient,
+		NodeID:   cfg.NodeID,
+		Nodes:    cfg.ClusterNodes,
+	})
+	if err != nil {
+		fail(logger, "mint scheduler init failed", err)
+	}
+	go mintScheduler.Run(ctx)
+
 	if supabaseClient != nil {
 		if cfg.Supabase.ServiceRoleKey != "" {
 			pollInterval := cfg.Supabase.PollInterval
@@ -253,6 +290,8 @@ func loadConfig() (config, error) {
 	dataDirFlag := flag.String("data-dir", "", "path to persistent storage directory")
 	ipfsFlag := flag.String("ipfs-api", "", "IPFS API endpoint")
 	pollFlag := flag.Duration("supabase-poll-interval", 0, "Supabase poll interval (e.g. 30s)")
+	genesisFlag := flag.String("genesis", "", "path to genesis.json")
+	mintTTLFlag := flag.Duration("mint-proposal-ttl", 0, "how long a mint proposal waits for approvals (e.g. 168h)")
 	flag.Parse()
 
 	setFlags := map[string]bool{}
@@ -287,6 +326,8 @@ func loadConfig() (config, error) {
 	envDataDir := strings.TrimSpace(os.Getenv("DEVNODE_DATA_DIR"))
 	envIPFS := strings.TrimSpace(os.Getenv("DEVNODE_IPFS_API"))
 	envPoll := strings.TrimSpace(os.Getenv("SUPABASE_POLL_INTERVAL"))
+	envGenesis := strings.TrimSpace(os.Getenv("DEVNODE_GENESIS_FILE"))
+	envMintTTL := strings.TrimSpace(os.Getenv("DEVNODE_MINT_PROPOSAL_TTL"))
 	envSupabaseURL := strings.TrimSpace(os.Getenv("SUPABASE_URL"))
 	envSupabaseAnon := strings.TrimSpace(os.Getenv("SUPABASE_ANON_KEY"))
 	envSupabaseService := strings.TrimSpace(os.Getenv("SUPABASE_SERVICE_KEY"))
@@ -303,11 +344,13 @@ func loadConfig() (config, error) {
 		filePeers        []string
 		fileOrigins      []string
 		fileDataDir      string
+		fileGenesis      string
 		fileIPFS         string
 		filePoll         time.Duration
 		fileSupabaseURL  string
 		fileSupabaseAnon string
 		fileSupabaseServ string
+		fileMintTTL      time.Duration
 	)
 
 	if fileCfg != nil {
@@ -318,11 +361,13 @@ func loadConfig() (config, error) {
 		filePeers = append([]string{}, fileCfg.Network.BootstrapPeers...)
 		fileOrigins = append([]string{}, fileCfg.API.AllowedOrigins...)
 		fileDataDir = strings.TrimSpace(fileCfg.Storage.BadgerPath)
+		fileGenesis = strings.TrimSpace(fileCfg.Chain.GenesisFile)
 		fileIPFS = strings.TrimSpace(fileCfg.Storage.IPFSAPI)
 		filePoll = fileCfg.Supabase.PollInterval.Duration
 		fileSupabaseURL = fileCfg.Supabase.URL
 		fileSupabaseAnon = fileCfg.Supabase.AnonKey
 		fileSupabaseServ = fileCfg.Supabase.ServiceRoleKey
+		fileMintTTL = fileCfg.API.MintProposalTTL.Duration
 	}
 
 	nodeID := pickString(setFlags["node"], *nodeFlag, envNode, fileNodeID, defaultNodeID)
@@ -331,6 +376,12 @@ func loadConfig() (config, error) {
 	dataDir := pickString(setFlags["data-dir"], *dataDirFlag, envDataDir, fileDataDir, defaultDataDir)
 	ipfsEndpoint := pickString(setFlags["ipfs-api"], *ipfsFlag, envIPFS, fileIPFS, "")
 
+	defaultGenesis := ""
+	if _, err := os.Stat("config/genesis.json"); err == nil {
+		defaultGenesis = "config/genesis.json"
+	}
+	genesisPath := pickString(setFlags["genesis"], *genesisFlag, envGenesis, fileGenesis, defaultGenesis)
+
 	peersList := pickStringSlice(setFlags["peers"], *peersFlag, envPeers, filePeers, nil)
 	clusterSize := pickInt(setFlags["cluster-size"], *clusterFlag, envCluster, len(filePeers), 1)
 	if len(peersList) == 0 {
@@ -351,6 +402,7 @@ func loadConfig() (config, error) {
 	supabaseAnon := pickString(false, "", envSupabaseAnon, fileSupabaseAnon, "")
 	supabaseService := pickString(false, "", envSupabaseService, fileSupabaseServ, "")
 	pollInterval := pickDuration(setFlags["supabase-poll-interval"], *pollFlag, envPoll, filePoll, defaultPollInterval)
+	mintTTL := pickDuration(setFlags["mint-proposal-ttl"], *mintTTLFlag, envMintTTL, fileMintTTL, 0)
 
 	return config{
 		NodeID:         nodeID,
@@ -361,13 +413,15 @@ func loadConfig() (config, error) {
 		FaultTolerance: faultTolerance,
 		SeedUsers:      seedUsers,
 		DataDir:        dataDir,
+		GenesisPath:    genesisPath,
 		Supabase: supabaseSettings{
 			URL:            supabaseURL,
 			AnonKey:        supabaseAnon,
 			ServiceRoleKey: supabaseService,
 			PollInterval:   pollInterval,
 		},
-		IPFSEndpoint: ipfsEndpoint,
+		IPFSEndpoint:    ipfsEndpoint,
+		MintProposalTTL: mintTTL,
 	}, nil
 }
 
@@ -511,6 +565,15 @@ func pickDuration(flagUsed bool, flagVal time.Duration, envVal string, fileVal t
 	return fallback
 }
 
+func isGenesisValidator(genesis blockchain.Genesis, nodeID string) bool {
+	for _, validator := range genesis.Validators {
+		if validator.ID == nodeID {
+			return true
+		}
+	}
+	return false
+}
+
 func buildClusterTransport(nodes []string) map[string]*network.Node {
 	transport := network.NewInMemoryTransport()
 	registry := make(map[string]*network.Node, len(nodes))
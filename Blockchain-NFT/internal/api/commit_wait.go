@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/types"
+)
+
+const (
+	// DefaultCommitWaitTimeout is how long a request with wait=committed
+	// blocks when it does not set a timeout.
+	DefaultCommitWaitTimeout = 5 * time.Second
+	// MaxCommitWaitTimeout bounds the timeout a request may ask for.
+	MaxCommitWaitTimeout = 30 * time.Second
+
+	// commitWaitPoll is how often a waiting request re-reads the receipt
+	// without an event, since the bus drops events for a full subscriber.
+	commitWaitPoll = 250 * time.Millisecond
+)
+
+var (
+	errInvalidWaitMode    = errors.New(`wait must be "committed"`)
+	errInvalidWaitTimeout = errors.New("timeout must be a positive duration of at most 30s")
+	errEventsUnavailable  = errors.New("event streaming not configured")
+)
+
+// commitWaiter follows the observer bus for a request made with
+// wait=committed. It subscribes before the transaction is submitted so that
+// a commit racing the submission is not missed.
+type commitWaiter struct {
+	bus     *observer.Bus
+	id      string
+	events  <-chan observer.Event
+	timeout time.Duration
+}
+
+// newCommitWaiter reads the wait and timeout query parameters. It returns a
+// nil waiter when the request does not wait for the commit.
+func (a *API) newCommitWaiter(r *http.Request) (*commitWaiter, error) {
+	query := r.URL.Query()
+	switch query.Get("wait") {
+	case "":
+		return nil, nil
+	case "committed":
+	default:
+		return nil, errInvalidWaitMode
+	}
+
+	timeout := DefaultCommitWaitTimeout
+	if raw := query.Get("timeout"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 || parsed > MaxCommitWaitTimeout {
+			return nil, errInvalidWaitTimeout
+		}
+		timeout = parsed
+	}
+
+	if a.observer == nil {
+		return nil, errEventsUnavailable
+	}
+
+	id, events := a.observer.Subscribe(16)
+	if id == "" {
+		return nil, errEventsUnavailable
+	}
+
+	return &commitWaiter{bus: a.observer, id: id, events: events, timeout: timeout}, nil
+}
+
+// close releases the waiter's subscription. It is safe on a nil waiter.
+func (cw *commitWaiter) close() {
+	if cw != nil {
+		cw.bus.Unsubscribe(cw.id)
+	}
+}
+
+// wait blocks until the receipt of txID leaves the pending state, re-reading
+// it whenever a block or transaction event arrives and on every poll tick. The
+// receipt is read once more when the timeout passes, so a commit whose event
+// was dropped is still reported. It reports false when the transaction is
+// still pending at the timeout or the request is cancelled first.
+func (cw *commitWaiter) wait(ctx context.Context, receipt func(string) (types.Receipt, bool), txID string) (types.Receipt, bool) {
+	timer := time.NewTimer(cw.timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(commitWaitPoll)
+	defer ticker.Stop()
+
+	settled := func() (types.Receipt, bool) {
+		current, ok := receipt(txID)
+		if !ok || current.Status == types.TxStatusPending {
+			return types.Receipt{}, false
+		}
+		return current, true
+	}
+
+	events := cw.events
+	check := true
+	for {
+		if check {
+			if current, ok := settled(); ok {
+				return current, true
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return types.Receipt{}, false
+		case <-timer.C:
+			return settled()
+		case <-ticker.C:
+			check = true
+		case event, ok := <-events:
+			if !ok {
+				// The bus closed; keep polling until the timeout.
+				events = nil
+				check = false
+				continue
+			}
+			switch event.Type {
+			case observer.EventBlockCommitted, observer.EventTransactionCommitted, observer.EventTransactionRejected:
+				check = true
+			default:
+				check = false
+			}
+		}
+	}
+}
+
+func writeCommitWaitError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errEventsUnavailable) {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeError(w, http.StatusBadRequest, err.Error())
+}
+
+// writeSubmitted answers a write request whose transaction was submitted.
+// Without a waiter it answers 201 with body at once. A waiting request
+// answers 201 once the transaction is committed, adding the block index and
+// hash to body and then calling committed, when set, to add what the commit
+// changed; 409 if the transaction is rejected meanwhile; and 202 with the
+// transaction ID if the timeout passes first.
+func (a *API) writeSubmitted(w http.ResponseWriter, r *http.Request, waiter *commitWaiter, txID string, body map[string]interface{}, committed func(body map[string]interface{})) {
+	if waiter == nil {
+		writeJSON(w, http.StatusCreated, body)
+		return
+	}
+
+	receipt, done := waiter.wait(r.Context(), a.chain.Receipt, txID)
+	switch {
+	case !done:
+		body["tx_id"] = txID
+		body["status"] = types.TxStatusPending
+		writeJSON(w, http.StatusAccepted, body)
+	case receipt.Status == types.TxStatusRejected:
+		writeError(w, http.StatusConflict, receipt.Reason)
+	default:
+		body["tx_id"] = txID
+		body["status"] = receipt.Status
+		body["block_index"] = receipt.BlockIndex
+		body["block_hash"] = receipt.BlockHash
+		if committed != nil {
+			committed(body)
+		}
+		writeJSON(w, http.StatusCreated, body)
+	}
+}
@@ -0,0 +1,156 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/consensus"
+	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/types"
+)
+
+// finalizingProposer commits each proposal in the background through the
+// chain finalizer, as a consensus round would.
+type finalizingProposer struct {
+	chain *blockchain.Blockchain
+	bus   *observer.Bus
+}
+
+func (p finalizingProposer) Propose(_ string, txs []types.Transaction) error {
+	block, err := p.chain.BuildBlock(txs)
+	if err != nil {
+		return err
+	}
+	go consensus.NewChainFinalizer(p.chain, p.bus)(block)
+	return nil
+}
+
+type waitResponse struct {
+	TxID           string            `json:"tx_id"`
+	Status         string            `json:"status"`
+	BlockIndex     int               `json:"block_index"`
+	BlockHash      string            `json:"block_hash"`
+	ProposalID     string            `json:"proposal_id"`
+	ProposalStatus string            `json:"proposal_status"`
+	Transaction    types.Transaction `json:"transaction"`
+}
+
+func decodeWaitResponse(t *testing.T, body []byte) waitResponse {
+	t.Helper()
+
+	var resp waitResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	return resp
+}
+
+func TestContributeStoryWaitsForCommit(t *testing.T) {
+	api, chain, manager, bus := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
+	api.WithConsensus("node-1", finalizingProposer{chain: chain, bus: bus})
+
+	w := postAuthenticated(api, "/api/story/contribute?wait=committed&timeout=2s", `{"story_id":"story-1","story_line":"Narrative"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 once committed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeWaitResponse(t, w.Body.Bytes())
+	latest := chain.LatestBlock()
+	if resp.Status != types.TxStatusCommitted || resp.BlockIndex != latest.Index || resp.BlockHash != latest.Hash {
+		t.Fatalf("expected the commit of block %d, got %+v", latest.Index, resp)
+	}
+	if resp.TxID != resp.Transaction.TxID || latest.Transactions[0].TxID != resp.TxID {
+		t.Fatalf("expected the committed block to hold the contribution, got %+v", resp)
+	}
+}
+
+func TestContributeStoryWaitTimesOut(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
+
+	w := postAuthenticated(api, "/api/story/contribute?wait=committed&timeout=20ms", `{"story_id":"story-1","story_line":"Narrative"}`)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected 202 when the commit does not arrive in time, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeWaitResponse(t, w.Body.Bytes())
+	if resp.Status != types.TxStatusPending || resp.TxID == "" || resp.TxID != resp.Transaction.TxID {
+		t.Fatalf("expected a pending tx id, got %+v", resp)
+	}
+	if _, ok := chain.PendingTransaction(resp.TxID); !ok {
+		t.Fatalf("expected the contribution to stay pending")
+	}
+}
+
+func TestContributeStoryRejectsInvalidWaitMode(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
+
+	for _, query := range []string{"?wait=soon", "?wait=committed&timeout=never", "?wait=committed&timeout=1m"} {
+		if w := postAuthenticated(api, "/api/story/contribute"+query, `{"story_id":"story-1","story_line":"Narrative"}`); w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", query, w.Code)
+		}
+	}
+	if len(chain.PendingTransactions()) != 0 {
+		t.Fatalf("expected nothing to be queued")
+	}
+}
+
+func TestMintStoryWaitsForCommit(t *testing.T) {
+	api, chain, manager, bus := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
+	author, _ := manager.GetWalletBySupabaseID("user-123")
+	commitTestTransactions(t, chain, signTestTransaction(t, chain, manager, blockchain.TxTypeContribution, 510, types.ContributionPayload{
+		Contribution: types.Contribution{ContributorID: "user-123", WalletAddress: author.Address, StoryID: "story-1", StoryLine: "Once", Timestamp: 510},
+	}))
+	closeTestStory(t, chain, manager, "story-1")
+	api.WithConsensus("node-1", finalizingProposer{chain: chain, bus: bus})
+
+	w := postAuthenticated(api, "/api/story/story-1/mint?wait=committed", `{"title":"Story","summary":"A tale"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 once committed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	resp := decodeWaitResponse(t, w.Body.Bytes())
+	if resp.Status != types.TxStatusCommitted || resp.ProposalID != resp.TxID || resp.BlockHash != chain.LatestBlock().Hash {
+		t.Fatalf("expected the proposal to be committed in the latest block, got %+v", resp)
+	}
+	// The sole author's weight meets the threshold, so the proposal minted.
+	if resp.ProposalStatus != mintProposalMinted {
+		t.Fatalf("expected the proposal to have minted, got %+v", resp)
+	}
+}
+
+func TestCommitWaitRereadsReceiptWithoutEvents(t *testing.T) {
+	bus := observer.NewBus()
+	t.Cleanup(bus.Close)
+
+	// The receipt commits after the first read, but no event announces it,
+	// as when the bus drops events for a full subscriber.
+	receiptAfter := func(pendingReads int) func(string) (types.Receipt, bool) {
+		reads := 0
+		return func(txID string) (types.Receipt, bool) {
+			reads++
+			if reads <= pendingReads {
+				return types.Receipt{TxID: txID, Status: types.TxStatusPending, BlockIndex: -1}, true
+			}
+			return types.Receipt{TxID: txID, Status: types.TxStatusCommitted, BlockIndex: 4}, true
+		}
+	}
+
+	for name, timeout := range map[string]time.Duration{"at the timeout": 20 * time.Millisecond, "on a poll": 5 * time.Second} {
+		id, events := bus.Subscribe(1)
+		waiter := &commitWaiter{bus: bus, id: id, events: events, timeout: timeout}
+
+		receipt, done := waiter.wait(context.Background(), receiptAfter(1), "tx-1")
+		waiter.close()
+		if !done || receipt.Status != types.TxStatusCommitted || receipt.BlockIndex != 4 {
+			t.Fatalf("expected the commit to be read %s, got %+v, %v", name, receipt, done)
+		}
+	}
+}
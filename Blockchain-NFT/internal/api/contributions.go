@@ -0,0 +1,115 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/supabase"
+	"storytelling-blockchain/internal/types"
+)
+
+func (a *API) handleAmendContribution(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	var request struct {
+		StoryLine string `json:"story_line"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || strings.TrimSpace(request.StoryLine) == "" {
+		writeError(w, http.StatusBadRequest, "story_line is required")
+		return
+	}
+
+	record, ok := a.revisableContribution(w, mux.Vars(r)["txID"], userID)
+	if !ok {
+		return
+	}
+
+	if !a.chain.CanContribute(record.StoryID, userID) {
+		writeError(w, http.StatusForbidden, blockchain.ErrNotStoryContributor.Error())
+		return
+	}
+
+	story, _ := a.chain.GetStory(record.StoryID)
+	if err := blockchain.CheckLineLength(story.Rules, request.StoryLine); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, record.StoryID, blockchain.TxTypeAmendContribution, types.AmendContributionPayload{
+		TxID:          mux.Vars(r)["txID"],
+		ContributorID: userID,
+		StoryLine:     request.StoryLine,
+	})
+}
+
+func (a *API) handleRetractContribution(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	record, ok := a.revisableContribution(w, mux.Vars(r)["txID"], userID)
+	if !ok {
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, record.StoryID, blockchain.TxTypeRetractContribution, types.RetractContributionPayload{
+		TxID:          mux.Vars(r)["txID"],
+		ContributorID: userID,
+	})
+}
+
+func (a *API) handleGetContributionHistory(w http.ResponseWriter, r *http.Request) {
+	txID := mux.Vars(r)["txID"]
+
+	record, ok := a.chain.GetContribution(txID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "contribution not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"tx_id":          txID,
+		"story_id":       record.StoryID,
+		"contributor_id": record.ContributorID,
+		"retracted":      record.Retracted,
+		"revisions":      a.chain.ContributionHistory(txID),
+	})
+}
+
+// revisableContribution loads a contribution the user may still amend or
+// retract, writing the error response and returning false otherwise.
+func (a *API) revisableContribution(w http.ResponseWriter, txID, userID string) (types.ContributionRecord, bool) {
+	record, ok := a.chain.GetContribution(txID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "contribution not found")
+		return types.ContributionRecord{}, false
+	}
+
+	if record.ContributorID != userID {
+		writeError(w, http.StatusForbidden, "only the original contributor can revise the contribution")
+		return types.ContributionRecord{}, false
+	}
+
+	if record.Retracted {
+		writeError(w, http.StatusConflict, "contribution already retracted")
+		return types.ContributionRecord{}, false
+	}
+
+	if story, ok := a.chain.GetStory(record.StoryID); !ok || story.Status != types.StoryStatusOpen {
+		writeError(w, http.StatusConflict, "story is closed")
+		return types.ContributionRecord{}, false
+	}
+
+	return record, true
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestAmendAndRetractContributionEndpoints(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
+
+	var submitted struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	submit := func(path, body string) {
+		t.Helper()
+		resp := postAuthenticated(api, path, body)
+		if resp.Code != http.StatusCreated {
+			t.Fatalf("expected 201 from %s, got %d: %s", path, resp.Code, resp.Body.String())
+		}
+		if err := json.Unmarshal(resp.Body.Bytes(), &submitted); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		commitTestTransactions(t, chain, submitted.Transaction)
+	}
+
+	submit("/api/story/contribute", `{"story_id":"story-1","story_line":"Once upon a tiem"}`)
+	original := submitted.Transaction.TxID
+
+	if w := postAuthenticated(api, "/api/contribution/missing/amend", `{"story_line":"x"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 amending unknown contribution, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/contribution/"+original+"/amend", `{"story_line":"  "}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for empty amendment, got %d", w.Code)
+	}
+
+	submit("/api/contribution/"+original+"/amend", `{"story_line":"Once upon a time"}`)
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/story-1", nil))
+	var story struct {
+		Contributions []types.Contribution `json:"contributions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &story); err != nil {
+		t.Fatalf("failed to decode story: %v", err)
+	}
+	if len(story.Contributions) != 1 || story.Contributions[0].StoryLine != "Once upon a time" || story.Contributions[0].TxID != original {
+		t.Fatalf("expected the amended line, got %+v", story.Contributions)
+	}
+
+	submit("/api/contribution/"+original+"/retract", "")
+
+	if w := postAuthenticated(api, "/api/contribution/"+original+"/retract", ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 retracting twice, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/contribution/"+original+"/history", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var history struct {
+		Retracted bool                         `json:"retracted"`
+		Revisions []types.ContributionRevision `json:"revisions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("failed to decode history: %v", err)
+	}
+	if !history.Retracted || len(history.Revisions) != 3 || history.Revisions[0].StoryLine != "Once upon a tiem" || history.Revisions[2].Revision != 2 {
+		t.Fatalf("unexpected history: %+v", history)
+	}
+}
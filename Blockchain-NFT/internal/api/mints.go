@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/supabase"
+	"storytelling-blockchain/internal/types"
+)
+
+// Proposal states reported by a mint request that waited for its commit.
+const (
+	mintProposalPending = "pending"
+	mintProposalMinted  = "minted"
+)
+
+func (a *API) handleApproveMint(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	proposal, ok := a.chain.MintProposal(mux.Vars(r)["proposalID"])
+	if !ok {
+		writeError(w, http.StatusNotFound, blockchain.ErrUnknownMintProposal.Error())
+		return
+	}
+
+	if proposal.ExpiresAt <= types.NowUnix() {
+		writeError(w, http.StatusConflict, blockchain.ErrMintProposalExpired.Error())
+		return
+	}
+
+	if blockchain.ApprovalUnits(proposal, userID) == 0 {
+		writeError(w, http.StatusForbidden, blockchain.ErrNotMintAuthor.Error())
+		return
+	}
+
+	for _, approver := range proposal.Approvals {
+		if approver == userID {
+			writeError(w, http.StatusConflict, blockchain.ErrAlreadyApproved.Error())
+			return
+		}
+	}
+
+	a.proposeStoryTransaction(w, userID, proposal.NFT.StoryID, blockchain.TxTypeApproveMint, types.ApproveMintPayload{
+		ProposalID: proposal.ID,
+		ApproverID: userID,
+	})
+}
+
+func (a *API) handleGetMintProposal(w http.ResponseWriter, r *http.Request) {
+	proposal, ok := a.chain.MintProposal(mux.Vars(r)["proposalID"])
+	if !ok {
+		writeError(w, http.StatusNotFound, blockchain.ErrUnknownMintProposal.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, proposal)
+}
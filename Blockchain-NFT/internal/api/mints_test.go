@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/internal/wallet"
+)
+
+func TestMintProposalEndpoints(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	generator, err := wallet.NewGenerator("passphrase")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	coAuthor, err := generator.GenerateWalletForUser("user-456")
+	if err != nil {
+		t.Fatalf("failed to generate wallet: %v", err)
+	}
+	chain.RegisterWallet(coAuthor)
+	author, _ := manager.GetWalletBySupabaseID("user-123")
+
+	commitTestTransactions(t, chain, signTestTransactionAs(t, chain, manager, "user-456", blockchain.TxTypeCreateStory, 500, types.CreateStoryPayload{
+		Story: types.StoryRecord{ID: "story-1", Title: "Shared", CreatorID: "user-456", Rules: types.StoryRules{MintWhileOpen: true}, CreatedAt: 500},
+	}))
+	for i, line := range []struct {
+		userID string
+		wallet types.Wallet
+		text   string
+	}{
+		{"user-456", coAuthor, "The ferry left without us"},
+		{"user-123", author, "So we swam"},
+		{"user-456", coAuthor, "Halfway across the fog came down"},
+	} {
+		commitTestTransactions(t, chain, signTestTransactionAs(t, chain, manager, line.userID, blockchain.TxTypeContribution, int64(510+i), types.ContributionPayload{
+			Contribution: types.Contribution{ContributorID: line.userID, WalletAddress: line.wallet.Address, StoryID: "story-1", StoryLine: line.text, Timestamp: int64(510 + i)},
+		}))
+	}
+
+	resp := postAuthenticated(api, "/api/story/story-1/mint", `{"title":"Shared","summary":"A swim"}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201 proposing the mint, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var proposed struct {
+		ProposalID  string            `json:"proposal_id"`
+		NFT         types.NFT         `json:"nft"`
+		Threshold   int64             `json:"threshold"`
+		ExpiresAt   int64             `json:"expires_at"`
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &proposed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if proposed.Threshold != blockchain.DefaultMintThreshold || proposed.ExpiresAt != 777+int64(DefaultMintProposalTTL.Seconds()) || proposed.Transaction.Type != blockchain.TxTypeProposeMint {
+		t.Fatalf("unexpected proposal response: %+v", proposed)
+	}
+	commitTestTransactions(t, chain, proposed.Transaction)
+
+	if _, minted := chain.GetNFT(proposed.NFT.TokenID); minted {
+		t.Fatalf("expected the minority author's proposal to wait for approval")
+	}
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/mint/"+proposed.ProposalID, nil))
+	var proposal types.MintProposal
+	if err := json.Unmarshal(w.Body.Bytes(), &proposal); err != nil {
+		t.Fatalf("failed to decode proposal: %v", err)
+	}
+	if w.Code != http.StatusOK || len(proposal.Approvals) != 1 || proposal.Approvals[0] != "user-123" {
+		t.Fatalf("unexpected proposal: %d %+v", w.Code, proposal)
+	}
+
+	if w := postAuthenticated(api, "/api/story/story-1/mint", `{"title":"Shared","summary":"Again"}`); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 while a proposal is pending, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/mint/"+proposed.ProposalID+"/approve", ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 approving twice, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/mint/missing/approve", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 approving an unknown proposal, got %d", w.Code)
+	}
+
+	commitTestTransactions(t, chain, signTestTransactionAs(t, chain, manager, "user-456", blockchain.TxTypeApproveMint, 800, types.ApproveMintPayload{
+		ProposalID: proposed.ProposalID,
+		ApproverID: "user-456",
+	}))
+
+	if _, minted := chain.GetNFT(proposed.NFT.TokenID); !minted {
+		t.Fatalf("expected the approval to mint the nft")
+	}
+	w = httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/mint/"+proposed.ProposalID, nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected finalized proposal to be gone, got %d", w.Code)
+	}
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/supabase"
+	"storytelling-blockchain/internal/types"
+)
+
+// ownedNFT resolves the caller and the active NFT they own, writing the
+// error response when there is none.
+func (a *API) ownedNFT(w http.ResponseWriter, r *http.Request) (string, types.NFT, bool) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return "", types.NFT{}, false
+	}
+
+	nft, ok := a.chain.GetNFT(mux.Vars(r)["tokenID"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "nft not found")
+		return "", types.NFT{}, false
+	}
+
+	if nft.Status == types.NFTStatusBurned {
+		writeError(w, http.StatusConflict, blockchain.ErrNFTBurned.Error())
+		return "", types.NFT{}, false
+	}
+
+	if nft.OwnerID != userID {
+		writeError(w, http.StatusForbidden, "only the owner can change the nft")
+		return "", types.NFT{}, false
+	}
+
+	return userID, nft, true
+}
+
+func (a *API) handleBurnNFT(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Reason string `json:"reason"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	userID, nft, ok := a.ownedNFT(w, r)
+	if !ok {
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, nft.TokenID, blockchain.TxTypeBurnNFT, types.BurnNFTPayload{
+		TokenID: nft.TokenID,
+		OwnerID: userID,
+		Reason:  request.Reason,
+	})
+}
+
+func (a *API) handleFreezeMetadata(w http.ResponseWriter, r *http.Request) {
+	userID, nft, ok := a.ownedNFT(w, r)
+	if !ok {
+		return
+	}
+
+	if nft.MetadataFrozen {
+		writeError(w, http.StatusConflict, blockchain.ErrMetadataFrozen.Error())
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, nft.TokenID, blockchain.TxTypeFreezeMetadata, types.FreezeMetadataPayload{
+		TokenID: nft.TokenID,
+		OwnerID: userID,
+	})
+}
+
+func (a *API) handleUpdateMetadata(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		ImageIPFSCID    string `json:"image_ipfs_cid"`
+		MetadataIPFSCID string `json:"metadata_ipfs_cid"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if request.ImageIPFSCID == "" || request.MetadataIPFSCID == "" {
+		writeError(w, http.StatusBadRequest, "image_ipfs_cid and metadata_ipfs_cid are required")
+		return
+	}
+
+	userID, nft, ok := a.ownedNFT(w, r)
+	if !ok {
+		return
+	}
+
+	if nft.MetadataFrozen {
+		writeError(w, http.StatusConflict, blockchain.ErrMetadataFrozen.Error())
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, nft.TokenID, blockchain.TxTypeUpdateMetadata, types.UpdateMetadataPayload{
+		TokenID:         nft.TokenID,
+		OwnerID:         userID,
+		ImageIPFSCID:    request.ImageIPFSCID,
+		MetadataIPFSCID: request.MetadataIPFSCID,
+	})
+}
+
+// handleModerationBurn relays a moderation burn. The validators' approvals
+// authorize it, so the request itself needs no user session.
+func (a *API) handleModerationBurn(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Reason             string            `json:"reason"`
+		ValidatorApprovals map[string]string `json:"validator_approvals"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if request.Reason == "" || len(request.ValidatorApprovals) == 0 {
+		writeError(w, http.StatusBadRequest, "reason and validator_approvals are required")
+		return
+	}
+
+	nft, ok := a.chain.GetNFT(mux.Vars(r)["tokenID"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "nft not found")
+		return
+	}
+
+	if nft.Status == types.NFTStatusBurned {
+		writeError(w, http.StatusConflict, blockchain.ErrNFTBurned.Error())
+		return
+	}
+
+	tx, err := blockchain.NewTransaction(blockchain.TxTypeBurnNFT, types.NowUnix(), types.BurnNFTPayload{
+		TokenID:            nft.TokenID,
+		Reason:             request.Reason,
+		ValidatorApprovals: request.ValidatorApprovals,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode burn")
+		return
+	}
+
+	if err := a.submitTransaction(nft.TokenID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"transaction": tx,
+	})
+}
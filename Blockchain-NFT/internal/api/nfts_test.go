@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/types"
+)
+
+func TestNFTLifecycleEndpoints(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	owner, ok := manager.GetWalletBySupabaseID("user-123")
+	if !ok {
+		t.Fatalf("expected wallet to exist")
+	}
+
+	createTestStory(t, chain, manager, "story-1")
+	addTestLine(t, chain, manager, owner.SupabaseUserID, "story-1", "Once upon a time", 510)
+	closeTestStory(t, chain, manager, "story-1")
+	commitTestTransactions(t, chain, newStoryMintTx(t, chain, manager, "story-1", "nft-1"))
+
+	getNFT := func() types.NFT {
+		w := httptest.NewRecorder()
+		api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/nft/nft-1", nil))
+		var nft types.NFT
+		if err := json.Unmarshal(w.Body.Bytes(), &nft); err != nil {
+			t.Fatalf("failed to decode nft: %v", err)
+		}
+		return nft
+	}
+	submit := func(path, body string, want int) {
+		t.Helper()
+		w := postAuthenticated(api, path, body)
+		if w.Code != want {
+			t.Fatalf("POST %s: expected %d, got %d: %s", path, want, w.Code, w.Body.String())
+		}
+		if want != http.StatusCreated {
+			return
+		}
+		var resp struct {
+			Transaction types.Transaction `json:"transaction"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		commitTestTransactions(t, chain, resp.Transaction)
+	}
+
+	if nft := getNFT(); nft.Status != types.NFTStatusActive {
+		t.Fatalf("expected an active nft, got %+v", nft)
+	}
+
+	submit("/api/nft/nft-1/metadata", `{"image_ipfs_cid":"image-2"}`, http.StatusBadRequest)
+	submit("/api/nft/missing/metadata", `{"image_ipfs_cid":"image-2","metadata_ipfs_cid":"meta-2"}`, http.StatusNotFound)
+	submit("/api/nft/nft-1/metadata", `{"image_ipfs_cid":"image-2","metadata_ipfs_cid":"meta-2"}`, http.StatusCreated)
+	submit("/api/nft/nft-1/freeze", `{}`, http.StatusCreated)
+	if nft := getNFT(); !nft.MetadataFrozen || nft.MetadataIPFSCID != "meta-2" {
+		t.Fatalf("expected frozen updated metadata, got %+v", nft)
+	}
+	submit("/api/nft/nft-1/metadata", `{"image_ipfs_cid":"image-3","metadata_ipfs_cid":"meta-3"}`, http.StatusConflict)
+
+	submit("/api/moderation/nft/nft-1/burn", `{"reason":"spam"}`, http.StatusBadRequest)
+
+	submit("/api/nft/nft-1/burn", `{"reason":"withdrawn"}`, http.StatusCreated)
+	if nft := getNFT(); nft.Status != types.NFTStatusBurned || nft.BurnReason != "withdrawn" {
+		t.Fatalf("expected a burned nft, got %+v", nft)
+	}
+	submit("/api/nft/nft-1/burn", `{}`, http.StatusConflict)
+	submit("/api/moderation/nft/nft-1/burn", `{"reason":"spam","validator_approvals":{"node-1":"sig"}}`, http.StatusConflict)
+
+	if history := chain.NFTHistory("nft-1"); len(history) != 2 || history[1].Type != blockchain.TxTypeBurnNFT {
+		t.Fatalf("expected the burn in the history, got %+v", history)
+	}
+}
@@ -3,7 +3,9 @@ package api
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
@@ -18,7 +20,6 @@ import (
 	"storytelling-blockchain/internal/supabase"
 	"storytelling-blockchain/internal/types"
 	"storytelling-blockchain/internal/wallet"
-	"storytelling-blockchain/pkg/utils"
 )
 
 // Config bundles the dependencies required to construct the API server.
@@ -31,8 +32,14 @@ type Config struct {
 	ConsensusNode  string
 	ConsensusNodes []string
 	IPFS           storage.IPFSClient
+	// MintProposalTTL is how long a mint proposal waits for approvals; zero
+	// means DefaultMintProposalTTL.
+	MintProposalTTL time.Duration
 }
 
+// DefaultMintProposalTTL is the lifetime of mint proposals unless configured.
+const DefaultMintProposalTTL = 7 * 24 * time.Hour
+
 // Proposer encapsulates the ability to submit transactions into consensus.
 type Proposer interface {
 	Propose(nodeID string, txs []types.Transaction) error
@@ -52,6 +59,8 @@ type API struct {
 	metrics        apiMetrics
 	startedAt      time.Time
 	ipfs           storage.IPFSClient
+
+	mintProposalTTL time.Duration
 }
 
 type apiMetrics struct {
@@ -109,6 +118,12 @@ func New(cfg Config) (*API, error) {
 		consensusNodes: append([]string{}, cfg.ConsensusNodes...),
 		startedAt:      time.Now().UTC(),
 		ipfs:           cfg.IPFS,
+
+		mintProposalTTL: cfg.MintProposalTTL,
+	}
+
+	if api.mintProposalTTL <= 0 {
+		api.mintProposalTTL = DefaultMintProposalTTL
 	}
 
 	api.registerRoutes()
@@ -131,9 +146,21 @@ func (a *API) registerRoutes() {
 	base.HandleFunc("/health/ready", a.handleReadiness).Methods(http.MethodGet)
 	base.HandleFunc("/blockchain", a.handleBlockchainState).Methods(http.MethodGet)
 	base.HandleFunc("/wallet/{userID}", a.handleGetWallet).Methods(http.MethodGet)
+	base.HandleFunc("/wallet/{userID}/proof", a.handleGetWalletProof).Methods(http.MethodGet)
+	base.HandleFunc("/wallet/{userID}/nonce", a.handleGetWalletNonce).Methods(http.MethodGet)
 	base.HandleFunc("/story/{storyID}", a.handleGetStory).Methods(http.MethodGet)
+	base.HandleFunc("/story/{storyID}/members", a.handleGetStoryMembers).Methods(http.MethodGet)
+	base.HandleFunc("/story/{storyID}/rules", a.handleGetStoryRules).Methods(http.MethodGet)
+	base.HandleFunc("/story/{storyID}/conflicts", a.handleGetStoryConflicts).Methods(http.MethodGet)
 	base.HandleFunc("/nft/{tokenID}", a.handleGetNFT).Methods(http.MethodGet)
 	base.HandleFunc("/nft/{tokenID}/authors", a.handleGetNFTAuthors).Methods(http.MethodGet)
+	base.HandleFunc("/nft/{tokenID}/proof", a.handleGetNFTProof).Methods(http.MethodGet)
+	base.HandleFunc("/nft/{tokenID}/history", a.handleGetNFTHistory).Methods(http.MethodGet)
+	base.HandleFunc("/tx/{txID}", a.handleGetTransactionReceipt).Methods(http.MethodGet)
+	base.HandleFunc("/tx/{txID}/proof", a.handleGetTransactionProof).Methods(http.MethodGet)
+	base.HandleFunc("/contribution/{txID}/history", a.handleGetContributionHistory).Methods(http.MethodGet)
+	base.HandleFunc("/mint/{proposalID}", a.handleGetMintProposal).Methods(http.MethodGet)
+	base.HandleFunc("/moderation/nft/{tokenID}/burn", a.handleModerationBurn).Methods(http.MethodPost)
 	base.HandleFunc("/events", a.handleEvents).Methods(http.MethodGet)
 
 	authSub := base.PathPrefix("").Subrouter()
@@ -141,15 +168,31 @@ func (a *API) registerRoutes() {
 		authSub.Use(a.middleware.AuthMiddleware().Wrap)
 	}
 
+	authSub.HandleFunc("/story", a.handleCreateStory).Methods(http.MethodPost)
 	authSub.HandleFunc("/story/contribute", a.handleContributeStory).Methods(http.MethodPost)
+	authSub.HandleFunc("/story/{storyID}/close", a.handleCloseStory).Methods(http.MethodPost)
+	authSub.HandleFunc("/story/{storyID}/fork", a.handleForkStory).Methods(http.MethodPost)
 	authSub.HandleFunc("/story/{storyID}/mint", a.handleMintStory).Methods(http.MethodPost)
+	authSub.HandleFunc("/mint/{proposalID}/approve", a.handleApproveMint).Methods(http.MethodPost)
+	authSub.HandleFunc("/story/{storyID}/invite", a.handleInviteContributor).Methods(http.MethodPost)
+	authSub.HandleFunc("/story/{storyID}/accept", a.handleAcceptInvite).Methods(http.MethodPost)
+	authSub.HandleFunc("/story/{storyID}/revoke", a.handleRevokeContributor).Methods(http.MethodPost)
+	authSub.HandleFunc("/contribution/{txID}/amend", a.handleAmendContribution).Methods(http.MethodPost)
+	authSub.HandleFunc("/contribution/{txID}/retract", a.handleRetractContribution).Methods(http.MethodPost)
+	authSub.HandleFunc("/nft/{tokenID}/transfer", a.handleTransferNFT).Methods(http.MethodPost)
+	authSub.HandleFunc("/nft/{tokenID}/shares/transfer", a.handleTransferShares).Methods(http.MethodPost)
+	authSub.HandleFunc("/nft/{tokenID}/burn", a.handleBurnNFT).Methods(http.MethodPost)
+	authSub.HandleFunc("/nft/{tokenID}/freeze", a.handleFreezeMetadata).Methods(http.MethodPost)
+	authSub.HandleFunc("/nft/{tokenID}/metadata", a.handleUpdateMetadata).Methods(http.MethodPost)
 }
 
 func (a *API) handleHealth(w http.ResponseWriter, r *http.Request) {
+	mempool := a.chain.MempoolStats()
 	status := map[string]interface{}{
 		"status":               "ok",
 		"blocks":               len(a.chain.Blocks()),
-		"pending_transactions": len(a.chain.PendingTransactions()),
+		"pending_transactions": mempool.Size,
+		"mempool":              mempool,
 		"consensus": map[string]interface{}{
 			"attached":     a.proposer != nil,
 			"primary_node": a.consensusNode,
@@ -213,6 +256,22 @@ func (a *API) handleGetWallet(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, wallet)
 }
 
+// handleGetWalletNonce reports the nonce the wallet's next signed transaction
+// must carry, counting its committed and pending mempool transactions.
+func (a *API) handleGetWalletNonce(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+
+	if _, ok := a.chain.GetWalletBySupabaseID(userID); !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"user_id":    userID,
+		"next_nonce": a.chain.NextNonce(userID),
+	})
+}
+
 func (a *API) handleContributeStory(w http.ResponseWriter, r *http.Request) {
 	userID, ok := supabase.UserIDFromContext(r.Context())
 	if !ok {
@@ -220,9 +279,17 @@ func (a *API) handleContributeStory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	waiter, err := a.newCommitWaiter(r)
+	if err != nil {
+		writeCommitWaitError(w, err)
+		return
+	}
+	defer waiter.close()
+
 	var request struct {
-		StoryID   string `json:"story_id"`
-		StoryLine string `json:"story_line"`
+		StoryID    string `json:"story_id"`
+		StoryLine  string `json:"story_line"`
+		ParentTxID string `json:"parent_tx_id"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
@@ -235,6 +302,17 @@ func (a *API) handleContributeStory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	story, ok := a.chain.GetStory(request.StoryID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	}
+
+	if story.Status != types.StoryStatusOpen {
+		writeError(w, http.StatusConflict, "story is closed")
+		return
+	}
+
 	wallet, ok := a.walletManager.GetWalletBySupabaseID(userID)
 	if !ok {
 		writeError(w, http.StatusNotFound, "wallet not found")
@@ -242,6 +320,7 @@ func (a *API) handleContributeStory(w http.ResponseWriter, r *http.Request) {
 	}
 
 	contribution := types.Contribution{
+		ParentTxID:    request.ParentTxID,
 		ContributorID: userID,
 		WalletAddress: wallet.Address,
 		StoryID:       request.StoryID,
@@ -249,47 +328,33 @@ func (a *API) handleContributeStory(w http.ResponseWriter, r *http.Request) {
 		Timestamp:     types.NowUnix(),
 	}
 
-	signature, err := a.walletManager.SignContribution(wallet, contribution)
-	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to sign contribution")
+	if err := a.chain.CheckContribution(contribution); err != nil {
+		writeError(w, storyRuleStatus(err), err.Error())
 		return
 	}
 
-	payload := struct {
-		Contribution types.Contribution `json:"contribution"`
-		Timestamp    int64              `json:"timestamp"`
-	}{Contribution: contribution, Timestamp: contribution.Timestamp}
-
-	payloadBytes, err := json.Marshal(payload)
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeContribution, contribution.Timestamp, a.chain.NextNonce(wallet.SupabaseUserID), types.ContributionPayload{Contribution: contribution})
 	if err != nil {
 		writeError(w, http.StatusInternalServerError, "failed to marshal contribution")
 		return
 	}
 
-	tx := types.Transaction{
-		Type:      "contribution",
-		Data:      payload,
-		Timestamp: contribution.Timestamp,
-		Signature: signature,
+	tx.Signature, err = a.walletManager.SignTransaction(wallet, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign contribution")
+		return
 	}
-	tx.TxID = utils.ComputeSHA256(payloadBytes)
-
-	a.chain.EnqueueTransaction(tx)
-
-	nodeID := a.selectConsensusNode(userID)
 
-	if a.proposer != nil && nodeID != "" {
-		if err := a.proposer.Propose(nodeID, []types.Transaction{tx}); err != nil {
-			writeError(w, http.StatusInternalServerError, "failed to propose transaction")
-			return
-		}
+	if err := a.submitTransaction(userID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
 	}
 
 	a.metrics.incContributions()
 
-	writeJSON(w, http.StatusCreated, map[string]interface{}{
+	a.writeSubmitted(w, r, waiter, tx.TxID, map[string]interface{}{
 		"transaction": tx,
-	})
+	}, nil)
 }
 
 func (a *API) handleGetStory(w http.ResponseWriter, r *http.Request) {
@@ -300,43 +365,51 @@ func (a *API) handleGetStory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contributions := a.chain.StoryContributions(storyID)
-	if len(contributions) == 0 {
+	record, ok := a.chain.GetStory(storyID)
+	if !ok {
 		writeError(w, http.StatusNotFound, "story not found")
 		return
 	}
 
-	authors := blockchain.AggregateAuthors(contributions)
-	nfts := a.chain.NFTsByStory(storyID)
+	contributions := a.chain.StoryContributions(storyID)
+	editions := a.chain.StoryEditions(storyID)
 
 	var (
-		title   string
-		summary string
+		title      = record.Title
+		summary    string
+		policyName = r.URL.Query().Get("policy")
 	)
 
-	if len(nfts) > 0 {
-		latest := nfts[0]
-		for _, nft := range nfts[1:] {
-			if nft.BlockIndex > latest.BlockIndex {
-				latest = nft
-				continue
-			}
-			if nft.BlockIndex == latest.BlockIndex && nft.MintedAt > latest.MintedAt {
-				latest = nft
-			}
-		}
+	if policyName == "" {
+		policyName = record.Rules.AuthorshipPolicy
+	}
 
+	if len(editions) > 0 {
+		latest := editions[len(editions)-1]
 		title = latest.Title
 		summary = latest.Summary
 	}
 
+	policy, err := blockchain.AuthorshipPolicyByName(policyName)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"story_id":      storyID,
-		"title":         title,
-		"summary":       summary,
-		"contributions": contributions,
-		"authors":       authors,
-		"nfts":          nfts,
+		"story_id":          storyID,
+		"title":             title,
+		"summary":           summary,
+		"status":            record.Status,
+		"creator_id":        record.CreatorID,
+		"rules":             record.Rules,
+		"lineage":           record.Lineage,
+		"contributions":     contributions,
+		"authorship_policy": policy.Name(),
+		"authors":           blockchain.StoryAuthors(types.Story{ID: storyID, Contributions: contributions, Lineage: record.Lineage}, policy, types.NowUnix()),
+		"edition":           record.Edition,
+		"editions":          editions,
+		"mint_proposals":    a.chain.MintProposalsByStory(storyID),
 	})
 }
 
@@ -371,13 +444,116 @@ func (a *API) handleGetNFTAuthors(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	authors := append([]types.Author{nft.MainAuthor}, nft.CoAuthors...)
 	writeJSON(w, http.StatusOK, map[string]interface{}{
-		"token_id": tokenID,
-		"authors":  authors,
+		"token_id":     nft.TokenID,
+		"total_shares": blockchain.TotalShareUnits,
+		"authors":      a.chain.ShareHoldings(tokenID),
+	})
+}
+
+func (a *API) handleGetNFTHistory(w http.ResponseWriter, r *http.Request) {
+	tokenID := mux.Vars(r)["tokenID"]
+	if tokenID == "" {
+		writeError(w, http.StatusBadRequest, "token id is required")
+		return
+	}
+
+	nft, ok := a.chain.GetNFT(tokenID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "nft not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"token_id":      tokenID,
+		"owner_id":      nft.OwnerID,
+		"owner_address": nft.OwnerAddress,
+		"history":       a.chain.NFTHistory(tokenID),
 	})
 }
 
+func (a *API) handleGetTransactionReceipt(w http.ResponseWriter, r *http.Request) {
+	txID := mux.Vars(r)["txID"]
+	if txID == "" {
+		writeError(w, http.StatusBadRequest, "transaction id is required")
+		return
+	}
+
+	receipt, ok := a.chain.Receipt(txID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, receipt)
+}
+
+func (a *API) handleGetTransactionProof(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txID := vars["txID"]
+	if txID == "" {
+		writeError(w, http.StatusBadRequest, "transaction id is required")
+		return
+	}
+
+	proof, ok := a.chain.TransactionProof(txID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, proof)
+}
+
+func (a *API) handleGetWalletProof(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["userID"]
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "user id is required")
+		return
+	}
+
+	a.writeStateProof(w, r, blockchain.StateKeyWallet+userID, "wallet not found")
+}
+
+func (a *API) handleGetNFTProof(w http.ResponseWriter, r *http.Request) {
+	tokenID := mux.Vars(r)["tokenID"]
+	if tokenID == "" {
+		writeError(w, http.StatusBadRequest, "token id is required")
+		return
+	}
+
+	a.writeStateProof(w, r, blockchain.StateKeyNFT+tokenID, "nft not found")
+}
+
+// writeStateProof serves a state proof for key at the height given by the
+// optional "height" query parameter, defaulting to the latest block.
+func (a *API) writeStateProof(w http.ResponseWriter, r *http.Request, key, notFound string) {
+	height := a.chain.LatestBlock().Index
+	if raw := r.URL.Query().Get("height"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "height must be an integer")
+			return
+		}
+		height = parsed
+	}
+
+	proof, err := a.chain.StateProof(height, key)
+	switch {
+	case errors.Is(err, blockchain.ErrHeightOutOfRange):
+		writeError(w, http.StatusBadRequest, "height out of range")
+		return
+	case errors.Is(err, blockchain.ErrStateKeyNotFound):
+		writeError(w, http.StatusNotFound, notFound)
+		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, "failed to build state proof")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, proof)
+}
+
 func (a *API) handleMintStory(w http.ResponseWriter, r *http.Request) {
 	userID, ok := supabase.UserIDFromContext(r.Context())
 	if !ok {
@@ -385,6 +561,13 @@ func (a *API) handleMintStory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	waiter, err := a.newCommitWaiter(r)
+	if err != nil {
+		writeCommitWaitError(w, err)
+		return
+	}
+	defer waiter.close()
+
 	vars := mux.Vars(r)
 	storyID := vars["storyID"]
 	if storyID == "" {
@@ -407,38 +590,50 @@ func (a *API) handleMintStory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	contributions := a.chain.StoryContributions(storyID)
-	if len(contributions) == 0 {
+	story, err := a.chain.NextEditionStory(storyID, strings.TrimSpace(request.Title), strings.TrimSpace(request.Summary))
+	switch {
+	case errors.Is(err, blockchain.ErrUnknownStory):
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	case errors.Is(err, blockchain.ErrNoContributions):
 		writeError(w, http.StatusNotFound, "story has no contributions")
 		return
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
-	authors := blockchain.AggregateAuthors(contributions)
+	policy, err := blockchain.AuthorshipPolicyByName(story.AuthorshipPolicy)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	authors := blockchain.StoryAuthors(story, policy, types.NowUnix())
 	if len(authors) == 0 {
 		writeError(w, http.StatusNotFound, "story has no authors")
 		return
 	}
 
-	if authors[0].SupabaseUserID != userID {
-		writeError(w, http.StatusForbidden, "only the main author can mint the story")
+	if blockchain.AllocateShares(authors)[userID] == 0 {
+		writeError(w, http.StatusForbidden, "only an author of the story can propose a mint")
 		return
 	}
 
-	if existing := a.chain.NFTsByStory(storyID); len(existing) > 0 {
-		writeError(w, http.StatusConflict, "story already minted")
+	if pending := a.chain.MintProposalsByStory(storyID); len(pending) > 0 {
+		writeError(w, http.StatusConflict, "story already has a pending mint proposal")
 		return
 	}
 
-	if a.ipfs == nil {
-		writeError(w, http.StatusServiceUnavailable, "ipfs unavailable")
+	wallet, ok := a.walletManager.GetWalletBySupabaseID(userID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
 		return
 	}
 
-	story := types.Story{
-		ID:            storyID,
-		Title:         strings.TrimSpace(request.Title),
-		Summary:       strings.TrimSpace(request.Summary),
-		Contributions: contributions,
+	if a.ipfs == nil {
+		writeError(w, http.StatusServiceUnavailable, "ipfs unavailable")
+		return
 	}
 
 	nft, err := blockchain.MintNFT(story, a.ipfs)
@@ -447,7 +642,8 @@ func (a *API) handleMintStory(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, blockchain.ErrNoContributions),
 			errors.Is(err, blockchain.ErrMissingStoryID),
-			errors.Is(err, blockchain.ErrMissingTitle):
+			errors.Is(err, blockchain.ErrMissingTitle),
+			errors.Is(err, blockchain.ErrUnknownAuthorshipPolicy):
 			status = http.StatusBadRequest
 		case errors.Is(err, blockchain.ErrNilIPFSClient):
 			status = http.StatusServiceUnavailable
@@ -457,35 +653,243 @@ func (a *API) handleMintStory(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	payload, err := json.Marshal(nft)
+	payload := types.ProposeMintPayload{
+		NFT:        nft,
+		ProposerID: userID,
+		ExpiresAt:  nft.MintedAt + int64(a.mintProposalTTL/time.Second),
+	}
+
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeProposeMint, nft.MintedAt, a.chain.NextNonce(wallet.SupabaseUserID), payload)
 	if err != nil {
-		writeError(w, http.StatusInternalServerError, "failed to encode nft")
+		writeError(w, http.StatusInternalServerError, "failed to encode mint proposal")
 		return
 	}
 
-	tx := types.Transaction{
-		Type:      "mint_nft",
-		Data:      nft,
-		Timestamp: nft.MintedAt,
+	tx.Signature, err = a.walletManager.SignTransaction(wallet, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign mint proposal")
+		return
 	}
-	tx.TxID = utils.ComputeSHA256(payload)
 
-	a.chain.EnqueueTransaction(tx)
+	if err := a.submitTransaction(storyID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
 
-	nodeID := a.selectConsensusNode(storyID)
-	if a.proposer != nil && nodeID != "" {
-		if err := a.proposer.Propose(nodeID, []types.Transaction{tx}); err != nil {
-			writeError(w, http.StatusInternalServerError, "failed to propose transaction")
-			return
+	// The threshold comes from the story's rules, which never change.
+	record, _ := a.chain.GetStory(storyID)
+	a.writeSubmitted(w, r, waiter, tx.TxID, map[string]interface{}{
+		"proposal_id": tx.TxID,
+		"nft":         nft,
+		"threshold":   blockchain.MintThreshold(record),
+		"expires_at":  payload.ExpiresAt,
+		"transaction": tx,
+	}, func(body map[string]interface{}) {
+		// The wait covers the proposal, not the mint: the proposal mints at
+		// once only when the proposer's weight meets the threshold.
+		body["proposal_status"] = mintProposalPending
+		if _, minted := a.chain.GetNFT(nft.TokenID); minted {
+			body["proposal_status"] = mintProposalMinted
 		}
+	})
+}
+
+func (a *API) handleTransferNFT(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	tokenID := mux.Vars(r)["tokenID"]
+	if tokenID == "" {
+		writeError(w, http.StatusBadRequest, "token id is required")
+		return
+	}
+
+	var request struct {
+		ToUserID string `json:"to_user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if request.ToUserID == "" {
+		writeError(w, http.StatusBadRequest, "to_user_id is required")
+		return
+	}
+
+	if request.ToUserID == userID {
+		writeError(w, http.StatusBadRequest, "cannot transfer an nft to its owner")
+		return
+	}
+
+	nft, ok := a.chain.GetNFT(tokenID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "nft not found")
+		return
+	}
+
+	if nft.OwnerID != userID {
+		writeError(w, http.StatusForbidden, "only the owner can transfer the nft")
+		return
+	}
+
+	owner, ok := a.walletManager.GetWalletBySupabaseID(userID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	recipient, ok := a.walletManager.GetWalletBySupabaseID(request.ToUserID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "recipient wallet not found")
+		return
+	}
+
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeTransferNFT, types.NowUnix(), a.chain.NextNonce(owner.SupabaseUserID), types.TransferNFTPayload{
+		TokenID:   tokenID,
+		FromID:    userID,
+		ToID:      recipient.SupabaseUserID,
+		ToAddress: recipient.Address,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode transfer")
+		return
+	}
+
+	tx.Signature, err = a.walletManager.SignTransaction(owner, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign transfer")
+		return
+	}
+
+	if err := a.submitTransaction(tokenID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"transaction": tx,
+	})
+}
+
+func (a *API) handleTransferShares(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	tokenID := mux.Vars(r)["tokenID"]
+	if tokenID == "" {
+		writeError(w, http.StatusBadRequest, "token id is required")
+		return
+	}
+
+	var request struct {
+		ToUserID string `json:"to_user_id"`
+		Amount   int64  `json:"amount"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	if request.ToUserID == "" {
+		writeError(w, http.StatusBadRequest, "to_user_id is required")
+		return
+	}
+
+	if request.ToUserID == userID {
+		writeError(w, http.StatusBadRequest, "cannot transfer shares to yourself")
+		return
+	}
+
+	if request.Amount <= 0 {
+		writeError(w, http.StatusBadRequest, "amount must be positive")
+		return
+	}
+
+	if _, ok := a.chain.GetNFT(tokenID); !ok {
+		writeError(w, http.StatusNotFound, "nft not found")
+		return
+	}
+
+	if a.chain.ShareBalance(tokenID, userID) < request.Amount {
+		writeError(w, http.StatusConflict, "insufficient share balance")
+		return
+	}
+
+	sender, ok := a.walletManager.GetWalletBySupabaseID(userID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	recipient, ok := a.walletManager.GetWalletBySupabaseID(request.ToUserID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "recipient wallet not found")
+		return
+	}
+
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeTransferShares, types.NowUnix(), a.chain.NextNonce(sender.SupabaseUserID), types.TransferSharesPayload{
+		TokenID:   tokenID,
+		FromID:    userID,
+		ToID:      recipient.SupabaseUserID,
+		ToAddress: recipient.Address,
+		Amount:    request.Amount,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode share transfer")
+		return
+	}
+
+	tx.Signature, err = a.walletManager.SignTransaction(sender, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign share transfer")
+		return
+	}
+
+	if err := a.submitTransaction(tokenID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
 	}
 
 	writeJSON(w, http.StatusCreated, map[string]interface{}{
-		"nft":         nft,
 		"transaction": tx,
 	})
 }
 
+// errTransactionRejected marks a transaction the mempool refused to admit.
+var errTransactionRejected = errors.New("transaction rejected")
+
+// submitTransaction admits tx to the mempool and proposes it to the consensus
+// node responsible for key. When the proposal fails the transaction is
+// withdrawn again, so an error always means nothing was queued.
+func (a *API) submitTransaction(key string, tx types.Transaction) error {
+	if err := a.chain.EnqueueTransaction(tx); err != nil {
+		return fmt.Errorf("%w: %v", errTransactionRejected, err)
+	}
+
+	nodeID := a.selectConsensusNode(key)
+	if a.proposer == nil || nodeID == "" {
+		return nil
+	}
+
+	if err := a.proposer.Propose(nodeID, []types.Transaction{tx}); err != nil {
+		// A transaction no longer pending was committed or rejected by a
+		// block meanwhile; its receipt reports the outcome.
+		if a.chain.WithdrawTransaction(tx.TxID) {
+			return err
+		}
+	}
+	return nil
+}
+
 func (a *API) handleNotImplemented(w http.ResponseWriter, r *http.Request) {
 	writeError(w, http.StatusNotImplemented, "endpoint not implemented yet")
 }
@@ -521,3 +925,13 @@ func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, map[string]string{"error": message})
 }
+
+// writeSubmitError reports a failed submitTransaction: 409 with the reason
+// when the mempool rejected the transaction, 500 when it could not be proposed.
+func writeSubmitError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errTransactionRejected) {
+		writeError(w, http.StatusConflict, err.Error())
+		return
+	}
+	writeError(w, http.StatusInternalServerError, "failed to propose transaction")
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,7 +23,6 @@ import (
 	"storytelling-blockchain/internal/supabase"
 	"storytelling-blockchain/internal/types"
 	"storytelling-blockchain/internal/wallet"
-	"storytelling-blockchain/pkg/utils"
 )
 
 type tokenVerifierStub struct {
@@ -138,6 +138,11 @@ func TestHealthEndpoint(t *testing.T) {
 	if _, ok := metrics["contributions"]; !ok {
 		t.Fatalf("expected contributions metric present")
 	}
+
+	mempool, ok := body["mempool"].(map[string]interface{})
+	if !ok || mempool["size"] != float64(0) || mempool["capacity"] == float64(0) {
+		t.Fatalf("expected mempool stats in health response, got %v", body["mempool"])
+	}
 }
 
 func TestLivenessEndpoint(t *testing.T) {
@@ -218,8 +223,53 @@ func TestGetWalletEndpoints(t *testing.T) {
 	}
 }
 
+func TestGetWalletNonce(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	nextNonce := func() int64 {
+		t.Helper()
+		w := httptest.NewRecorder()
+		api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/wallet/user-123/nonce", nil))
+		var resp struct {
+			NextNonce int64 `json:"next_nonce"`
+		}
+		if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &resp) != nil {
+			t.Fatalf("unexpected nonce response: %d %s", w.Code, w.Body.String())
+		}
+		return resp.NextNonce
+	}
+
+	if got := nextNonce(); got != 1 {
+		t.Fatalf("expected next nonce 1 for a new wallet, got %d", got)
+	}
+
+	createTestStory(t, chain, manager, "story-1")
+	resp := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"Once upon a time"}`)
+	var created struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil || created.Transaction.Nonce != 2 {
+		t.Fatalf("expected the contribution to carry nonce 2, got %+v (%v)", created.Transaction, err)
+	}
+	if got := nextNonce(); got != 3 {
+		t.Fatalf("expected the pending contribution to be counted, got next nonce %d", got)
+	}
+
+	commitTestTransactions(t, chain, created.Transaction)
+	if got := nextNonce(); got != 3 {
+		t.Fatalf("expected next nonce 3, got %d", got)
+	}
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/wallet/unknown/nonce", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for missing wallet, got %d", w.Code)
+	}
+}
+
 func TestContributeStory(t *testing.T) {
-	api, chain, _, _ := setupAPI(t)
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
 
 	body, _ := json.Marshal(map[string]string{
 		"story_id":   "story-1",
@@ -267,7 +317,8 @@ func TestContributeStory(t *testing.T) {
 
 func TestContributeStoryTriggersConsensus(t *testing.T) {
 	stub := &proposerStub{}
-	api, _, _, _ := setupAPI(t)
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
 	api.WithConsensus("node-1", stub)
 
 	body, _ := json.Marshal(map[string]string{
@@ -330,9 +381,31 @@ func TestSelectConsensusNodeShards(t *testing.T) {
 	}
 }
 
+func TestContributeStoryRejectedByMempool(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+	commitTestTransactions(t, chain, signTestTransaction(t, chain, manager, blockchain.TxTypeCreateStory, 500, types.CreateStoryPayload{
+		Story: types.StoryRecord{ID: "story-1", Title: "Story", CreatorID: "user-123", Rules: types.StoryRules{RequireAlternation: true}, CreatedAt: 500},
+	}))
+
+	if w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"First"}`); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for the first line, got %d: %s", w.Code, w.Body.String())
+	}
+
+	// The committed story has no lines yet, but the pending first line makes
+	// a second line by the same author break the alternation rule.
+	w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"Second"}`)
+	if w.Code != http.StatusConflict || !strings.Contains(w.Body.String(), blockchain.ErrAlternationRequired.Error()) {
+		t.Fatalf("expected 409 from the mempool, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(chain.PendingTransactions()) != 1 {
+		t.Fatalf("expected only the first line to be pending")
+	}
+}
+
 func TestContributeStoryConsensusError(t *testing.T) {
 	stub := &proposerStub{err: errors.New("consensus failed")}
-	api, _, _, _ := setupAPI(t)
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
 	api.WithConsensus("node-1", stub)
 
 	body, _ := json.Marshal(map[string]string{
@@ -358,6 +431,10 @@ func TestContributeStoryConsensusError(t *testing.T) {
 	if resp["error"] != "failed to propose transaction" {
 		t.Fatalf("unexpected error message: %s", resp["error"])
 	}
+
+	if _, pending := chain.PendingTransaction(stub.txs[0][0].TxID); pending || len(chain.PendingTransactions()) != 0 {
+		t.Fatalf("expected the transaction to be withdrawn when the proposal fails")
+	}
 }
 
 func TestContributeStoryEndToEndConsensus(t *testing.T) {
@@ -414,6 +491,7 @@ func TestContributeStoryEndToEndConsensus(t *testing.T) {
 	}
 
 	api.WithConsensus("node-1", service)
+	createTestStory(t, chain, manager, "story-1")
 
 	subID, events := bus.Subscribe(16)
 	defer bus.Unsubscribe(subID)
@@ -459,7 +537,8 @@ func TestContributeStoryEndToEndConsensus(t *testing.T) {
 		}
 	}
 
-	if len(chain.Blocks()) != 2 {
+	// Genesis, the story block and the contribution committed through consensus.
+	if len(chain.Blocks()) != 3 {
 		t.Fatalf("expected committed block, got %d blocks", len(chain.Blocks()))
 	}
 
@@ -507,6 +586,7 @@ func TestStoryAndNFTEndpointsDefaultResponses(t *testing.T) {
 
 func TestGetStoryIncludesSummary(t *testing.T) {
 	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-42")
 
 	walletObj, ok := manager.GetWalletBySupabaseID("user-123")
 	if !ok {
@@ -521,35 +601,26 @@ func TestGetStoryIncludesSummary(t *testing.T) {
 		Timestamp:     555,
 	}
 
-	signature, err := manager.SignContribution(walletObj, contribution)
+	contribTx, err := blockchain.NewSignedTransaction(blockchain.TxTypeContribution, contribution.Timestamp, chain.NextNonce(walletObj.SupabaseUserID), types.ContributionPayload{Contribution: contribution})
 	if err != nil {
-		t.Fatalf("failed to sign contribution: %v", err)
+		t.Fatalf("failed to build contribution transaction: %v", err)
 	}
 
-	contribPayload := struct {
-		Contribution types.Contribution `json:"contribution"`
-		Timestamp    int64              `json:"timestamp"`
-	}{Contribution: contribution, Timestamp: contribution.Timestamp}
-
-	contribBytes, err := json.Marshal(contribPayload)
+	contribTx.Signature, err = manager.SignTransaction(walletObj, contribTx)
 	if err != nil {
-		t.Fatalf("failed to marshal contribution payload: %v", err)
+		t.Fatalf("failed to sign contribution: %v", err)
 	}
 
-	contribTx := types.Transaction{
-		Type:      "contribution",
-		Data:      contribPayload,
-		Timestamp: contribution.Timestamp,
-		Signature: signature,
+	block, err := chain.BuildBlock([]types.Transaction{contribTx})
+	if err != nil {
+		t.Fatalf("failed to build contribution block: %v", err)
 	}
-	contribTx.TxID = utils.ComputeSHA256(contribBytes)
-
-	prev := chain.LatestBlock()
-	block := blockchain.NewBlock(prev.Index+1, prev.Hash, []types.Transaction{contribTx})
 	if err := chain.AddBlock(block); err != nil {
 		t.Fatalf("failed to add contribution block: %v", err)
 	}
 
+	closeTestStory(t, chain, manager, "story-42")
+
 	story := types.Story{
 		ID:            "story-42",
 		Title:         "Adventure",
@@ -562,20 +633,16 @@ func TestGetStoryIncludesSummary(t *testing.T) {
 		t.Fatalf("failed to mint nft: %v", err)
 	}
 
-	mintPayload, err := json.Marshal(nft)
-	if err != nil {
-		t.Fatalf("failed to marshal nft payload: %v", err)
-	}
+	mintTx := signTestTransactionAs(t, chain, manager, nft.OwnerID, blockchain.TxTypeProposeMint, nft.MintedAt, types.ProposeMintPayload{
+		NFT:        nft,
+		ProposerID: nft.OwnerID,
+		ExpiresAt:  nft.MintedAt + 3600,
+	})
 
-	mintTx := types.Transaction{
-		Type:      "mint_nft",
-		Data:      nft,
-		Timestamp: nft.MintedAt,
+	mintBlock, err := chain.BuildBlock([]types.Transaction{mintTx})
+	if err != nil {
+		t.Fatalf("failed to build mint block: %v", err)
 	}
-	mintTx.TxID = utils.ComputeSHA256(mintPayload)
-
-	prev = chain.LatestBlock()
-	mintBlock := blockchain.NewBlock(prev.Index+1, prev.Hash, []types.Transaction{mintTx})
 	if err := chain.AddBlock(mintBlock); err != nil {
 		t.Fatalf("failed to add mint block: %v", err)
 	}
@@ -593,7 +660,7 @@ func TestGetStoryIncludesSummary(t *testing.T) {
 		Title         string               `json:"title"`
 		Summary       string               `json:"summary"`
 		Contributions []types.Contribution `json:"contributions"`
-		NFTs          []types.NFT          `json:"nfts"`
+		Editions      []types.NFT          `json:"editions"`
 	}
 
 	if err := json.Unmarshal(resp.Body.Bytes(), &body); err != nil {
@@ -608,12 +675,107 @@ func TestGetStoryIncludesSummary(t *testing.T) {
 		t.Fatalf("expected summary to match minted story, got %q", body.Summary)
 	}
 
-	if len(body.NFTs) != 1 {
-		t.Fatalf("expected single nft entry, got %d", len(body.NFTs))
+	if len(body.Editions) != 1 {
+		t.Fatalf("expected single edition, got %d", len(body.Editions))
+	}
+
+	if body.Editions[0].Summary != "A quest across chains." {
+		t.Fatalf("expected nft summary to propagate, got %q", body.Editions[0].Summary)
+	}
+}
+
+func TestTransactionProofEndpoint(t *testing.T) {
+	api, chain, _, _ := setupAPI(t)
+
+	generator, err := wallet.NewGenerator("passphrase")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+
+	txs := make([]types.Transaction, 0, 2)
+	for _, userID := range []string{"user-a", "user-b"} {
+		walletObj, err := generator.GenerateWalletForUser(userID)
+		if err != nil {
+			t.Fatalf("failed to generate wallet: %v", err)
+		}
+		txs = append(txs, newCreateWalletTx(t, walletObj, 777))
+	}
+
+	block, err := chain.BuildBlock(txs)
+	if err != nil {
+		t.Fatalf("failed to build block: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("failed to add block: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tx/"+txs[1].TxID+"/proof", nil)
+	resp := httptest.NewRecorder()
+	api.Router().ServeHTTP(resp, req)
+
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+
+	var proof blockchain.TransactionProof
+	if err := json.Unmarshal(resp.Body.Bytes(), &proof); err != nil {
+		t.Fatalf("failed to decode proof: %v", err)
+	}
+
+	if proof.BlockHash != block.Hash || !proof.Verify() {
+		t.Fatalf("expected proof to verify against block %s, got %+v", block.Hash, proof)
+	}
+
+	missing := httptest.NewRecorder()
+	api.Router().ServeHTTP(missing, httptest.NewRequest(http.MethodGet, "/api/tx/unknown/proof", nil))
+	if missing.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown transaction, got %d", missing.Code)
+	}
+}
+
+func TestTransactionReceiptEndpoint(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
+
+	w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"Narrative"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var submitted struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &submitted); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	getReceipt := func() types.Receipt {
+		t.Helper()
+		resp := httptest.NewRecorder()
+		api.Router().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/tx/"+submitted.Transaction.TxID, nil))
+		if resp.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", resp.Code, resp.Body.String())
+		}
+		var receipt types.Receipt
+		if err := json.Unmarshal(resp.Body.Bytes(), &receipt); err != nil {
+			t.Fatalf("failed to decode receipt: %v", err)
+		}
+		return receipt
+	}
+
+	if receipt := getReceipt(); receipt.Status != types.TxStatusPending || receipt.BlockIndex != -1 || receipt.Type != blockchain.TxTypeContribution {
+		t.Fatalf("expected a pending receipt, got %+v", receipt)
+	}
+
+	commitTestTransactions(t, chain, chain.PendingTransactions()...)
+	latest := chain.LatestBlock()
+	if receipt := getReceipt(); receipt.Status != types.TxStatusCommitted || receipt.BlockIndex != latest.Index || receipt.BlockHash != latest.Hash {
+		t.Fatalf("expected a receipt for block %d, got %+v", latest.Index, receipt)
 	}
 
-	if body.NFTs[0].Summary != "A quest across chains." {
-		t.Fatalf("expected nft summary to propagate, got %q", body.NFTs[0].Summary)
+	missing := httptest.NewRecorder()
+	api.Router().ServeHTTP(missing, httptest.NewRequest(http.MethodGet, "/api/tx/unknown", nil))
+	if missing.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown transaction, got %d", missing.Code)
 	}
 }
 
@@ -657,3 +819,335 @@ func TestEventsWebsocket(t *testing.T) {
 		t.Fatalf("expected transaction queued event, got %s", received.Type)
 	}
 }
+
+func newCreateWalletTx(t *testing.T, walletObj types.Wallet, timestamp int64) types.Transaction {
+	t.Helper()
+
+	tx, err := blockchain.NewTransaction(blockchain.TxTypeCreateWallet, timestamp, types.CreateWalletPayload{Wallet: walletObj})
+	if err != nil {
+		t.Fatalf("failed to build wallet transaction: %v", err)
+	}
+	return tx
+}
+
+func TestStateProofEndpoints(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	walletObj, ok := manager.GetWalletBySupabaseID("user-123")
+	if !ok {
+		t.Fatalf("expected wallet to exist")
+	}
+
+	block, err := chain.BuildBlock([]types.Transaction{newCreateWalletTx(t, walletObj, 777)})
+	if err != nil {
+		t.Fatalf("failed to build block: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("failed to add block: %v", err)
+	}
+
+	resp := httptest.NewRecorder()
+	api.Router().ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/api/wallet/user-123/proof", nil))
+	if resp.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", resp.Code)
+	}
+
+	var proof blockchain.StateProof
+	if err := json.Unmarshal(resp.Body.Bytes(), &proof); err != nil {
+		t.Fatalf("failed to decode proof: %v", err)
+	}
+
+	if proof.BlockHash != block.Hash || !proof.Verify() {
+		t.Fatalf("expected proof to verify against block %s, got %+v", block.Hash, proof)
+	}
+
+	cases := []struct {
+		Path string
+		Want int
+	}{
+		{Path: "/api/wallet/user-123/proof?height=0", Want: http.StatusNotFound},
+		{Path: "/api/wallet/user-123/proof?height=5", Want: http.StatusBadRequest},
+		{Path: "/api/wallet/user-123/proof?height=abc", Want: http.StatusBadRequest},
+		{Path: "/api/nft/unknown/proof", Want: http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.Path, nil))
+		if w.Code != tc.Want {
+			t.Fatalf("expected %d for %s, got %d", tc.Want, tc.Path, w.Code)
+		}
+	}
+}
+
+func TestTransferNFTAndHistory(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	generator, err := wallet.NewGenerator("passphrase")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	recipient, err := generator.GenerateWalletForUser("user-456")
+	if err != nil {
+		t.Fatalf("failed to generate wallet: %v", err)
+	}
+	chain.RegisterWallet(recipient)
+
+	owner, ok := manager.GetWalletBySupabaseID("user-123")
+	if !ok {
+		t.Fatalf("expected wallet to exist")
+	}
+
+	createTestStory(t, chain, manager, "story-1")
+	addTestLine(t, chain, manager, "user-123", "story-1", "Once upon a time", 510)
+	closeTestStory(t, chain, manager, "story-1")
+	commitTestTransactions(t, chain, newStoryMintTx(t, chain, manager, "story-1", "nft-1"))
+
+	transfer := func(tokenID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/nft/"+tokenID+"/transfer", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		api.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	rejected := []struct {
+		TokenID string
+		Body    string
+		Want    int
+	}{
+		{TokenID: "nft-1", Body: `{}`, Want: http.StatusBadRequest},
+		{TokenID: "nft-1", Body: `{"to_user_id":"user-123"}`, Want: http.StatusBadRequest},
+		{TokenID: "missing", Body: `{"to_user_id":"user-456"}`, Want: http.StatusNotFound},
+		{TokenID: "nft-1", Body: `{"to_user_id":"nobody"}`, Want: http.StatusNotFound},
+	}
+	for _, tc := range rejected {
+		if w := transfer(tc.TokenID, tc.Body); w.Code != tc.Want {
+			t.Fatalf("expected %d for %s %s, got %d", tc.Want, tc.TokenID, tc.Body, w.Code)
+		}
+	}
+
+	resp := transfer("nft-1", `{"to_user_id":"user-456"}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var created struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	commitTestTransactions(t, chain, created.Transaction)
+
+	if w := transfer("nft-1", `{"to_user_id":"user-456"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("expected previous owner to be forbidden, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/nft/nft-1/history", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var history struct {
+		OwnerID string                `json:"owner_id"`
+		History []types.NFTProvenance `json:"history"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &history); err != nil {
+		t.Fatalf("failed to decode history: %v", err)
+	}
+
+	if history.OwnerID != "user-456" || len(history.History) != 2 {
+		t.Fatalf("unexpected history response: %+v", history)
+	}
+	if history.History[1].FromAddress != owner.Address || history.History[1].ToAddress != recipient.Address {
+		t.Fatalf("unexpected transfer provenance: %+v", history.History[1])
+	}
+
+	w = httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/nft/missing/history", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown nft, got %d", w.Code)
+	}
+}
+
+// addTestLine commits a line written by userID to the story.
+func addTestLine(t *testing.T, chain *blockchain.Blockchain, manager *wallet.Manager, userID, storyID, line string, timestamp int64) {
+	t.Helper()
+
+	walletObj, ok := manager.GetWalletBySupabaseID(userID)
+	if !ok {
+		t.Fatalf("expected wallet of %s to exist", userID)
+	}
+	commitTestTransactions(t, chain, signTestTransactionAs(t, chain, manager, userID, blockchain.TxTypeContribution, timestamp, types.ContributionPayload{
+		Contribution: types.Contribution{ContributorID: userID, WalletAddress: walletObj.Address, StoryID: storyID, StoryLine: line, Timestamp: timestamp},
+	}))
+}
+
+// newStoryMintTx mints the next edition of the story from its committed
+// lines, as MintNFT derives it, under the given token ID. The main author
+// proposes it, which mints it at once when they hold the threshold.
+func newStoryMintTx(t *testing.T, chain *blockchain.Blockchain, manager *wallet.Manager, storyID, tokenID string) types.Transaction {
+	t.Helper()
+
+	story, err := chain.NextEditionStory(storyID, "Tale", "")
+	if err != nil {
+		t.Fatalf("failed to assemble story: %v", err)
+	}
+	nft, err := blockchain.MintNFT(story, storage.NewMemoryIPFS())
+	if err != nil {
+		t.Fatalf("failed to mint nft: %v", err)
+	}
+	nft.TokenID = tokenID
+
+	return signTestTransactionAs(t, chain, manager, nft.OwnerID, blockchain.TxTypeProposeMint, nft.MintedAt, types.ProposeMintPayload{
+		NFT:        nft,
+		ProposerID: nft.OwnerID,
+		ExpiresAt:  nft.MintedAt + 3600,
+	})
+}
+
+func commitTestTransactions(t *testing.T, chain *blockchain.Blockchain, txs ...types.Transaction) {
+	t.Helper()
+
+	block, err := chain.BuildBlock(txs)
+	if err != nil {
+		t.Fatalf("failed to build block: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("failed to add block: %v", err)
+	}
+}
+
+func TestNFTAuthorsReturnLiveShareHoldings(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	generator, err := wallet.NewGenerator("passphrase")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	coAuthor, err := generator.GenerateWalletForUser("user-456")
+	if err != nil {
+		t.Fatalf("failed to generate wallet: %v", err)
+	}
+	chain.RegisterWallet(coAuthor)
+
+	createTestStory(t, chain, manager, "story-1")
+	for i, userID := range []string{"user-123", "user-456", "user-123", "user-123"} {
+		addTestLine(t, chain, manager, userID, "story-1", "A line", int64(510+i))
+	}
+	closeTestStory(t, chain, manager, "story-1")
+	commitTestTransactions(t, chain, newStoryMintTx(t, chain, manager, "story-1", "nft-1"))
+
+	transfer := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/nft/nft-1/shares/transfer", bytes.NewBufferString(body))
+		req.Header.Set("Authorization", "Bearer valid-token")
+		w := httptest.NewRecorder()
+		api.Router().ServeHTTP(w, req)
+		return w
+	}
+
+	rejected := []struct {
+		Body string
+		Want int
+	}{
+		{Body: `{"amount":10}`, Want: http.StatusBadRequest},
+		{Body: `{"to_user_id":"user-456","amount":0}`, Want: http.StatusBadRequest},
+		{Body: `{"to_user_id":"user-456","amount":7501}`, Want: http.StatusConflict},
+		{Body: `{"to_user_id":"nobody","amount":10}`, Want: http.StatusNotFound},
+	}
+	for _, tc := range rejected {
+		if w := transfer(tc.Body); w.Code != tc.Want {
+			t.Fatalf("expected %d for %s, got %d", tc.Want, tc.Body, w.Code)
+		}
+	}
+
+	resp := transfer(`{"to_user_id":"user-456","amount":500}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var created struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, created.Transaction)
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/nft/nft-1/authors", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var body struct {
+		TotalShares int64                `json:"total_shares"`
+		Authors     []types.ShareHolding `json:"authors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode authors: %v", err)
+	}
+
+	if body.TotalShares != blockchain.TotalShareUnits || len(body.Authors) != 2 {
+		t.Fatalf("unexpected authors response: %+v", body)
+	}
+	if body.Authors[0].SupabaseUserID != "user-123" || body.Authors[0].Shares != 7000 || body.Authors[0].OwnershipPercentage != 70 {
+		t.Fatalf("unexpected main author holding: %+v", body.Authors[0])
+	}
+	if body.Authors[1].SupabaseUserID != "user-456" || body.Authors[1].Shares != 3000 {
+		t.Fatalf("unexpected co-author holding: %+v", body.Authors[1])
+	}
+}
+
+func TestGetStoryAuthorshipPolicy(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-7")
+
+	walletObj, ok := manager.GetWalletBySupabaseID("user-123")
+	if !ok {
+		t.Fatalf("expected wallet to exist")
+	}
+
+	contribution := types.Contribution{
+		ContributorID: "user-123",
+		WalletAddress: walletObj.Address,
+		StoryID:       "story-7",
+		StoryLine:     "Four words right here",
+		Timestamp:     555,
+	}
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeContribution, contribution.Timestamp, chain.NextNonce(walletObj.SupabaseUserID), types.ContributionPayload{Contribution: contribution})
+	if err != nil {
+		t.Fatalf("failed to build contribution transaction: %v", err)
+	}
+	if tx.Signature, err = manager.SignTransaction(walletObj, tx); err != nil {
+		t.Fatalf("failed to sign contribution: %v", err)
+	}
+	commitTestTransactions(t, chain, tx)
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/story-7?policy=words", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var body struct {
+		AuthorshipPolicy string         `json:"authorship_policy"`
+		Authors          []types.Author `json:"authors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if body.AuthorshipPolicy != blockchain.AuthorshipWords || len(body.Authors) != 1 || body.Authors[0].Weight != 4 {
+		t.Fatalf("expected words policy weights, got %+v", body)
+	}
+
+	w = httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/story-7?policy=loudest", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown policy, got %d", w.Code)
+	}
+}
@@ -0,0 +1,482 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/supabase"
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+func (a *API) handleCreateStory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	var request struct {
+		StoryID string           `json:"story_id"`
+		Title   string           `json:"title"`
+		Rules   types.StoryRules `json:"rules"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		writeError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+
+	createdAt := types.NowUnix()
+	if err := blockchain.ValidateStoryRules(request.Rules, createdAt); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	wallet, ok := a.walletManager.GetWalletBySupabaseID(userID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	storyID := strings.TrimSpace(request.StoryID)
+	if storyID == "" {
+		storyID = "story_" + utils.ComputeSHA256([]byte(fmt.Sprintf("%s|%s|%d", userID, title, createdAt)))[:12]
+	}
+
+	if _, exists := a.chain.GetStory(storyID); exists {
+		writeError(w, http.StatusConflict, "story already exists")
+		return
+	}
+
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeCreateStory, createdAt, a.chain.NextNonce(wallet.SupabaseUserID), types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        storyID,
+		Title:     title,
+		CreatorID: userID,
+		Rules:     request.Rules,
+		CreatedAt: createdAt,
+	}})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode story")
+		return
+	}
+
+	tx.Signature, err = a.walletManager.SignTransaction(wallet, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign story")
+		return
+	}
+
+	if err := a.submitTransaction(storyID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"story_id":    storyID,
+		"transaction": tx,
+	})
+}
+
+func (a *API) handleForkStory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	var request struct {
+		StoryID          string           `json:"story_id"`
+		Title            string           `json:"title"`
+		Rules            types.StoryRules `json:"rules"`
+		BranchTxID       string           `json:"branch_tx_id"`
+		BranchLine       int              `json:"branch_line"`
+		InheritanceRatio *int64           `json:"inheritance_ratio"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	title := strings.TrimSpace(request.Title)
+	if title == "" {
+		writeError(w, http.StatusBadRequest, "title is required")
+		return
+	}
+
+	parentID := mux.Vars(r)["storyID"]
+	if _, ok := a.chain.GetStory(parentID); !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	}
+
+	// branch_line is a shorthand for the line at that position of the
+	// parent's current main line; the fork records the line itself.
+	branchTxID := strings.TrimSpace(request.BranchTxID)
+	if branchTxID == "" {
+		lines := a.chain.StoryContributions(parentID)
+		if request.BranchLine < 1 || request.BranchLine > len(lines) {
+			writeError(w, http.StatusBadRequest, blockchain.ErrInvalidBranchPoint.Error())
+			return
+		}
+		branchTxID = lines[request.BranchLine-1].TxID
+	}
+
+	ratio := blockchain.DefaultInheritanceRatio
+	if request.InheritanceRatio != nil {
+		ratio = *request.InheritanceRatio
+	}
+	if ratio < 0 || ratio >= blockchain.TotalShareUnits {
+		writeError(w, http.StatusBadRequest, blockchain.ErrInvalidInheritanceRatio.Error())
+		return
+	}
+
+	createdAt := types.NowUnix()
+	if err := blockchain.ValidateStoryRules(request.Rules, createdAt); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	wallet, ok := a.walletManager.GetWalletBySupabaseID(userID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	storyID := strings.TrimSpace(request.StoryID)
+	if storyID == "" {
+		storyID = "story_" + utils.ComputeSHA256([]byte(fmt.Sprintf("%s|%s|%s|%d", userID, parentID, title, createdAt)))[:12]
+	}
+
+	if _, exists := a.chain.GetStory(storyID); exists {
+		writeError(w, http.StatusConflict, "story already exists")
+		return
+	}
+
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeForkStory, createdAt, a.chain.NextNonce(wallet.SupabaseUserID), types.ForkStoryPayload{Story: types.StoryRecord{
+		ID:        storyID,
+		Title:     title,
+		CreatorID: userID,
+		Rules:     request.Rules,
+		CreatedAt: createdAt,
+		Lineage: &types.StoryLineage{
+			ParentID:         parentID,
+			BranchTxID:       branchTxID,
+			InheritanceRatio: ratio,
+		},
+	}})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode story")
+		return
+	}
+
+	tx.Signature, err = a.walletManager.SignTransaction(wallet, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign story")
+		return
+	}
+
+	if err := a.submitTransaction(storyID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"story_id":    storyID,
+		"transaction": tx,
+	})
+}
+
+func (a *API) handleCloseStory(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	storyID := mux.Vars(r)["storyID"]
+	if storyID == "" {
+		writeError(w, http.StatusBadRequest, "story id is required")
+		return
+	}
+
+	story, ok := a.chain.GetStory(storyID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	}
+
+	if story.CreatorID != userID {
+		writeError(w, http.StatusForbidden, "only the story creator can close the story")
+		return
+	}
+
+	if story.Status != types.StoryStatusOpen {
+		writeError(w, http.StatusConflict, "story already closed")
+		return
+	}
+
+	wallet, ok := a.walletManager.GetWalletBySupabaseID(userID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeCloseStory, types.NowUnix(), a.chain.NextNonce(wallet.SupabaseUserID), types.CloseStoryPayload{
+		StoryID:  storyID,
+		ClosedBy: userID,
+	})
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode story")
+		return
+	}
+
+	tx.Signature, err = a.walletManager.SignTransaction(wallet, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign story")
+		return
+	}
+
+	if err := a.submitTransaction(storyID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"transaction": tx,
+	})
+}
+
+func (a *API) handleInviteContributor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	var request struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.UserID == "" {
+		writeError(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+
+	story, ok := a.ownedOpenStory(w, mux.Vars(r)["storyID"], userID)
+	if !ok {
+		return
+	}
+
+	if request.UserID == story.CreatorID || a.chain.StoryMemberStatus(story.ID, request.UserID) != "" {
+		writeError(w, http.StatusConflict, "user already invited")
+		return
+	}
+
+	if _, ok := a.walletManager.GetWalletBySupabaseID(request.UserID); !ok {
+		writeError(w, http.StatusNotFound, "invitee wallet not found")
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, story.ID, blockchain.TxTypeInviteContributor, types.InviteContributorPayload{
+		StoryID:   story.ID,
+		InviteeID: request.UserID,
+		InvitedBy: userID,
+	})
+}
+
+func (a *API) handleAcceptInvite(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	story, ok := a.chain.GetStory(mux.Vars(r)["storyID"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	}
+
+	if story.Status != types.StoryStatusOpen {
+		writeError(w, http.StatusConflict, "story is closed")
+		return
+	}
+
+	if a.chain.StoryMemberStatus(story.ID, userID) != types.MemberStatusInvited {
+		writeError(w, http.StatusConflict, "no pending invite")
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, story.ID, blockchain.TxTypeAcceptInvite, types.AcceptInvitePayload{
+		StoryID: story.ID,
+		UserID:  userID,
+	})
+}
+
+func (a *API) handleRevokeContributor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := supabase.UserIDFromContext(r.Context())
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "missing user context")
+		return
+	}
+
+	var request struct {
+		UserID string `json:"user_id"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil || request.UserID == "" {
+		writeError(w, http.StatusBadRequest, "user_id is required")
+		return
+	}
+
+	story, ok := a.ownedOpenStory(w, mux.Vars(r)["storyID"], userID)
+	if !ok {
+		return
+	}
+
+	if a.chain.StoryMemberStatus(story.ID, request.UserID) == "" {
+		writeError(w, http.StatusNotFound, "contributor not found")
+		return
+	}
+
+	a.proposeStoryTransaction(w, userID, story.ID, blockchain.TxTypeRevokeContributor, types.RevokeContributorPayload{
+		StoryID:       story.ID,
+		ContributorID: request.UserID,
+		RevokedBy:     userID,
+	})
+}
+
+func (a *API) handleGetStoryMembers(w http.ResponseWriter, r *http.Request) {
+	story, ok := a.chain.GetStory(mux.Vars(r)["storyID"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	}
+
+	members := a.chain.StoryMembers(story.ID)
+	if members == nil {
+		members = []blockchain.StoryMember{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"story_id":    story.ID,
+		"creator_id":  story.CreatorID,
+		"invite_only": story.Rules.InviteOnly,
+		"members":     members,
+	})
+}
+
+// ownedOpenStory loads an open story managed by userID, writing the error
+// response and returning false otherwise.
+func (a *API) ownedOpenStory(w http.ResponseWriter, storyID, userID string) (types.StoryRecord, bool) {
+	story, ok := a.chain.GetStory(storyID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return types.StoryRecord{}, false
+	}
+
+	if story.CreatorID != userID {
+		writeError(w, http.StatusForbidden, "only the story creator can manage contributors")
+		return types.StoryRecord{}, false
+	}
+
+	if story.Status != types.StoryStatusOpen {
+		writeError(w, http.StatusConflict, "story is closed")
+		return types.StoryRecord{}, false
+	}
+
+	return story, true
+}
+
+// proposeStoryTransaction signs a story transaction with the user's wallet
+// and submits it to consensus.
+func (a *API) proposeStoryTransaction(w http.ResponseWriter, userID, storyID, txType string, payload interface{}) {
+	wallet, ok := a.walletManager.GetWalletBySupabaseID(userID)
+	if !ok {
+		writeError(w, http.StatusNotFound, "wallet not found")
+		return
+	}
+
+	tx, err := blockchain.NewSignedTransaction(txType, types.NowUnix(), a.chain.NextNonce(wallet.SupabaseUserID), payload)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode transaction")
+		return
+	}
+
+	tx.Signature, err = a.walletManager.SignTransaction(wallet, tx)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to sign transaction")
+		return
+	}
+
+	if err := a.submitTransaction(storyID, tx); err != nil {
+		writeSubmitError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, map[string]interface{}{
+		"transaction": tx,
+	})
+}
+
+func (a *API) handleGetStoryRules(w http.ResponseWriter, r *http.Request) {
+	story, ok := a.chain.GetStory(mux.Vars(r)["storyID"])
+	if !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"story_id": story.ID,
+		"status":   story.Status,
+		"rules":    story.Rules,
+		"progress": story.Progress,
+	})
+}
+
+func (a *API) handleGetStoryConflicts(w http.ResponseWriter, r *http.Request) {
+	storyID := mux.Vars(r)["storyID"]
+	if _, ok := a.chain.GetStory(storyID); !ok {
+		writeError(w, http.StatusNotFound, "story not found")
+		return
+	}
+
+	conflicts := a.chain.StoryConflicts(storyID)
+	if conflicts == nil {
+		conflicts = []blockchain.StoryConflict{}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"story_id":  storyID,
+		"conflicts": conflicts,
+	})
+}
+
+// storyRuleStatus maps a rejected contribution to an HTTP status: malformed
+// lines are the caller's fault, the rest depend on the story's state.
+func storyRuleStatus(err error) int {
+	switch {
+	case errors.Is(err, blockchain.ErrUnknownStory):
+		return http.StatusNotFound
+	case errors.Is(err, blockchain.ErrNotStoryContributor):
+		return http.StatusForbidden
+	case errors.Is(err, blockchain.ErrLineTooShort), errors.Is(err, blockchain.ErrLineTooLong), errors.Is(err, blockchain.ErrUnknownParentContribution):
+		return http.StatusBadRequest
+	default:
+		return http.StatusConflict
+	}
+}
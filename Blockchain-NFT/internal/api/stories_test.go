@@ -0,0 +1,455 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/internal/wallet"
+)
+
+func signTestTransaction(t *testing.T, chain *blockchain.Blockchain, manager *wallet.Manager, txType string, timestamp int64, payload interface{}) types.Transaction {
+	t.Helper()
+
+	return signTestTransactionAs(t, chain, manager, "user-123", txType, timestamp, payload)
+}
+
+// signTestTransactionAs signs a transaction with the user's next committed
+// nonce.
+func signTestTransactionAs(t *testing.T, chain *blockchain.Blockchain, manager *wallet.Manager, userID, txType string, timestamp int64, payload interface{}) types.Transaction {
+	t.Helper()
+
+	walletObj, ok := manager.GetWalletBySupabaseID(userID)
+	if !ok {
+		t.Fatalf("expected wallet to exist")
+	}
+
+	tx, err := blockchain.NewSignedTransaction(txType, timestamp, chain.NextNonce(userID), payload)
+	if err != nil {
+		t.Fatalf("failed to build %s transaction: %v", txType, err)
+	}
+	if tx.Signature, err = manager.SignTransaction(walletObj, tx); err != nil {
+		t.Fatalf("failed to sign %s transaction: %v", txType, err)
+	}
+	return tx
+}
+
+// createTestStory commits an open story created by user-123.
+func createTestStory(t *testing.T, chain *blockchain.Blockchain, manager *wallet.Manager, storyID string) {
+	t.Helper()
+
+	commitTestTransactions(t, chain, signTestTransaction(t, chain, manager, blockchain.TxTypeCreateStory, 500, types.CreateStoryPayload{
+		Story: types.StoryRecord{ID: storyID, Title: "Story " + storyID, CreatorID: "user-123", CreatedAt: 500},
+	}))
+}
+
+// closeTestStory commits the closing of a story created by user-123.
+func closeTestStory(t *testing.T, chain *blockchain.Blockchain, manager *wallet.Manager, storyID string) {
+	t.Helper()
+
+	commitTestTransactions(t, chain, signTestTransaction(t, chain, manager, blockchain.TxTypeCloseStory, 600, types.CloseStoryPayload{
+		StoryID:  storyID,
+		ClosedBy: "user-123",
+	}))
+}
+
+func postAuthenticated(api *API, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer valid-token")
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, req)
+	return w
+}
+
+func TestStoryLifecycleEndpoints(t *testing.T) {
+	api, chain, _, _ := setupAPI(t)
+
+	if w := postAuthenticated(api, "/api/story", `{"story_id":"story-1"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without title, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story", `{"title":"T","rules":{"authorship_policy":"loudest"}}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown policy, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"Hi"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for contribution to unknown story, got %d", w.Code)
+	}
+
+	resp := postAuthenticated(api, "/api/story", `{"story_id":"story-1","title":"Ledger Tales","rules":{"authorship_policy":"words"}}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+
+	var created struct {
+		StoryID     string            `json:"story_id"`
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, created.Transaction)
+
+	if w := postAuthenticated(api, "/api/story", `{"story_id":"story-1","title":"Again"}`); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for duplicate story, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/story-1/mint", `{"title":"T","summary":"S"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when minting a story without lines, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/story-1", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+
+	var story struct {
+		Title            string           `json:"title"`
+		Status           string           `json:"status"`
+		CreatorID        string           `json:"creator_id"`
+		Rules            types.StoryRules `json:"rules"`
+		AuthorshipPolicy string           `json:"authorship_policy"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &story); err != nil {
+		t.Fatalf("failed to decode story: %v", err)
+	}
+	if story.Title != "Ledger Tales" || story.Status != types.StoryStatusOpen || story.CreatorID != "user-123" || story.AuthorshipPolicy != blockchain.AuthorshipWords {
+		t.Fatalf("unexpected story response: %+v", story)
+	}
+
+	resp = postAuthenticated(api, "/api/story/story-1/close", "")
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201 closing story, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, created.Transaction)
+
+	if w := postAuthenticated(api, "/api/story/story-1/close", ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 closing twice, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"Too late"}`); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 for contribution to closed story, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/missing/close", ""); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 closing unknown story, got %d", w.Code)
+	}
+}
+
+func TestStoryMembershipEndpoints(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	generator, err := wallet.NewGenerator("passphrase")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	guest, err := generator.GenerateWalletForUser("user-456")
+	if err != nil {
+		t.Fatalf("failed to generate wallet: %v", err)
+	}
+	chain.RegisterWallet(guest)
+
+	commitTestTransactions(t, chain, signTestTransactionAs(t, chain, manager, "user-456", blockchain.TxTypeCreateStory, 500, types.CreateStoryPayload{
+		Story: types.StoryRecord{ID: "guest-story", Title: "Private", CreatorID: "user-456", Rules: types.StoryRules{InviteOnly: true}, CreatedAt: 500},
+	}))
+
+	if w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"guest-story","story_line":"Let me in"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 contributing to invite-only story, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/guest-story/invite", `{"user_id":"user-123"}`); w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 inviting to another user's story, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/guest-story/accept", ""); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 accepting without invite, got %d", w.Code)
+	}
+
+	commitTestTransactions(t, chain, signTestTransactionAs(t, chain, manager, "user-456", blockchain.TxTypeInviteContributor, 510, types.InviteContributorPayload{
+		StoryID:   "guest-story",
+		InviteeID: "user-123",
+		InvitedBy: "user-456",
+	}))
+
+	resp := postAuthenticated(api, "/api/story/guest-story/accept", "")
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201 accepting invite, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var accepted struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &accepted); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, accepted.Transaction)
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/guest-story/members", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var members struct {
+		InviteOnly bool                     `json:"invite_only"`
+		Members    []blockchain.StoryMember `json:"members"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &members); err != nil {
+		t.Fatalf("failed to decode members: %v", err)
+	}
+	if !members.InviteOnly || len(members.Members) != 1 || members.Members[0].UserID != "user-123" || members.Members[0].Status != types.MemberStatusActive {
+		t.Fatalf("unexpected members response: %+v", members)
+	}
+
+	resp = postAuthenticated(api, "/api/story/contribute", `{"story_id":"guest-story","story_line":"Thanks for having me"}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201 contributing as member, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var contributed struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &contributed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, contributed.Transaction)
+
+	createTestStory(t, chain, manager, "own-story")
+	if w := postAuthenticated(api, "/api/story/own-story/invite", `{"user_id":"nobody"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 inviting user without wallet, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/own-story/revoke", `{"user_id":"user-456"}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 revoking non-member, got %d", w.Code)
+	}
+
+	resp = postAuthenticated(api, "/api/story/own-story/invite", `{"user_id":"user-456"}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201 inviting, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &accepted); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, accepted.Transaction)
+
+	if w := postAuthenticated(api, "/api/story/own-story/invite", `{"user_id":"user-456"}`); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 inviting twice, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/own-story/revoke", `{"user_id":"user-456"}`); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 revoking invite, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestStoryRulesEndpoint(t *testing.T) {
+	api, chain, _, _ := setupAPI(t)
+
+	if w := postAuthenticated(api, "/api/story", `{"story_id":"story-1","title":"T","rules":{"min_line_length":10,"max_line_length":5}}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for inconsistent rules, got %d", w.Code)
+	}
+
+	resp := postAuthenticated(api, "/api/story", `{"story_id":"story-1","title":"Haiku","rules":{"max_line_length":20,"require_alternation":true}}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, created.Transaction)
+
+	if w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"an old silent pond, a frog jumps"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for line over the limit, got %d", w.Code)
+	}
+
+	resp = postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"an old silent pond"}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, created.Transaction)
+
+	if w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"a frog jumps in"}`); w.Code != http.StatusConflict {
+		t.Fatalf("expected 409 when alternation is required, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/story-1/rules", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var body struct {
+		Rules    types.StoryRules    `json:"rules"`
+		Progress types.StoryProgress `json:"progress"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode rules: %v", err)
+	}
+	if body.Rules.MaxLineLength != 20 || !body.Rules.RequireAlternation || body.Progress.LineCount != 1 || body.Progress.LastContributorID != "user-123" {
+		t.Fatalf("unexpected rules response: %+v", body)
+	}
+}
+
+func TestForkStoryEndpoint(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+
+	generator, err := wallet.NewGenerator("passphrase")
+	if err != nil {
+		t.Fatalf("failed to create generator: %v", err)
+	}
+	original, err := generator.GenerateWalletForUser("user-456")
+	if err != nil {
+		t.Fatalf("failed to generate wallet: %v", err)
+	}
+	chain.RegisterWallet(original)
+
+	commitTestTransactions(t, chain, signTestTransactionAs(t, chain, manager, "user-456", blockchain.TxTypeCreateStory, 500, types.CreateStoryPayload{
+		Story: types.StoryRecord{ID: "root", Title: "Root", CreatorID: "user-456", CreatedAt: 500},
+	}))
+	line := signTestTransactionAs(t, chain, manager, "user-456", blockchain.TxTypeContribution, 510, types.ContributionPayload{
+		Contribution: types.Contribution{ContributorID: "user-456", WalletAddress: original.Address, StoryID: "root", StoryLine: "Once upon a time", Timestamp: 510},
+	})
+	commitTestTransactions(t, chain, line)
+
+	if w := postAuthenticated(api, "/api/story/root/fork", `{"title":"Remix","branch_line":2}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for branch past the end, got %d", w.Code)
+	}
+	if w := postAuthenticated(api, "/api/story/missing/fork", `{"title":"Remix","branch_line":1}`); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown parent, got %d", w.Code)
+	}
+
+	resp := postAuthenticated(api, "/api/story/root/fork", `{"story_id":"remix","title":"Remix","branch_line":1}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var created struct {
+		Transaction types.Transaction `json:"transaction"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, created.Transaction)
+	if record, _ := chain.GetStory("remix"); record.Lineage == nil || record.Lineage.BranchTxID != line.TxID {
+		t.Fatalf("expected branch_line to record the line's tx id, got %+v", record.Lineage)
+	}
+
+	resp = postAuthenticated(api, "/api/story/contribute", `{"story_id":"remix","story_line":"there was a fork"}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", resp.Code, resp.Body.String())
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	commitTestTransactions(t, chain, created.Transaction)
+	closeTestStory(t, chain, manager, "remix")
+
+	resp = postAuthenticated(api, "/api/story/remix/mint", `{"title":"Remix","summary":"A fork"}`)
+	if resp.Code != http.StatusCreated {
+		t.Fatalf("expected 201 minting fork, got %d: %s", resp.Code, resp.Body.String())
+	}
+	var minted struct {
+		NFT types.NFT `json:"nft"`
+	}
+	if err := json.Unmarshal(resp.Body.Bytes(), &minted); err != nil {
+		t.Fatalf("failed to decode mint: %v", err)
+	}
+
+	nft := minted.NFT
+	if nft.MainAuthor.SupabaseUserID != "user-123" || nft.MainAuthor.Weight != blockchain.TotalShareUnits-blockchain.DefaultInheritanceRatio {
+		t.Fatalf("unexpected main author: %+v", nft.MainAuthor)
+	}
+	if len(nft.CoAuthors) != 1 || nft.CoAuthors[0].SupabaseUserID != "user-456" || nft.CoAuthors[0].Weight != blockchain.DefaultInheritanceRatio {
+		t.Fatalf("expected original author to be credited, got %+v", nft.CoAuthors)
+	}
+}
+
+func TestStoryConflictsEndpoint(t *testing.T) {
+	api, chain, manager, _ := setupAPI(t)
+	createTestStory(t, chain, manager, "story-1")
+
+	walletObj, _ := manager.GetWalletBySupabaseID("user-123")
+
+	contribute := func(parentTxID, line string, timestamp int64) types.Transaction {
+		tx := signTestTransaction(t, chain, manager, blockchain.TxTypeContribution, timestamp, types.ContributionPayload{
+			Contribution: types.Contribution{ParentTxID: parentTxID, ContributorID: "user-123", WalletAddress: walletObj.Address, StoryID: "story-1", StoryLine: line, Timestamp: timestamp},
+		})
+		commitTestTransactions(t, chain, tx)
+		return tx
+	}
+
+	first := contribute("", "Once upon a time", 510)
+	kept := contribute(first.TxID, "a dragon woke", 530)
+	contribute(first.TxID, "a chain was forged", 520)
+
+	if w := postAuthenticated(api, "/api/story/contribute", `{"story_id":"story-1","story_line":"Dangling","parent_tx_id":"missing"}`); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown parent, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/story-1/conflicts", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	var body struct {
+		Conflicts []blockchain.StoryConflict `json:"conflicts"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode conflicts: %v", err)
+	}
+	if len(body.Conflicts) != 1 || body.Conflicts[0].ParentTxID != first.TxID || body.Conflicts[0].MainTxID != kept.TxID || len(body.Conflicts[0].Branches) != 2 {
+		t.Fatalf("unexpected conflicts response: %+v", body)
+	}
+
+	w = httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/missing/conflicts", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown story, got %d", w.Code)
+	}
+}
+
+func TestMintStoryEditions(t *testing.T) {
+	api, chain, _, _ := setupAPI(t)
+
+	var created struct {
+		Transaction types.Transaction `json:"transaction"`
+		NFT         types.NFT         `json:"nft"`
+	}
+	post := func(path, body string) types.NFT {
+		t.Helper()
+
+		resp := postAuthenticated(api, path, body)
+		if resp.Code != http.StatusCreated {
+			t.Fatalf("expected 201 from %s, got %d: %s", path, resp.Code, resp.Body.String())
+		}
+		created.NFT = types.NFT{}
+		if err := json.Unmarshal(resp.Body.Bytes(), &created); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		commitTestTransactions(t, chain, created.Transaction)
+		return created.NFT
+	}
+
+	post("/api/story", `{"story_id":"story-1","title":"Serial","rules":{"mint_while_open":true}}`)
+	post("/api/story/contribute", `{"story_id":"story-1","story_line":"Chapter one"}`)
+	first := post("/api/story/story-1/mint", `{"title":"Serial","summary":"Part one"}`)
+	post("/api/story/contribute", `{"story_id":"story-1","story_line":"Chapter two"}`)
+	second := post("/api/story/story-1/mint", `{"title":"Serial","summary":"Parts one and two"}`)
+
+	if first.Edition != 1 || first.Supersedes != "" || second.Edition != 2 || second.Supersedes != first.TokenID {
+		t.Fatalf("unexpected editions: %+v / %+v", first, second)
+	}
+
+	w := httptest.NewRecorder()
+	api.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/story/story-1", nil))
+	var story struct {
+		Summary  string      `json:"summary"`
+		Edition  int         `json:"edition"`
+		Editions []types.NFT `json:"editions"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &story); err != nil {
+		t.Fatalf("failed to decode story: %v", err)
+	}
+	if story.Edition != 2 || len(story.Editions) != 2 || story.Editions[0].TokenID != first.TokenID || story.Summary != "Parts one and two" {
+		t.Fatalf("unexpected edition timeline: %+v", story)
+	}
+}
@@ -51,6 +51,34 @@ type consensusSignerStub struct{}
 func (consensusSignerStub) Sign(_ []byte) (string, error)      { return "sig", nil }
 func (consensusSignerStub) Verify(string, []byte, string) bool { return true }
 
+// commitStory creates an open story for user-123 directly on the chain.
+func commitStory(t *testing.T, chain *blockchain.Blockchain, manager *wallet.Manager, storyID string) {
+	t.Helper()
+
+	creator, ok := manager.GetWalletBySupabaseID("user-123")
+	if !ok {
+		t.Fatalf("expected wallet to exist")
+	}
+
+	tx, err := blockchain.NewSignedTransaction(blockchain.TxTypeCreateStory, 500, chain.NextNonce(creator.SupabaseUserID), types.CreateStoryPayload{
+		Story: types.StoryRecord{ID: storyID, Title: "Story", CreatorID: "user-123", CreatedAt: 500},
+	})
+	if err != nil {
+		t.Fatalf("build story failed: %v", err)
+	}
+	if tx.Signature, err = manager.SignTransaction(creator, tx); err != nil {
+		t.Fatalf("sign story failed: %v", err)
+	}
+
+	block, err := chain.BuildBlock([]types.Transaction{tx})
+	if err != nil {
+		t.Fatalf("build block failed: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("add block failed: %v", err)
+	}
+}
+
 func TestAttachConsensusWiresAPIAndStorage(t *testing.T) {
 	chain := blockchain.NewBlockchain()
 	bus := observer.NewBus()
@@ -112,6 +140,8 @@ func TestAttachConsensusWiresAPIAndStorage(t *testing.T) {
 		t.Fatalf("expected proposer to be invoked for storage path")
 	}
 
+	commitStory(t, chain, manager, "story-1")
+
 	body, _ := json.Marshal(map[string]string{
 		"story_id":   "story-1",
 		"story_line": "Attached consensus",
@@ -220,6 +250,8 @@ func TestBootstrapConsensus(t *testing.T) {
 
 	waitForEvents(t, events, []observer.EventType{observer.EventBlockCommitted, observer.EventTransactionCommitted})
 
+	commitStory(t, chain, manager, "story-1")
+
 	body, _ := json.Marshal(map[string]string{
 		"story_id":   "story-1",
 		"story_line": "Consistent consensus",
@@ -0,0 +1,171 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"storytelling-blockchain/internal/types"
+)
+
+// Built-in authorship policies.
+const (
+	AuthorshipCount      = "count"
+	AuthorshipWords      = "words"
+	AuthorshipCharacters = "characters"
+	AuthorshipTimeDecay  = "time_decay"
+)
+
+const (
+	// TimeDecayHalfLife is the age, in seconds, after which a contribution
+	// counts for half as much under the time_decay policy.
+	TimeDecayHalfLife int64 = 7 * 24 * 60 * 60
+
+	timeDecayScale int64 = 1 << 20
+)
+
+var errUnknownAuthorshipPolicy = errors.New("blockchain: unknown authorship policy")
+
+// ErrUnknownAuthorshipPolicy is returned for policy names without a built-in strategy.
+var ErrUnknownAuthorshipPolicy = errUnknownAuthorshipPolicy
+
+// AuthorshipPolicy decides how much a single contribution counts towards
+// authorship. Weights are integers so every node derives the same split;
+// asOf is the reference time, the mint time for minted NFTs.
+type AuthorshipPolicy interface {
+	Name() string
+	Weight(contribution types.Contribution, asOf int64) int64
+}
+
+// countPolicy weighs every contribution equally.
+type countPolicy struct{}
+
+func (countPolicy) Name() string { return AuthorshipCount }
+
+func (countPolicy) Weight(types.Contribution, int64) int64 { return 1 }
+
+// wordsPolicy weighs a contribution by its number of words.
+type wordsPolicy struct{}
+
+func (wordsPolicy) Name() string { return AuthorshipWords }
+
+func (wordsPolicy) Weight(contribution types.Contribution, _ int64) int64 {
+	return atLeastOne(int64(len(strings.Fields(contribution.StoryLine))))
+}
+
+// charactersPolicy weighs a contribution by its number of characters.
+type charactersPolicy struct{}
+
+func (charactersPolicy) Name() string { return AuthorshipCharacters }
+
+func (charactersPolicy) Weight(contribution types.Contribution, _ int64) int64 {
+	return atLeastOne(int64(utf8.RuneCountInString(strings.TrimSpace(contribution.StoryLine))))
+}
+
+// timeDecayPolicy halves the weight of a contribution for every full
+// TimeDecayHalfLife between its timestamp and the reference time.
+type timeDecayPolicy struct{}
+
+func (timeDecayPolicy) Name() string { return AuthorshipTimeDecay }
+
+func (timeDecayPolicy) Weight(contribution types.Contribution, asOf int64) int64 {
+	age := asOf - contribution.Timestamp
+	if age <= 0 {
+		return timeDecayScale
+	}
+
+	periods := age / TimeDecayHalfLife
+	if periods >= 63 {
+		return 1
+	}
+	return atLeastOne(timeDecayScale >> uint(periods))
+}
+
+func atLeastOne(weight int64) int64 {
+	if weight < 1 {
+		return 1
+	}
+	return weight
+}
+
+var authorshipPolicies = map[string]AuthorshipPolicy{
+	AuthorshipCount:      countPolicy{},
+	AuthorshipWords:      wordsPolicy{},
+	AuthorshipCharacters: charactersPolicy{},
+	AuthorshipTimeDecay:  timeDecayPolicy{},
+}
+
+// AuthorshipPolicyByName resolves a built-in policy. An empty name selects
+// the count policy so that stories created before policies existed keep
+// their original split.
+func AuthorshipPolicyByName(name string) (AuthorshipPolicy, error) {
+	if name == "" {
+		return countPolicy{}, nil
+	}
+
+	policy, ok := authorshipPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", errUnknownAuthorshipPolicy, name)
+	}
+	return policy, nil
+}
+
+// AuthorshipPolicies lists the built-in policy names in sorted order.
+func AuthorshipPolicies() []string {
+	names := make([]string, 0, len(authorshipPolicies))
+	for name := range authorshipPolicies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AggregateAuthorsWithPolicy groups contributions by contributor and weighs
+// them with the policy as of the given time. Authors are ordered by weight,
+// then by Supabase user ID, so the first entry is the main author.
+func AggregateAuthorsWithPolicy(contributions []types.Contribution, policy AuthorshipPolicy, asOf int64) []types.Author {
+	if len(contributions) == 0 {
+		return nil
+	}
+
+	if policy == nil {
+		policy = countPolicy{}
+	}
+
+	var total int64
+	byContributor := make(map[string]*types.Author)
+
+	for _, c := range contributions {
+		author, ok := byContributor[c.ContributorID]
+		if !ok {
+			author = &types.Author{
+				SupabaseUserID: c.ContributorID,
+				WalletAddress:  c.WalletAddress,
+			}
+			byContributor[c.ContributorID] = author
+		}
+
+		weight := policy.Weight(c, asOf)
+		author.ContributionCount++
+		author.Weight += weight
+		author.GithubCommitStyleContributions = append(author.GithubCommitStyleContributions, c)
+		total += weight
+	}
+
+	authors := make([]types.Author, 0, len(byContributor))
+	for _, author := range byContributor {
+		author.OwnershipPercentage = float64(author.Weight) / float64(total) * 100
+		authors = append(authors, *author)
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Weight == authors[j].Weight {
+			return authors[i].SupabaseUserID < authors[j].SupabaseUserID
+		}
+		return authors[i].Weight > authors[j].Weight
+	})
+
+	return authors
+}
@@ -0,0 +1,116 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+)
+
+func authorshipContributions() []types.Contribution {
+	return []types.Contribution{
+		{ContributorID: "user-a", WalletAddress: "0xaaa", StoryID: "story-1", StoryLine: "Go.", Timestamp: 100},
+		{ContributorID: "user-a", WalletAddress: "0xaaa", StoryID: "story-1", StoryLine: "Run.", Timestamp: 100},
+		{ContributorID: "user-b", WalletAddress: "0xbbb", StoryID: "story-1", StoryLine: "The dragon slept beneath the ledger of forgotten blocks.", Timestamp: 100 + 2*TimeDecayHalfLife},
+	}
+}
+
+func TestAuthorshipPoliciesWeighContributions(t *testing.T) {
+	asOf := int64(100 + 2*TimeDecayHalfLife)
+
+	cases := []struct {
+		Policy string
+		Main   string
+		Want   map[string]int64
+	}{
+		{Policy: AuthorshipCount, Main: "user-a", Want: map[string]int64{"user-a": 2, "user-b": 1}},
+		{Policy: AuthorshipWords, Main: "user-b", Want: map[string]int64{"user-a": 2, "user-b": 9}},
+		{Policy: AuthorshipCharacters, Main: "user-b", Want: map[string]int64{"user-a": 7, "user-b": 56}},
+		{Policy: AuthorshipTimeDecay, Main: "user-b", Want: map[string]int64{"user-a": timeDecayScale / 2, "user-b": timeDecayScale}},
+	}
+
+	for _, tc := range cases {
+		policy, err := AuthorshipPolicyByName(tc.Policy)
+		if err != nil {
+			t.Fatalf("%s: resolve policy: %v", tc.Policy, err)
+		}
+
+		authors := AggregateAuthorsWithPolicy(authorshipContributions(), policy, asOf)
+		if len(authors) != 2 || authors[0].SupabaseUserID != tc.Main {
+			t.Fatalf("%s: expected %s as main author, got %+v", tc.Policy, tc.Main, authors)
+		}
+
+		for _, author := range authors {
+			if author.Weight != tc.Want[author.SupabaseUserID] {
+				t.Fatalf("%s: expected %s to weigh %d, got %d", tc.Policy, author.SupabaseUserID, tc.Want[author.SupabaseUserID], author.Weight)
+			}
+		}
+	}
+}
+
+func TestAuthorshipPolicyByName(t *testing.T) {
+	policy, err := AuthorshipPolicyByName("")
+	if err != nil || policy.Name() != AuthorshipCount {
+		t.Fatalf("expected empty name to select the count policy, got %v %v", policy, err)
+	}
+
+	if _, err := AuthorshipPolicyByName("loudest"); !errors.Is(err, ErrUnknownAuthorshipPolicy) {
+		t.Fatalf("expected unknown policy error, got %v", err)
+	}
+
+	want := []string{AuthorshipCharacters, AuthorshipCount, AuthorshipTimeDecay, AuthorshipWords}
+	got := AuthorshipPolicies()
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestMintNFTRecordsAuthorshipPolicy(t *testing.T) {
+	ipfs := storage.NewMemoryIPFS()
+
+	originalNow := types.NowUnix
+	types.NowUnix = func() int64 { return 100 + 2*TimeDecayHalfLife }
+	t.Cleanup(func() { types.NowUnix = originalNow })
+
+	nft, err := MintNFT(types.Story{
+		ID:               "story-1",
+		Title:            "Ledger Dragons",
+		Contributions:    authorshipContributions(),
+		AuthorshipPolicy: AuthorshipWords,
+	}, ipfs)
+	if err != nil {
+		t.Fatalf("mint nft failed: %v", err)
+	}
+
+	if nft.AuthorshipPolicy != AuthorshipWords || nft.MainAuthor.SupabaseUserID != "user-b" {
+		t.Fatalf("expected words policy to make user-b main author, got %+v", nft)
+	}
+
+	metadataBytes, err := ipfs.Fetch(nft.MetadataIPFSCID)
+	if err != nil {
+		t.Fatalf("failed to fetch metadata: %v", err)
+	}
+
+	var metadata struct {
+		AuthorshipPolicy string `json:"authorship_policy"`
+		MintedAt         int64  `json:"minted_at"`
+	}
+	if err := json.Unmarshal(metadataBytes, &metadata); err != nil {
+		t.Fatalf("failed to decode metadata: %v", err)
+	}
+
+	if metadata.AuthorshipPolicy != AuthorshipWords || metadata.MintedAt != nft.MintedAt {
+		t.Fatalf("expected metadata to record the policy and mint time, got %+v", metadata)
+	}
+
+	if _, err := MintNFT(types.Story{ID: "story-1", Title: "x", Contributions: authorshipContributions(), AuthorshipPolicy: "loudest"}, ipfs); !errors.Is(err, ErrUnknownAuthorshipPolicy) {
+		t.Fatalf("expected unknown policy to be rejected, got %v", err)
+	}
+}
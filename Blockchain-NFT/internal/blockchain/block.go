@@ -1,9 +1,8 @@
 package blockchain
 
 import (
-	"encoding/json"
-
 	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/canonical"
 	"storytelling-blockchain/pkg/utils"
 )
 
@@ -14,49 +13,48 @@ func NewBlock(index int, prevHash string, transactions []types.Transaction) type
 		Timestamp:           types.NowUnix(),
 		Transactions:        transactions,
 		PrevHash:            prevHash,
+		TxRoot:              CalculateTxRoot(transactions),
 		ValidatorSignatures: make(map[string]string),
 	}
 	block.Hash = CalculateHash(block)
 	return block
 }
 
-// CalculateHash deterministically hashes the block fields.
+// CalculateHash deterministically hashes the block header.
 func CalculateHash(block types.Block) string {
-	clone := block
-	clone.Hash = ""
-
-	for i := range clone.Transactions {
-		if clone.Transactions[i].Data == nil {
-			continue
-		}
-
-		canonical, err := canonicalizeJSON(clone.Transactions[i].Data)
-		if err != nil {
-			return ""
-		}
+	return HashHeader(block.Header())
+}
 
-		clone.Transactions[i].Data = canonical
-	}
+// HashHeader hashes the canonical encoding of a block header; it allows light
+// clients to verify a block hash without the block's transactions.
+func HashHeader(header types.BlockHeader) string {
+	return headerEncoder(header).Hash()
+}
 
-	payload, err := json.Marshal(clone)
-	if err != nil {
-		return ""
-	}
+// EncodeHeader returns the canonical encoding of a block header.
+func EncodeHeader(header types.BlockHeader) []byte {
+	return headerEncoder(header).Encoded()
+}
 
-	return utils.ComputeSHA256(payload)
+func headerEncoder(header types.BlockHeader) *canonical.Encoder {
+	return canonical.NewEncoder(canonical.TagBlockHeader).
+		Int64(int64(header.Index)).
+		Int64(header.Timestamp).
+		String(header.PrevHash).
+		String(header.TxRoot).
+		String(header.StateRoot).
+		Int64(int64(header.Nonce))
 }
 
-// canonicalizeJSON normalizes JSON-compatible values to avoid hash drift after reloading from storage.
-func canonicalizeJSON(value interface{}) (interface{}, error) {
-	bytes, err := json.Marshal(value)
-	if err != nil {
-		return nil, err
-	}
+// CalculateTxRoot computes the Merkle root over the transaction IDs in block order.
+func CalculateTxRoot(transactions []types.Transaction) string {
+	return utils.MerkleRoot(transactionIDs(transactions))
+}
 
-	var decoded interface{}
-	if err := json.Unmarshal(bytes, &decoded); err != nil {
-		return nil, err
+func transactionIDs(transactions []types.Transaction) []string {
+	ids := make([]string, len(transactions))
+	for i, tx := range transactions {
+		ids[i] = tx.TxID
 	}
-
-	return decoded, nil
+	return ids
 }
@@ -35,3 +35,18 @@ func TestNewBlock(t *testing.T) {
 		t.Fatalf("hash mismatch: expected %s got %s", expected, block.Hash)
 	}
 }
+
+func TestHashHeaderVector(t *testing.T) {
+	header := types.BlockHeader{Index: 1, Timestamp: 1761609600, PrevHash: "ab", TxRoot: "cd", StateRoot: "ef", Nonce: 0}
+
+	const want = "862266514d2226ad7d5fd0d35da2e9103b329f59601bdce33e5c531fb1f9f4d2"
+	if got := HashHeader(header); got != want {
+		t.Fatalf("unexpected header hash %s, want %s", got, want)
+	}
+
+	signed := types.Block{Index: 1, Timestamp: 1761609600, PrevHash: "ab", TxRoot: "cd", StateRoot: "ef"}
+	signed.ValidatorSignatures = map[string]string{"node-1": "sig"}
+	if CalculateHash(signed) != want {
+		t.Fatalf("validator signatures must not affect the block hash")
+	}
+}
@@ -3,8 +3,8 @@ package blockchain
 import (
 	"errors"
 	"sync"
-	"time"
 
+	"storytelling-blockchain/internal/mempool"
 	"storytelling-blockchain/internal/observer"
 	"storytelling-blockchain/internal/types"
 )
@@ -20,22 +20,96 @@ var (
 type Blockchain struct {
 	mu                  sync.RWMutex
 	blocks              []types.Block
-	walletRegistry      map[string]types.Wallet
-	nftRegistry         map[string]types.NFT
-	pendingTransactions []types.Transaction
+	state               types.State
+	stateTrees          []*stateTree
+	mempool             *mempool.Pool
 	observer            *observer.Bus
 	store               BlockStateStore
+	genesis             Genesis
+	registry            *TxRegistry
+	storyContributions  map[string][]types.Contribution
+	contributionIndex   map[string]contributionLocation
+	contributionHistory map[string][]types.ContributionRevision
+	nftHistory          map[string][]types.NFTProvenance
+	pendingMints        map[string]types.MintProposal
+	txBlocks            map[string]int
+	rejections          *rejectionLog
+	pendingView         *pendingView
 }
 
-// NewBlockchain bootstraps a chain with a genesis block.
-func NewBlockchain() *Blockchain {
-	genesis := NewBlock(0, "", nil)
-	return &Blockchain{
-		blocks:              []types.Block{genesis},
-		walletRegistry:      make(map[string]types.Wallet),
-		nftRegistry:         make(map[string]types.NFT),
-		pendingTransactions: make([]types.Transaction, 0),
+// Option customises a Blockchain at construction time.
+type Option func(*Blockchain)
+
+// WithTxRegistry builds the chain with the provided transaction handlers
+// instead of the built-in set.
+func WithTxRegistry(registry *TxRegistry) Option {
+	return func(bc *Blockchain) {
+		if registry != nil {
+			bc.registry = registry
+		}
+	}
+}
+
+// NewBlockchain bootstraps a chain from the default development genesis.
+func NewBlockchain(opts ...Option) *Blockchain {
+	genesis := DefaultGenesis()
+	block, _ := genesis.Block() // the default genesis always validates
+	return newBlockchain(genesis, block, opts)
+}
+
+// NewBlockchainFromGenesis bootstraps a chain from the provided genesis document.
+func NewBlockchainFromGenesis(genesis Genesis, opts ...Option) (*Blockchain, error) {
+	block, err := genesis.Block()
+	if err != nil {
+		return nil, err
 	}
+	return newBlockchain(genesis, block, opts), nil
+}
+
+func newBlockchain(genesis Genesis, block types.Block, opts []Option) *Blockchain {
+	state := genesis.State()
+	tree, _ := newStateTree(state) // genesis.Block already committed to this state
+
+	bc := &Blockchain{
+		blocks:              []types.Block{block},
+		state:               state,
+		stateTrees:          []*stateTree{tree},
+		mempool:             mempool.New(mempool.DefaultConfig()),
+		genesis:             genesis,
+		registry:            defaultTxRegistry,
+		storyContributions:  make(map[string][]types.Contribution),
+		contributionIndex:   make(map[string]contributionLocation),
+		contributionHistory: make(map[string][]types.ContributionRevision),
+		nftHistory:          make(map[string][]types.NFTProvenance),
+		pendingMints:        make(map[string]types.MintProposal),
+		txBlocks:            make(map[string]int),
+		rejections:          newRejectionLog(DefaultRejectedReceipts),
+	}
+
+	for _, tx := range block.Transactions {
+		bc.txBlocks[tx.TxID] = block.Index
+	}
+
+	for _, opt := range opts {
+		opt(bc)
+	}
+
+	return bc
+}
+
+// Genesis returns the genesis document the chain was built from.
+func (bc *Blockchain) Genesis() Genesis {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.genesis
+}
+
+// ChainID returns the genesis chain ID that signatures on this chain are
+// bound to.
+func (bc *Blockchain) ChainID() string {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+	return bc.genesis.ChainID
 }
 
 // SetObserver attaches the provided event bus to the blockchain.
@@ -77,51 +151,41 @@ func (bc *Blockchain) AddBlock(block types.Block) error {
 		if block.Hash != CalculateHash(block) {
 			return errHashMismatch
 		}
-
-		previous := bc.blocks
-		bc.blocks = []types.Block{block}
-
-		if err := bc.persistLocked(block); err != nil {
-			bc.blocks = previous
-			return err
+		if block.Hash != bc.blocks[0].Hash {
+			return errGenesisMismatch
 		}
 
+		// The genesis block is fixed at construction time; re-adding it is a no-op.
 		return nil
 	}
 
-	prev := bc.blocks[len(bc.blocks)-1]
-
-	state := types.State{
-		WalletRegistry: make(map[string]types.Wallet, len(bc.walletRegistry)),
-		NFTRegistry:    make(map[string]types.NFT, len(bc.nftRegistry)),
-	}
-
-	for k, v := range bc.walletRegistry {
-		state.WalletRegistry[k] = v
+	if err := bc.checkUncommittedLocked(block.Transactions); err != nil {
+		return err
 	}
 
-	for k, v := range bc.nftRegistry {
-		state.NFTRegistry[k] = v
-	}
+	prev := bc.blocks[len(bc.blocks)-1]
 
-	updatedState, err := ValidateBlock(block, prev, state)
+	// The block is applied to the live state in place; the journal undoes it
+	// if the block cannot be persisted.
+	updatedTree, journal, payloads, err := validateBlock(bc.registry, block, prev, &bc.state, bc.stateTrees[len(bc.stateTrees)-1])
 	if err != nil {
 		return err
 	}
 
-	prevWallets := bc.walletRegistry
-	prevNFTs := bc.nftRegistry
-
 	bc.blocks = append(bc.blocks, block)
-	bc.walletRegistry = updatedState.WalletRegistry
-	bc.nftRegistry = updatedState.NFTRegistry
+	bc.stateTrees = append(bc.stateTrees, updatedTree)
 
 	if err := bc.persistLocked(block); err != nil {
 		bc.blocks = bc.blocks[:len(bc.blocks)-1]
-		bc.walletRegistry = prevWallets
-		bc.nftRegistry = prevNFTs
+		bc.stateTrees = bc.stateTrees[:len(bc.stateTrees)-1]
+		journal.revert()
 		return err
 	}
+
+	bc.pendingView = nil
+	bc.indexPayloadsLocked(block, payloads)
+	bc.mempool.RemoveIncluded(block.Transactions)
+	bc.dropPendingLocked(block.Transactions)
 	return nil
 }
 
@@ -149,40 +213,15 @@ func (bc *Blockchain) ValidateChain() bool {
 		if CalculateHash(curr) != curr.Hash {
 			return false
 		}
+
+		if CalculateTxRoot(curr.Transactions) != curr.TxRoot {
+			return false
+		}
 	}
 
 	return true
 }
 
-// EnqueueTransaction stages a transaction for inclusion in the next block.
-func (bc *Blockchain) EnqueueTransaction(tx types.Transaction) {
-	bc.mu.Lock()
-	bc.pendingTransactions = append(bc.pendingTransactions, tx)
-	bc.mu.Unlock()
-
-	bc.emitEvent(observer.Event{
-		Type:      observer.EventTransactionQueued,
-		Timestamp: time.Now().UTC(),
-		Data:      tx,
-	})
-}
-
-// PendingTransactions returns a copy of currently staged transactions.
-func (bc *Blockchain) PendingTransactions() []types.Transaction {
-	bc.mu.RLock()
-	defer bc.mu.RUnlock()
-	txs := make([]types.Transaction, len(bc.pendingTransactions))
-	copy(txs, bc.pendingTransactions)
-	return txs
-}
-
-// ClearPendingTransactions drops all staged transactions.
-func (bc *Blockchain) ClearPendingTransactions() {
-	bc.mu.Lock()
-	defer bc.mu.Unlock()
-	bc.pendingTransactions = bc.pendingTransactions[:0]
-}
-
 func (bc *Blockchain) emitEvent(event observer.Event) {
 	if bc == nil {
 		return
@@ -198,15 +237,22 @@ func (bc *Blockchain) State() types.State {
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
 
-	return cloneStateMaps(bc.walletRegistry, bc.nftRegistry)
+	return cloneState(bc.state)
 }
 
 // RegisterWallet inserts or updates a wallet in the chain state keyed by Supabase user ID.
+// Wallets already committed on-chain are left untouched so that local
+// registrations cannot diverge the committed state.
 func (bc *Blockchain) RegisterWallet(wallet types.Wallet) {
 	bc.mu.Lock()
 	defer bc.mu.Unlock()
 
-	bc.walletRegistry[wallet.SupabaseUserID] = wallet
+	if existing, ok := bc.state.WalletRegistry[wallet.SupabaseUserID]; ok && existing.BlockIndex >= 0 {
+		return
+	}
+
+	bc.state.WalletRegistry[wallet.SupabaseUserID] = wallet
+	bc.pendingView = nil
 }
 
 // GetWalletBySupabaseID fetches the wallet for the provided Supabase user ID.
@@ -214,6 +260,6 @@ func (bc *Blockchain) GetWalletBySupabaseID(userID string) (types.Wallet, bool)
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
 
-	wallet, ok := bc.walletRegistry[userID]
+	wallet, ok := bc.state.WalletRegistry[userID]
 	return wallet, ok
 }
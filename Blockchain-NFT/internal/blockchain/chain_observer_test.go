@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"storytelling-blockchain/internal/observer"
-	"storytelling-blockchain/internal/types"
 )
 
 func TestEnqueueTransactionEmitsEvent(t *testing.T) {
@@ -16,8 +15,10 @@ func TestEnqueueTransactionEmitsEvent(t *testing.T) {
 	id, ch := bus.Subscribe(1)
 	t.Cleanup(func() { bus.Unsubscribe(id) })
 
-	tx := types.Transaction{TxID: "tx-1", Type: "test"}
-	chain.EnqueueTransaction(tx)
+	wallet, _ := newKeyedWallet(t, "alice")
+	if err := chain.EnqueueTransaction(newCreateWalletTx(t, wallet, 10)); err != nil {
+		t.Fatalf("enqueue transaction: %v", err)
+	}
 
 	select {
 	case ev := <-ch:
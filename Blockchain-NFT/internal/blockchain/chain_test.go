@@ -37,12 +37,14 @@ func TestAddBlockAndValidate(t *testing.T) {
 	types.NowUnix = func() int64 { return 2000 }
 	t.Cleanup(func() { types.NowUnix = originalNow })
 
-	bc := NewBlockchain()
-	genesis := bc.LatestBlock()
+	bc := newTestChain(t)
 
 	tx := types.Transaction{TxID: "tx-1", Type: "test", Timestamp: types.NowUnix()}
 
-	block := NewBlock(genesis.Index+1, genesis.Hash, []types.Transaction{tx})
+	block, err := bc.BuildBlock([]types.Transaction{tx})
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
 
 	if err := bc.AddBlock(block); err != nil {
 		t.Fatalf("unexpected error adding block: %v", err)
@@ -66,17 +68,25 @@ func TestAddBlockAndValidate(t *testing.T) {
 func TestPendingTransactionQueue(t *testing.T) {
 	bc := NewBlockchain()
 
-	tx := types.Transaction{TxID: "tx-queue", Type: "pending"}
-	bc.EnqueueTransaction(tx)
+	alice, _ := newKeyedWallet(t, "alice")
+	bob, _ := newKeyedWallet(t, "bob")
+	included := newCreateWalletTx(t, alice, 10)
+	waiting := newCreateWalletTx(t, bob, 10)
+	for _, tx := range []types.Transaction{included, waiting} {
+		if err := bc.EnqueueTransaction(tx); err != nil {
+			t.Fatalf("enqueue transaction: %v", err)
+		}
+	}
 
 	pending := bc.PendingTransactions()
-	if len(pending) != 1 || pending[0].TxID != "tx-queue" {
+	if len(pending) != 2 || pending[0].TxID != included.TxID || pending[1].TxID != waiting.TxID {
 		t.Fatalf("pending queue not updated as expected")
 	}
 
-	bc.ClearPendingTransactions()
-	if len(bc.PendingTransactions()) != 0 {
-		t.Fatalf("pending queue should be empty after clear")
+	commitTransactions(t, bc, included)
+	pending = bc.PendingTransactions()
+	if len(pending) != 1 || pending[0].TxID != waiting.TxID {
+		t.Fatalf("expected only the committed transaction to leave the queue, got %+v", pending)
 	}
 }
 
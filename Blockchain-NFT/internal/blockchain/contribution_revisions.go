@@ -0,0 +1,207 @@
+package blockchain
+
+import (
+	"errors"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errUnexpectedContributionTxID = errors.New("blockchain: contribution payload cannot carry a tx id")
+	errMissingContributionTxID    = errors.New("blockchain: contribution tx id required")
+	errUnknownContribution        = errors.New("blockchain: contribution not found")
+	errNotContributionAuthor      = errors.New("blockchain: only the original contributor can revise the contribution")
+	errContributionRetracted      = errors.New("blockchain: contribution already retracted")
+	errEmptyStoryLine             = errors.New("blockchain: story line required")
+)
+
+// Exported errors for contribution revisions.
+var (
+	ErrUnknownContribution   = errUnknownContribution
+	ErrNotContributionAuthor = errNotContributionAuthor
+	ErrContributionRetracted = errContributionRetracted
+)
+
+// contributionLocation points at a contribution in the story index.
+type contributionLocation struct {
+	storyID  string
+	position int
+}
+
+// revisableContribution returns the record of a live contribution owned by
+// contributorID whose story is still open.
+func revisableContribution(state types.State, txID, contributorID string) (types.ContributionRecord, types.StoryRecord, error) {
+	if txID == "" {
+		return types.ContributionRecord{}, types.StoryRecord{}, errMissingContributionTxID
+	}
+
+	record, ok := state.Contributions[txID]
+	if !ok {
+		return types.ContributionRecord{}, types.StoryRecord{}, errUnknownContribution
+	}
+
+	if contributorID == "" || contributorID != record.ContributorID {
+		return types.ContributionRecord{}, types.StoryRecord{}, errNotContributionAuthor
+	}
+
+	if record.Retracted {
+		return types.ContributionRecord{}, types.StoryRecord{}, errContributionRetracted
+	}
+
+	story, err := openStory(state, record.StoryID)
+	if err != nil {
+		return types.ContributionRecord{}, types.StoryRecord{}, err
+	}
+
+	return record, story, nil
+}
+
+// amendContributionHandler replaces the text of a committed contribution.
+type amendContributionHandler struct{}
+
+func (amendContributionHandler) Type() string { return TxTypeAmendContribution }
+
+func (amendContributionHandler) Signer(payload interface{}) string {
+	return payload.(types.AmendContributionPayload).ContributorID
+}
+
+func (amendContributionHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.AmendContributionPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (amendContributionHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	amend := decoded.(types.AmendContributionPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	_, story, err := revisableContribution(state, amend.TxID, amend.ContributorID)
+	if err != nil {
+		return err
+	}
+
+	if LineLength(amend.StoryLine) == 0 {
+		return errEmptyStoryLine
+	}
+
+	if !canContribute(state, story, amend.ContributorID) {
+		return errNotStoryContributor
+	}
+
+	if err := CheckLineLength(story.Rules, amend.StoryLine); err != nil {
+		return err
+	}
+
+	wallet, ok := state.WalletRegistry[amend.ContributorID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, wallet, tx)
+}
+
+func (amendContributionHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	amend := decoded.(types.AmendContributionPayload)
+
+	record := state.Contributions[amend.TxID]
+	record.StoryLine = amend.StoryLine
+	record.Revision++
+	SetStateEntry(ctx, StateKeyContribution, state.Contributions, amend.TxID, record)
+	return nil
+}
+
+// retractContributionHandler withdraws a committed contribution. The line
+// stays in the block history but no longer belongs to the story's text and
+// no longer counts towards authorship.
+type retractContributionHandler struct{}
+
+func (retractContributionHandler) Type() string { return TxTypeRetractContribution }
+
+func (retractContributionHandler) Signer(payload interface{}) string {
+	return payload.(types.RetractContributionPayload).ContributorID
+}
+
+func (retractContributionHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.RetractContributionPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (retractContributionHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	retract := decoded.(types.RetractContributionPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if _, _, err := revisableContribution(state, retract.TxID, retract.ContributorID); err != nil {
+		return err
+	}
+
+	wallet, ok := state.WalletRegistry[retract.ContributorID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, wallet, tx)
+}
+
+func (retractContributionHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	retract := decoded.(types.RetractContributionPayload)
+
+	record := state.Contributions[retract.TxID]
+	record.Revision++
+	record.Retracted = true
+	SetStateEntry(ctx, StateKeyContribution, state.Contributions, retract.TxID, record)
+	return nil
+}
+
+// appendRevisionLocked adds a revision to a contribution's history, numbering
+// it after the previous one.
+func (bc *Blockchain) appendRevisionLocked(txID string, revision types.ContributionRevision) {
+	history := bc.contributionHistory[txID]
+	if len(history) > 0 {
+		revision.Revision = history[len(history)-1].Revision + 1
+	}
+	bc.contributionHistory[txID] = append(history, revision)
+}
+
+// GetContribution returns the status record of a committed contribution.
+func (bc *Blockchain) GetContribution(txID string) (types.ContributionRecord, bool) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	record, ok := bc.state.Contributions[txID]
+	return record, ok
+}
+
+// ContributionHistory returns every revision of a contribution, starting
+// with the original line.
+func (bc *Blockchain) ContributionHistory(txID string) []types.ContributionRevision {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	indexed := bc.contributionHistory[txID]
+	if len(indexed) == 0 {
+		return nil
+	}
+
+	history := make([]types.ContributionRevision, len(indexed))
+	copy(history, indexed)
+	return history
+}
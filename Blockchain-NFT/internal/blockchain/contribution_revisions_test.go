@@ -0,0 +1,118 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func newAmendTx(t *testing.T, priv, contributorID, txID, line string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeAmendContribution, timestamp, types.AmendContributionPayload{TxID: txID, ContributorID: contributorID, StoryLine: line})
+}
+
+func newRetractTx(t *testing.T, priv, contributorID, txID string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeRetractContribution, timestamp, types.RetractContributionPayload{TxID: txID, ContributorID: contributorID})
+}
+
+func TestAmendAndRetractContributions(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 20))
+
+	first := newContributionTx(t, alicePriv, alice, "story-1", "Once upon a tiem", 30)
+	second := newContributionTx(t, bobPriv, bob, "story-1", "there was a chain", 31)
+	commitTransactions(t, bc, first, second)
+
+	lines := bc.StoryContributions("story-1")
+	if len(lines) != 2 || lines[0].TxID != first.TxID || lines[1].TxID != second.TxID {
+		t.Fatalf("expected contributions to carry their tx ids, got %+v", lines)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{newAmendTx(t, bobPriv, "bob", first.TxID, "Hijacked", 40)}); !errors.Is(err, ErrNotContributionAuthor) {
+		t.Fatalf("expected amend by another author to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newAmendTx(t, alicePriv, "alice", "missing", "Typo", 40)}); !errors.Is(err, ErrUnknownContribution) {
+		t.Fatalf("expected amend of unknown contribution to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, newAmendTx(t, alicePriv, "alice", first.TxID, "Once upon a time", 40))
+	commitTransactions(t, bc, newRetractTx(t, bobPriv, "bob", second.TxID, 41))
+
+	if _, err := bc.BuildBlock([]types.Transaction{newAmendTx(t, bobPriv, "bob", second.TxID, "Back again", 42)}); !errors.Is(err, ErrContributionRetracted) {
+		t.Fatalf("expected amend of retracted contribution to fail, got %v", err)
+	}
+
+	lines = bc.StoryContributions("story-1")
+	if len(lines) != 1 || lines[0].StoryLine != "Once upon a time" || lines[0].Timestamp != 30 {
+		t.Fatalf("expected only the amended line, got %+v", lines)
+	}
+
+	history := bc.ContributionHistory(first.TxID)
+	if len(history) != 2 || history[0].StoryLine != "Once upon a tiem" || history[1].Type != TxTypeAmendContribution || history[1].Revision != 1 {
+		t.Fatalf("unexpected amend history: %+v", history)
+	}
+
+	history = bc.ContributionHistory(second.TxID)
+	if len(history) != 2 || history[1].Type != TxTypeRetractContribution {
+		t.Fatalf("unexpected retract history: %+v", history)
+	}
+
+	record, ok := bc.GetContribution(second.TxID)
+	if !ok || !record.Retracted || record.Revision != 1 {
+		t.Fatalf("unexpected contribution record: %+v", record)
+	}
+
+	commitTransactions(t, bc, newCloseStoryTx(t, alicePriv, "alice", "story-1", 50))
+
+	if _, err := bc.BuildBlock([]types.Transaction{newAmendTx(t, alicePriv, "alice", first.TxID, "Once upon a rhyme", 60)}); !errors.Is(err, ErrStoryClosed) {
+		t.Fatalf("expected amend after close to fail, got %v", err)
+	}
+}
+
+func TestRetractedLinesKeepForkBranch(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "root", 20))
+
+	first := newContributionTx(t, alicePriv, alice, "root", "One", 30)
+	commitTransactions(t, bc, first)
+	second := newContributionTx(t, alicePriv, alice, "root", "Two", 31)
+	commitTransactions(t, bc, second)
+	commitTransactions(t, bc, newForkStoryTx(t, alicePriv, "alice", "fork", types.StoryLineage{ParentID: "root", BranchTxID: second.TxID}, 40))
+	commitTransactions(t, bc, newRetractTx(t, alicePriv, "alice", first.TxID, 50))
+
+	lines := bc.StoryContributions("fork")
+	if len(lines) != 1 || lines[0].StoryLine != "Two" {
+		t.Fatalf("expected the fork to inherit only the remaining line, got %+v", lines)
+	}
+}
+
+func TestContributionPayloadRejectsTxID(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 20))
+
+	tx := signTestTx(t, alicePriv, TxTypeContribution, 30, types.ContributionPayload{Contribution: types.Contribution{
+		TxID:          "forged",
+		ContributorID: "alice",
+		WalletAddress: alice.Address,
+		StoryID:       "story-1",
+		StoryLine:     "Hello",
+		Timestamp:     30,
+	}})
+	if _, err := bc.BuildBlock([]types.Transaction{tx}); !errors.Is(err, errUnexpectedContributionTxID) {
+		t.Fatalf("expected payload tx id to be rejected, got %v", err)
+	}
+}
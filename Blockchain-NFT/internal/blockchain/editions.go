@@ -0,0 +1,80 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errInvalidEdition    = errors.New("blockchain: nft edition must follow the story's latest edition")
+	errInvalidSupersedes = errors.New("blockchain: nft must supersede the story's latest edition")
+)
+
+// Exported errors for NFT editions.
+var (
+	ErrInvalidEdition    = errInvalidEdition
+	ErrInvalidSupersedes = errInvalidSupersedes
+)
+
+// NextEdition returns the edition number and the superseded token ID of the
+// next NFT minted from the story.
+func NextEdition(story types.StoryRecord) (int, string) {
+	return story.Edition + 1, story.EditionTokenID
+}
+
+// checkEdition makes sure an NFT is the next edition of its story.
+func checkEdition(story types.StoryRecord, nft types.NFT) error {
+	edition, supersedes := NextEdition(story)
+	if nft.Edition != edition {
+		return errInvalidEdition
+	}
+	if nft.Supersedes != supersedes {
+		return errInvalidSupersedes
+	}
+	return nil
+}
+
+// StoryEditions returns the NFTs minted from a story, first edition first.
+func (bc *Blockchain) StoryEditions(storyID string) []types.NFT {
+	editions := bc.NFTsByStory(storyID)
+	sort.Slice(editions, func(i, j int) bool {
+		if editions[i].Edition == editions[j].Edition {
+			return editions[i].BlockIndex < editions[j].BlockIndex
+		}
+		return editions[i].Edition < editions[j].Edition
+	})
+	return editions
+}
+
+// NextEditionStory assembles the next edition of a story from its current
+// main line, ready for MintNFT.
+func (bc *Blockchain) NextEditionStory(storyID, title, summary string) (types.Story, error) {
+	record, ok := bc.GetStory(storyID)
+	if !ok {
+		return types.Story{}, errUnknownStory
+	}
+
+	contributions := bc.StoryContributions(storyID)
+	if len(contributions) == 0 {
+		return types.Story{}, errNoContributions
+	}
+
+	policy, err := AuthorshipPolicyByName(record.Rules.AuthorshipPolicy)
+	if err != nil {
+		return types.Story{}, err
+	}
+
+	edition, supersedes := NextEdition(record)
+	return types.Story{
+		ID:               storyID,
+		Title:            title,
+		Summary:          summary,
+		Contributions:    contributions,
+		AuthorshipPolicy: policy.Name(),
+		Lineage:          record.Lineage,
+		Edition:          edition,
+		Supersedes:       supersedes,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+// newStoryNFT builds an NFT of the story whose authors are derived from the
+// lines committed on bc, as MintNFT derives them.
+func newStoryNFT(t *testing.T, bc *Blockchain, storyID, tokenID string, mintedAt int64) types.NFT {
+	t.Helper()
+
+	state := bc.State()
+	authors, err := mintAuthors(state, storyID, mintedAt)
+	if err != nil || len(authors) == 0 {
+		t.Fatalf("derive authors of %s: %v", storyID, err)
+	}
+
+	policy, err := AuthorshipPolicyByName(state.StoryRegistry[storyID].Rules.AuthorshipPolicy)
+	if err != nil {
+		t.Fatalf("story policy: %v", err)
+	}
+
+	return types.NFT{
+		TokenID:          tokenID,
+		StoryID:          storyID,
+		MainAuthor:       authors[0],
+		CoAuthors:        authors[1:],
+		OwnerID:          authors[0].SupabaseUserID,
+		OwnerAddress:     authors[0].WalletAddress,
+		AuthorshipPolicy: policy.Name(),
+		MintedAt:         mintedAt,
+	}
+}
+
+// newEditionTx proposes an edition of story-1 on behalf of its main author,
+// which mints it at once when that author holds the threshold.
+func newEditionTx(t *testing.T, bc *Blockchain, priv, tokenID string, edition int, supersedes string, mintedAt int64) (types.NFT, types.Transaction) {
+	t.Helper()
+
+	nft := newStoryNFT(t, bc, "story-1", tokenID, mintedAt)
+	nft.Edition = edition
+	nft.Supersedes = supersedes
+
+	return nft, newProposeMintTx(t, priv, nft.OwnerID, nft, types.NowUnix()+3600)
+}
+
+func TestMintNFTEditions(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+	commitTransactions(t, bc, newOpenMintStoryTx(t, alicePriv, "alice", "story-1", 20))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "It was a dark night", 25))
+
+	_, skipped := newEditionTx(t, bc, alicePriv, "nft-1", 2, "", 30)
+	if _, err := bc.BuildBlock([]types.Transaction{skipped}); !errors.Is(err, ErrInvalidEdition) {
+		t.Fatalf("expected edition skipping the first to fail, got %v", err)
+	}
+
+	first, firstTx := newEditionTx(t, bc, alicePriv, "nft-1", 1, "", 30)
+	first.AuthorshipPolicy = AuthorshipWords
+	mismatched := newProposeMintTx(t, alicePriv, "alice", first, types.NowUnix()+3600)
+	if _, err := bc.BuildBlock([]types.Transaction{mismatched}); !errors.Is(err, errAuthorshipPolicyMismatch) {
+		t.Fatalf("expected policy differing from story rules to fail, got %v", err)
+	}
+
+	// An open story can be minted; later editions capture the lines added since.
+	commitTransactions(t, bc, firstTx)
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Once upon a time", 40))
+
+	_, repeated := newEditionTx(t, bc, alicePriv, "nft-1b", 1, "", 50)
+	if _, err := bc.BuildBlock([]types.Transaction{repeated}); !errors.Is(err, ErrInvalidEdition) {
+		t.Fatalf("expected a second first edition to fail, got %v", err)
+	}
+	_, detached := newEditionTx(t, bc, alicePriv, "nft-2", 2, "", 50)
+	if _, err := bc.BuildBlock([]types.Transaction{detached}); !errors.Is(err, ErrInvalidSupersedes) {
+		t.Fatalf("expected edition without supersedes to fail, got %v", err)
+	}
+
+	_, secondTx := newEditionTx(t, bc, alicePriv, "nft-2", 2, "nft-1", 50)
+	commitTransactions(t, bc, secondTx)
+
+	story, ok := bc.GetStory("story-1")
+	if !ok || story.Edition != 2 || story.EditionTokenID != "nft-2" {
+		t.Fatalf("expected story to point at the second edition, got %+v", story)
+	}
+
+	editions := bc.StoryEditions("story-1")
+	if len(editions) != 2 || editions[0].TokenID != "nft-1" || editions[1].Supersedes != "nft-1" {
+		t.Fatalf("unexpected edition timeline: %+v", editions)
+	}
+}
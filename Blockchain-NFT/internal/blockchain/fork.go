@@ -0,0 +1,162 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+
+	"storytelling-blockchain/internal/types"
+)
+
+// DefaultInheritanceRatio is the share of a fork, in share units, credited to
+// the authors of the inherited lines when a fork does not choose one.
+const DefaultInheritanceRatio int64 = 2000
+
+var (
+	errMissingLineage          = errors.New("blockchain: fork requires a parent story and branch point")
+	errInvalidBranchPoint      = errors.New("blockchain: branch point outside the parent story")
+	errInvalidInheritanceRatio = errors.New("blockchain: inheritance ratio must be between 0 and 9999 share units")
+)
+
+// Exported errors for story forks.
+var (
+	ErrInvalidBranchPoint      = errInvalidBranchPoint
+	ErrInvalidInheritanceRatio = errInvalidInheritanceRatio
+)
+
+// forkStoryHandler registers a story that continues another one from a
+// chosen line.
+type forkStoryHandler struct{}
+
+func (forkStoryHandler) Type() string { return TxTypeForkStory }
+
+func (forkStoryHandler) Signer(payload interface{}) string {
+	return payload.(types.ForkStoryPayload).Story.CreatorID
+}
+
+func (forkStoryHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.ForkStoryPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (forkStoryHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	story := decoded.(types.ForkStoryPayload).Story
+
+	lineage := story.Lineage
+	if lineage == nil || lineage.ParentID == "" || lineage.BranchTxID == "" {
+		return errMissingLineage
+	}
+
+	if _, ok := state.StoryRegistry[lineage.ParentID]; !ok {
+		return errUnknownStory
+	}
+
+	if !storyHasLine(state, lineage.ParentID, lineage.BranchTxID) {
+		return errInvalidBranchPoint
+	}
+
+	if lineage.InheritanceRatio < 0 || lineage.InheritanceRatio >= TotalShareUnits {
+		return errInvalidInheritanceRatio
+	}
+
+	return validateNewStory(state, tx, story)
+}
+
+func (forkStoryHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	registerStory(ctx, state, decoded.(types.ForkStoryPayload).Story)
+	return nil
+}
+
+// StoryAuthors aggregates the authors of a story under the policy. For forks
+// the inherited lines and the fork's own lines are weighed separately: the
+// ancestor authors split Lineage.InheritanceRatio share units and the fork
+// authors split the rest, and each author's Weight is the resulting number of
+// units. A line is inherited when its StoryID is not the fork's, so lines
+// left out of the list, such as retracted ones, do not shift the split. The
+// fork author with most units leads the list so that the fork is owned by one
+// of its own writers; a fork without lines of its own credits everything to
+// its ancestors.
+func StoryAuthors(story types.Story, policy AuthorshipPolicy, asOf int64) []types.Author {
+	if story.Lineage == nil {
+		return AggregateAuthorsWithPolicy(story.Contributions, policy, asOf)
+	}
+
+	var inherited, forked []types.Contribution
+	for _, contribution := range story.Contributions {
+		if contribution.StoryID == story.ID {
+			forked = append(forked, contribution)
+		} else {
+			inherited = append(inherited, contribution)
+		}
+	}
+
+	ancestors := AggregateAuthorsWithPolicy(inherited, policy, asOf)
+	own := AggregateAuthorsWithPolicy(forked, policy, asOf)
+
+	ancestorUnits := story.Lineage.InheritanceRatio
+	if len(own) == 0 {
+		ancestorUnits = TotalShareUnits
+	}
+
+	units := make(map[string]int64, len(ancestors)+len(own))
+	for holder, n := range allocateUnits(ancestors, ancestorUnits) {
+		units[holder] += n
+	}
+	for holder, n := range allocateUnits(own, TotalShareUnits-ancestorUnits) {
+		units[holder] += n
+	}
+
+	byAuthor := make(map[string]*types.Author)
+	for _, group := range [][]types.Author{ancestors, own} {
+		for _, author := range group {
+			merged, ok := byAuthor[author.SupabaseUserID]
+			if !ok {
+				merged = &types.Author{SupabaseUserID: author.SupabaseUserID, WalletAddress: author.WalletAddress}
+				byAuthor[author.SupabaseUserID] = merged
+			}
+			merged.ContributionCount += author.ContributionCount
+			merged.GithubCommitStyleContributions = append(merged.GithubCommitStyleContributions, author.GithubCommitStyleContributions...)
+		}
+	}
+
+	authors := make([]types.Author, 0, len(byAuthor))
+	for holder, author := range byAuthor {
+		if units[holder] == 0 {
+			continue
+		}
+		author.Weight = units[holder]
+		author.OwnershipPercentage = float64(author.Weight) / float64(TotalShareUnits) * 100
+		authors = append(authors, *author)
+	}
+
+	sort.Slice(authors, func(i, j int) bool {
+		if authors[i].Weight == authors[j].Weight {
+			return authors[i].SupabaseUserID < authors[j].SupabaseUserID
+		}
+		return authors[i].Weight > authors[j].Weight
+	})
+
+	if len(own) == 0 {
+		return authors
+	}
+
+	var lead string
+	var leadUnits int64 = -1
+	for _, author := range own {
+		if n := units[author.SupabaseUserID]; n > leadUnits || (n == leadUnits && author.SupabaseUserID < lead) {
+			lead, leadUnits = author.SupabaseUserID, n
+		}
+	}
+
+	for i, author := range authors {
+		if author.SupabaseUserID == lead {
+			copy(authors[1:i+1], authors[:i])
+			authors[0] = author
+			break
+		}
+	}
+
+	return authors
+}
@@ -0,0 +1,208 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+)
+
+func newForkStoryTx(t *testing.T, priv, creatorID, storyID string, lineage types.StoryLineage, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeForkStory, timestamp, types.ForkStoryPayload{Story: types.StoryRecord{
+		ID:        storyID,
+		Title:     "Fork " + storyID,
+		CreatorID: creatorID,
+		CreatedAt: timestamp,
+		Lineage:   &lineage,
+	}})
+}
+
+func TestForkStoryInheritsPrefix(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "root", 20))
+	one := newContributionTx(t, alicePriv, alice, "root", "One", 30)
+	commitTransactions(t, bc, one)
+	two := newContributionTx(t, alicePriv, alice, "root", "Two", 31)
+	commitTransactions(t, bc, two)
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "root", "Three", 32))
+
+	if _, err := bc.BuildBlock([]types.Transaction{newForkStoryTx(t, bobPriv, "bob", "fork", types.StoryLineage{ParentID: "root", BranchTxID: "missing"}, 40)}); !errors.Is(err, ErrInvalidBranchPoint) {
+		t.Fatalf("expected unknown branch line to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newForkStoryTx(t, bobPriv, "bob", "fork", types.StoryLineage{ParentID: "root", BranchTxID: two.TxID, InheritanceRatio: TotalShareUnits}, 40)}); !errors.Is(err, ErrInvalidInheritanceRatio) {
+		t.Fatalf("expected full inheritance ratio to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newForkStoryTx(t, bobPriv, "bob", "fork", types.StoryLineage{ParentID: "missing", BranchTxID: one.TxID}, 40)}); !errors.Is(err, ErrUnknownStory) {
+		t.Fatalf("expected unknown parent to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, newForkStoryTx(t, bobPriv, "bob", "fork", types.StoryLineage{ParentID: "root", BranchTxID: two.TxID, InheritanceRatio: 2500}, 40))
+	own := newContributionTx(t, bobPriv, bob, "fork", "Two and a half", 50)
+	commitTransactions(t, bc, own)
+
+	lines := bc.StoryContributions("fork")
+	if len(lines) != 3 || lines[0].StoryLine != "One" || lines[1].StoryLine != "Two" || lines[2].StoryLine != "Two and a half" {
+		t.Fatalf("unexpected fork lines: %+v", lines)
+	}
+
+	// A line of another story is not a branch point of the parent.
+	if _, err := bc.BuildBlock([]types.Transaction{newForkStoryTx(t, alicePriv, "alice", "fork-2", types.StoryLineage{ParentID: "root", BranchTxID: own.TxID}, 60)}); !errors.Is(err, ErrInvalidBranchPoint) {
+		t.Fatalf("expected a line of the fork to be rejected for root, got %v", err)
+	}
+
+	// A fork of the fork may branch inside the inherited prefix or after it.
+	commitTransactions(t, bc, newForkStoryTx(t, alicePriv, "alice", "fork-2", types.StoryLineage{ParentID: "fork", BranchTxID: own.TxID}, 60))
+	if lines := bc.StoryContributions("fork-2"); len(lines) != 3 || lines[2].ContributorID != "bob" {
+		t.Fatalf("unexpected nested fork lines: %+v", lines)
+	}
+	commitTransactions(t, bc, newForkStoryTx(t, bobPriv, "bob", "fork-3", types.StoryLineage{ParentID: "fork", BranchTxID: one.TxID}, 61))
+	if lines := bc.StoryContributions("fork-3"); len(lines) != 1 || lines[0].StoryLine != "One" {
+		t.Fatalf("unexpected fork lines inside the inherited prefix: %+v", lines)
+	}
+}
+
+func TestForkPrefixFollowsBranchLine(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "root", 20))
+
+	one := newContributionTx(t, alicePriv, alice, "root", "One", 30)
+	commitTransactions(t, bc, one)
+	two := newContributionTx(t, alicePriv, alice, "root", "Two", 31)
+	commitTransactions(t, bc, two)
+	commitTransactions(t, bc, newForkStoryTx(t, bobPriv, "bob", "fork", types.StoryLineage{ParentID: "root", BranchTxID: two.TxID}, 40))
+
+	// Another reply to the first line, whatever its place in the parent's
+	// main line, leaves the fork's prefix as it was.
+	commitTransactions(t, bc, newReplyTx(t, bobPriv, bob, "root", one.TxID, "Two, again", 50))
+
+	lines := bc.StoryContributions("fork")
+	if len(lines) != 2 || lines[0].TxID != one.TxID || lines[1].TxID != two.TxID {
+		t.Fatalf("expected the fork to keep its branch line, got %+v", lines)
+	}
+}
+
+func TestStoryAuthorsCreditsAncestors(t *testing.T) {
+	contributions := []types.Contribution{
+		{TxID: "one", StoryID: "root", ContributorID: "alice", StoryLine: "One"},
+		{TxID: "two", StoryID: "root", ContributorID: "carol", StoryLine: "Two"},
+		{TxID: "three", StoryID: "fork", ContributorID: "bob", StoryLine: "Three"},
+	}
+
+	story := types.Story{
+		ID:            "fork",
+		Contributions: contributions,
+		Lineage:       &types.StoryLineage{ParentID: "root", BranchTxID: "two", InheritanceRatio: 3000},
+	}
+
+	authors := StoryAuthors(story, countPolicy{}, 0)
+	if len(authors) != 3 {
+		t.Fatalf("expected 3 authors, got %+v", authors)
+	}
+	if authors[0].SupabaseUserID != "bob" || authors[0].Weight != 7000 {
+		t.Fatalf("expected bob to lead with 7000 units, got %+v", authors[0])
+	}
+	if authors[1].Weight != 1500 || authors[2].Weight != 1500 {
+		t.Fatalf("expected ancestors to split 3000 units, got %+v", authors[1:])
+	}
+
+	balances := AllocateShares(authors)
+	if balances["bob"] != 7000 || balances["alice"] != 1500 || balances["carol"] != 1500 {
+		t.Fatalf("unexpected share allocation: %+v", balances)
+	}
+
+	// An ancestor outweighing every fork author still does not own the fork.
+	story.Lineage = &types.StoryLineage{ParentID: "root", BranchTxID: "one", InheritanceRatio: 9000}
+	story.Contributions = []types.Contribution{contributions[0], {StoryID: "fork", ContributorID: "bob", StoryLine: "Two'"}}
+	if authors := StoryAuthors(story, countPolicy{}, 0); authors[0].SupabaseUserID != "bob" || authors[1].Weight != 9000 {
+		t.Fatalf("expected fork author to lead, got %+v", authors)
+	}
+
+	// Without lines of its own the fork credits its ancestors in full.
+	story.Contributions = contributions[:1]
+	if authors := StoryAuthors(story, countPolicy{}, 0); len(authors) != 1 || authors[0].Weight != TotalShareUnits {
+		t.Fatalf("expected ancestors to receive every unit, got %+v", authors)
+	}
+}
+
+func TestStoryAuthorsIgnoreRetractedInheritedLines(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "root", 20))
+
+	first := newContributionTx(t, alicePriv, alice, "root", "One", 30)
+	commitTransactions(t, bc, first)
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "root", "Two", 31))
+	third := newContributionTx(t, alicePriv, alice, "root", "Three", 32)
+	commitTransactions(t, bc, third)
+	commitTransactions(t, bc, newForkStoryTx(t, bobPriv, "bob", "fork", types.StoryLineage{ParentID: "root", BranchTxID: third.TxID, InheritanceRatio: 2000}, 40))
+	commitTransactions(t, bc, newContributionTx(t, bobPriv, bob, "fork", "Four", 50))
+	commitTransactions(t, bc, newRetractTx(t, alicePriv, "alice", first.TxID, 60))
+
+	record, _ := bc.GetStory("fork")
+	check := func(label string) {
+		t.Helper()
+		story := types.Story{ID: "fork", Contributions: bc.StoryContributions("fork"), Lineage: record.Lineage}
+		balances := AllocateShares(StoryAuthors(story, countPolicy{}, 0))
+		if balances["alice"] != 2000 || balances["bob"] != 8000 {
+			t.Fatalf("%s: expected alice 2000 and bob 8000 units, got %+v", label, balances)
+		}
+	}
+
+	check("retracted parent line")
+
+	// Retracting the branch line itself does not hand its place to the fork.
+	commitTransactions(t, bc, newRetractTx(t, alicePriv, "alice", third.TxID, 70))
+	check("retracted branch line")
+}
+
+func TestMintForkRecordsLineage(t *testing.T) {
+	ipfs := storage.NewMemoryIPFS()
+
+	story := types.Story{
+		ID:    "fork",
+		Title: "Fork",
+		Contributions: []types.Contribution{
+			{TxID: "one", StoryID: "root", ContributorID: "alice", StoryLine: "One"},
+			{TxID: "two", StoryID: "fork", ContributorID: "bob", StoryLine: "Two"},
+		},
+		Lineage: &types.StoryLineage{ParentID: "root", BranchTxID: "one", InheritanceRatio: 2000},
+	}
+
+	nft, err := MintNFT(story, ipfs)
+	if err != nil {
+		t.Fatalf("mint: %v", err)
+	}
+
+	if nft.OwnerID != "bob" || nft.MainAuthor.Weight != 8000 || len(nft.CoAuthors) != 1 || nft.CoAuthors[0].Weight != 2000 {
+		t.Fatalf("unexpected fork authors: %+v / %+v", nft.MainAuthor, nft.CoAuthors)
+	}
+
+	var metadata struct {
+		Lineage *types.StoryLineage `json:"lineage"`
+	}
+	raw, err := ipfs.Fetch(nft.MetadataIPFSCID)
+	if err != nil {
+		t.Fatalf("fetch metadata: %v", err)
+	}
+	if err := json.Unmarshal(raw, &metadata); err != nil {
+		t.Fatalf("decode metadata: %v", err)
+	}
+	if metadata.Lineage == nil || metadata.Lineage.ParentID != "root" || metadata.Lineage.InheritanceRatio != 2000 {
+		t.Fatalf("expected lineage in metadata, got %+v", metadata.Lineage)
+	}
+}
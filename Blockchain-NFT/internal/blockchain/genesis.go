@@ -0,0 +1,162 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"storytelling-blockchain/internal/types"
+)
+
+const (
+	// DefaultChainID identifies the local development network.
+	DefaultChainID = "kahani-devnet"
+	// DefaultGenesisTimestamp pins the development genesis block to a fixed time.
+	DefaultGenesisTimestamp int64 = 1761609600
+
+	genesisTransactionType = "genesis"
+)
+
+var (
+	errMissingChainID       = errors.New("genesis: chain id required")
+	errMissingGenesisTime   = errors.New("genesis: timestamp required")
+	errMissingValidatorID   = errors.New("genesis: validator id required")
+	errDuplicateValidator   = errors.New("genesis: duplicate validator id")
+	errDuplicateGenesisUser = errors.New("genesis: duplicate wallet supabase user id")
+	errGenesisMismatch      = errors.New("blockchain: genesis hash mismatch")
+)
+
+// ErrGenesisMismatch is returned when stored blocks were produced from a different genesis.
+var ErrGenesisMismatch = errGenesisMismatch
+
+// Genesis describes the deterministic starting point shared by every node of a chain.
+type Genesis struct {
+	ChainID    string            `json:"chain_id"`
+	Timestamp  int64             `json:"timestamp"`
+	Validators []types.Validator `json:"validators"`
+	Wallets    []types.Wallet    `json:"wallets,omitempty"`
+}
+
+// DefaultGenesis returns the genesis used when no genesis file is configured.
+func DefaultGenesis() Genesis {
+	return Genesis{
+		ChainID:   DefaultChainID,
+		Timestamp: DefaultGenesisTimestamp,
+	}
+}
+
+// LoadGenesis reads and validates a genesis.json document.
+func LoadGenesis(path string) (Genesis, error) {
+	if path == "" {
+		return Genesis{}, errors.New("genesis: path is empty")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Genesis{}, fmt.Errorf("genesis: read file failed: %w", err)
+	}
+
+	var genesis Genesis
+	if err := json.Unmarshal(data, &genesis); err != nil {
+		return Genesis{}, fmt.Errorf("genesis: decode json failed: %w", err)
+	}
+
+	if err := genesis.Validate(); err != nil {
+		return Genesis{}, err
+	}
+
+	return genesis, nil
+}
+
+// Validate checks the genesis document for missing or conflicting entries.
+func (g Genesis) Validate() error {
+	if g.ChainID == "" {
+		return errMissingChainID
+	}
+
+	if g.Timestamp <= 0 {
+		return errMissingGenesisTime
+	}
+
+	validators := make(map[string]struct{}, len(g.Validators))
+	for _, validator := range g.Validators {
+		if validator.ID == "" {
+			return errMissingValidatorID
+		}
+		if _, exists := validators[validator.ID]; exists {
+			return fmt.Errorf("%w: %s", errDuplicateValidator, validator.ID)
+		}
+		validators[validator.ID] = struct{}{}
+	}
+
+	wallets := make(map[string]struct{}, len(g.Wallets))
+	for _, wallet := range g.Wallets {
+		if wallet.SupabaseUserID == "" {
+			return errMissingWalletID
+		}
+		if wallet.Address == "" {
+			return errMissingWalletAddress
+		}
+		if wallet.PublicKey == "" {
+			return errMissingWalletKeys
+		}
+		if _, exists := wallets[wallet.SupabaseUserID]; exists {
+			return fmt.Errorf("%w: %s", errDuplicateGenesisUser, wallet.SupabaseUserID)
+		}
+		wallets[wallet.SupabaseUserID] = struct{}{}
+	}
+
+	return nil
+}
+
+// Block builds the genesis block. The genesis document is embedded as the
+// block's only transaction so that the block hash commits to all of it.
+func (g Genesis) Block() (types.Block, error) {
+	if err := g.Validate(); err != nil {
+		return types.Block{}, err
+	}
+
+	tx, err := NewTransaction(genesisTransactionType, g.Timestamp, g)
+	if err != nil {
+		return types.Block{}, err
+	}
+
+	block := types.Block{
+		Index:               0,
+		Timestamp:           g.Timestamp,
+		Transactions:        []types.Transaction{tx},
+		TxRoot:              CalculateTxRoot([]types.Transaction{tx}),
+		StateRoot:           CalculateStateRoot(g.State()),
+		ValidatorSignatures: make(map[string]string),
+	}
+	block.Hash = CalculateHash(block)
+	return block, nil
+}
+
+// State returns the chain state seeded by the genesis document.
+func (g Genesis) State() types.State {
+	state := types.State{
+		ChainID:        g.ChainID,
+		WalletRegistry: make(map[string]types.Wallet, len(g.Wallets)),
+		NFTRegistry:    make(map[string]types.NFT),
+		ShareBalances:  make(map[string]map[string]int64),
+		StoryRegistry:  make(map[string]types.StoryRecord),
+		StoryMembers:   make(map[string]map[string]string),
+		Contributions:  make(map[string]types.ContributionRecord),
+		MintProposals:  make(map[string]types.MintProposal),
+		Nonces:         make(map[string]int64),
+		Validators:     make(map[string]types.Validator, len(g.Validators)),
+	}
+
+	for _, wallet := range g.Wallets {
+		wallet.BlockIndex = 0
+		state.WalletRegistry[wallet.SupabaseUserID] = wallet
+	}
+
+	for _, validator := range g.Validators {
+		state.Validators[validator.ID] = validator
+	}
+
+	return state
+}
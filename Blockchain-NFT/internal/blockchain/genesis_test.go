@@ -0,0 +1,127 @@
+package blockchain
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+)
+
+func TestGenesisBlockIsDeterministic(t *testing.T) {
+	originalNow := types.NowUnix
+	types.NowUnix = func() int64 { return 1111 }
+
+	first := NewBlockchain().LatestBlock()
+
+	types.NowUnix = func() int64 { return 2222 }
+	t.Cleanup(func() { types.NowUnix = originalNow })
+
+	second := NewBlockchain().LatestBlock()
+
+	if first.Hash != second.Hash {
+		t.Fatalf("expected identical genesis hashes, got %s and %s", first.Hash, second.Hash)
+	}
+
+	if first.Timestamp != DefaultGenesisTimestamp {
+		t.Fatalf("expected genesis timestamp %d, got %d", DefaultGenesisTimestamp, first.Timestamp)
+	}
+}
+
+func TestGenesisSeedsWalletsAndValidators(t *testing.T) {
+	genesis := Genesis{
+		ChainID:    "kahani-test",
+		Timestamp:  1700000000,
+		Validators: []types.Validator{{ID: "node-1"}, {ID: "node-2"}},
+		Wallets: []types.Wallet{
+			{SupabaseUserID: "user-1", Address: "0xabc", PublicKey: "pub"},
+		},
+	}
+
+	bc, err := NewBlockchainFromGenesis(genesis)
+	if err != nil {
+		t.Fatalf("new blockchain from genesis: %v", err)
+	}
+
+	wallet, ok := bc.GetWalletBySupabaseID("user-1")
+	if !ok {
+		t.Fatalf("expected genesis wallet to be registered")
+	}
+
+	if wallet.BlockIndex != 0 {
+		t.Fatalf("expected genesis wallet block index 0, got %d", wallet.BlockIndex)
+	}
+
+	if got := len(bc.Genesis().Validators); got != 2 {
+		t.Fatalf("expected two genesis validators, got %d", got)
+	}
+
+	other := genesis
+	other.ChainID = "kahani-other"
+	otherBlock, err := other.Block()
+	if err != nil {
+		t.Fatalf("build other genesis: %v", err)
+	}
+
+	if otherBlock.Hash == bc.LatestBlock().Hash {
+		t.Fatalf("expected chain id to change the genesis hash")
+	}
+}
+
+func TestGenesisValidate(t *testing.T) {
+	cases := map[string]Genesis{
+		"missing chain id":    {Timestamp: 1},
+		"missing timestamp":   {ChainID: "c"},
+		"missing validator":   {ChainID: "c", Timestamp: 1, Validators: []types.Validator{{}}},
+		"duplicate validator": {ChainID: "c", Timestamp: 1, Validators: []types.Validator{{ID: "a"}, {ID: "a"}}},
+		"incomplete wallet":   {ChainID: "c", Timestamp: 1, Wallets: []types.Wallet{{SupabaseUserID: "u"}}},
+	}
+
+	for name, genesis := range cases {
+		if err := genesis.Validate(); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestLoadGenesisFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "genesis.json")
+	doc := `{"chain_id":"kahani-file","timestamp":1700000000,"validators":[{"id":"node-1"}]}`
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatalf("write genesis: %v", err)
+	}
+
+	genesis, err := LoadGenesis(path)
+	if err != nil {
+		t.Fatalf("load genesis: %v", err)
+	}
+
+	if genesis.ChainID != "kahani-file" || len(genesis.Validators) != 1 {
+		t.Fatalf("unexpected genesis: %+v", genesis)
+	}
+}
+
+func TestLoadBlockchainRejectsGenesisMismatch(t *testing.T) {
+	store, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	if _, err := LoadBlockchain(store); err != nil {
+		t.Fatalf("initial load failed: %v", err)
+	}
+
+	other := DefaultGenesis()
+	other.ChainID = "kahani-other"
+
+	if _, err := LoadBlockchainWithGenesis(store, other); !errors.Is(err, ErrGenesisMismatch) {
+		t.Fatalf("expected genesis mismatch error, got %v", err)
+	}
+
+	if _, err := LoadBlockchain(store); err != nil {
+		t.Fatalf("reload with matching genesis failed: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"storytelling-blockchain/internal/mempool"
+	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/types"
+)
+
+// WithMempool builds the chain with a mempool bounded by cfg instead of the
+// default limits.
+func WithMempool(cfg mempool.Config) Option {
+	return func(bc *Blockchain) {
+		bc.mempool = mempool.New(cfg)
+	}
+}
+
+// EnqueueTransaction admits a transaction to the mempool for inclusion in a
+// later block. It is rejected unless it applies to the committed state on top
+// of the transactions already pending, so that duplicates, bad signatures and
+// reused nonces never reach consensus. Committed blocks remove exactly their
+// own transactions from the mempool. When the store keeps the mempool, the
+// transaction is persisted before the admission is reported.
+func (bc *Blockchain) EnqueueTransaction(tx types.Transaction) error {
+	bc.mu.RLock()
+	err := bc.admitLocked(tx)
+	bc.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	bc.emitEvent(observer.Event{
+		Type:      observer.EventTransactionQueued,
+		Timestamp: time.Now().UTC(),
+		Data:      tx,
+	})
+	return nil
+}
+
+// admitLocked adds tx to the mempool and persists it. Holding the chain lock
+// keeps a block from committing tx, and deleting its stored entry, before the
+// entry is written. Refused transactions get a rejection receipt unless they
+// lack an ID or are already pending or committed.
+func (bc *Blockchain) admitLocked(tx types.Transaction) error {
+	bc.expirePendingLocked()
+
+	if err := bc.checkUncommittedLocked([]types.Transaction{tx}); err != nil {
+		return err
+	}
+
+	queuedAt := time.Now()
+	if err := bc.mempool.AddAt(tx, queuedAt, bc.checkPendingLocked); err != nil {
+		if !errors.Is(err, mempool.ErrMissingTxID) && !errors.Is(err, mempool.ErrDuplicateTx) {
+			bc.rejectLocked(tx, err)
+		}
+		return err
+	}
+
+	store, ok := bc.store.(PendingTxStore)
+	if !ok {
+		return nil
+	}
+
+	if err := store.SavePendingTransaction(types.PendingTransaction{Transaction: tx, QueuedAt: queuedAt.UnixNano()}); err != nil {
+		bc.mempool.Remove(tx.TxID)
+		return fmt.Errorf("blockchain: persist pending transaction: %w", err)
+	}
+
+	return nil
+}
+
+// dropPendingLocked deletes the stored entries of transactions that left the
+// mempool. A failed delete is not fatal: the next load prunes entries that
+// were committed or expired.
+func (bc *Blockchain) dropPendingLocked(txs []types.Transaction) {
+	store, ok := bc.store.(PendingTxStore)
+	if !ok || len(txs) == 0 {
+		return
+	}
+
+	txIDs := make([]string, len(txs))
+	for i, tx := range txs {
+		txIDs[i] = tx.TxID
+	}
+	_ = store.DeletePendingTransactions(txIDs...)
+}
+
+// restorePendingLocked reloads the stored mempool once the chain has been
+// replayed. Entries are re-admitted in their original order and keep their
+// age; those already committed, expired or no longer valid against the
+// recovered state are pruned from the store, and the latter two rejected.
+func (bc *Blockchain) restorePendingLocked() error {
+	store, ok := bc.store.(PendingTxStore)
+	if !ok {
+		return nil
+	}
+
+	stored, err := store.PendingTransactions()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(stored, func(i, j int) bool {
+		if stored[i].QueuedAt == stored[j].QueuedAt {
+			return stored[i].Transaction.TxID < stored[j].Transaction.TxID
+		}
+		return stored[i].QueuedAt < stored[j].QueuedAt
+	})
+
+	var pruned []string
+	for _, entry := range stored {
+		tx := entry.Transaction
+		if _, committed := bc.txBlocks[tx.TxID]; committed {
+			pruned = append(pruned, tx.TxID)
+			continue
+		}
+		if err := bc.mempool.AddAt(tx, time.Unix(0, entry.QueuedAt), bc.checkPendingLocked); err != nil {
+			pruned = append(pruned, tx.TxID)
+			bc.rejectLocked(tx, err)
+		}
+	}
+
+	return store.DeletePendingTransactions(pruned...)
+}
+
+// pendingView is the committed state with the pending transactions applied,
+// as if they formed the next block. Each admission applies its transaction to
+// the view, so a check only validates its own transaction. The view is
+// dropped when the committed state changes and rebuilt once transactions
+// leave the mempool.
+type pendingView struct {
+	state   types.State
+	size    int
+	removed uint64
+}
+
+// checkPendingLocked checks tx against the pending view and returns the user
+// that signed it. Pending transactions that no longer apply when the view is
+// rebuilt are skipped rather than held against tx; they leave the mempool
+// when they expire.
+func (bc *Blockchain) checkPendingLocked(pending mempool.Pending, tx types.Transaction) (string, error) {
+	prev := bc.blocks[len(bc.blocks)-1]
+	ctx := TxContext{BlockIndex: prev.Index + 1, BlockTimestamp: types.NowUnix()}
+
+	view := bc.pendingView
+	if view == nil || view.size != pending.Len() || view.removed != pending.Removed() {
+		view = &pendingView{state: cloneState(bc.liveStateLocked()), size: pending.Len(), removed: pending.Removed()}
+		for _, queued := range pending.Transactions() {
+			_, _ = bc.registry.apply(ctx, &view.state, queued)
+		}
+		bc.pendingView = view
+	}
+
+	checked, err := bc.registry.check(ctx, view.state, tx)
+	if err != nil {
+		return "", err
+	}
+
+	// The pool enforces the sender limit after the check, too late to keep
+	// tx out of the view.
+	if err := pending.CheckSender(checked.signer); err != nil {
+		return "", err
+	}
+
+	if err := checked.apply(ctx, &view.state); err != nil {
+		bc.pendingView = nil
+		return "", err
+	}
+
+	view.size++
+	return checked.signer, nil
+}
+
+// PendingTransactions returns the mempool's transactions in admission order.
+// Transactions that waited past the mempool's time to live are rejected first.
+func (bc *Blockchain) PendingTransactions() []types.Transaction {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	bc.expirePendingLocked()
+	return bc.mempool.Transactions()
+}
+
+// WithdrawTransaction removes a pending transaction from the mempool and the
+// store, for a submitter that could not hand it to consensus. No receipt is
+// recorded. It reports whether the transaction was still pending.
+func (bc *Blockchain) WithdrawTransaction(txID string) bool {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	tx, ok := bc.mempool.Get(txID)
+	if !ok || !bc.mempool.Remove(txID) {
+		return false
+	}
+
+	bc.dropPendingLocked([]types.Transaction{tx})
+	return true
+}
+
+// PendingTransaction returns a transaction waiting in the mempool.
+func (bc *Blockchain) PendingTransaction(txID string) (types.Transaction, bool) {
+	return bc.mempool.Get(txID)
+}
+
+// MempoolStats reports the size, limits and counters of the mempool.
+func (bc *Blockchain) MempoolStats() mempool.Stats {
+	return bc.mempool.Stats()
+}
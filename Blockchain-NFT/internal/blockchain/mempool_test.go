@@ -0,0 +1,99 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/mempool"
+	"storytelling-blockchain/internal/types"
+)
+
+func TestEnqueueTransactionAdmission(t *testing.T) {
+	bc := NewBlockchain(WithMempool(mempool.Config{MaxPerSender: 2}))
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	_, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+
+	if err := bc.EnqueueTransaction(types.Transaction{TxID: "tx-1", Type: "unknown"}); !errors.Is(err, ErrUnknownTransactionType) {
+		t.Fatalf("expected an unknown type to be rejected, got %v", err)
+	}
+	if err := bc.EnqueueTransaction(newCreateStoryTx(t, bobPriv, "alice", "story-1", 20)); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected a forged signature to be rejected, got %v", err)
+	}
+
+	create := signTestTxWithNonce(t, alicePriv, 1, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{ID: "story-1", Title: "Story", CreatorID: "alice", CreatedAt: 20}})
+	if err := bc.EnqueueTransaction(create); err != nil {
+		t.Fatalf("enqueue create_story: %v", err)
+	}
+	if err := bc.EnqueueTransaction(create); !errors.Is(err, mempool.ErrDuplicateTx) {
+		t.Fatalf("expected a duplicate to be rejected, got %v", err)
+	}
+
+	// Admission applies the pending transactions first, so a line can follow
+	// the story it belongs to before either is committed.
+	line := types.ContributionPayload{Contribution: types.Contribution{ContributorID: "alice", WalletAddress: alice.Address, StoryID: "story-1", StoryLine: "Once", Timestamp: 30}}
+	if err := bc.EnqueueTransaction(signTestTxWithNonce(t, alicePriv, 2, TxTypeContribution, 30, line)); err != nil {
+		t.Fatalf("enqueue contribution: %v", err)
+	}
+
+	if next := bc.NextNonce("alice"); next != 3 {
+		t.Fatalf("expected the next nonce to count pending transactions, got %d", next)
+	}
+
+	line.Contribution.StoryLine, line.Contribution.Timestamp = "Again", 31
+	if err := bc.EnqueueTransaction(signTestTxWithNonce(t, alicePriv, 2, TxTypeContribution, 31, line)); !errors.Is(err, ErrNonceReplay) {
+		t.Fatalf("expected a pending nonce to be rejected, got %v", err)
+	}
+	if err := bc.EnqueueTransaction(signTestTxWithNonce(t, alicePriv, 3, TxTypeContribution, 31, line)); !errors.Is(err, mempool.ErrSenderLimit) {
+		t.Fatalf("expected the sender limit to apply, got %v", err)
+	}
+
+	pending := bc.PendingTransactions()
+	if len(pending) != 2 {
+		t.Fatalf("expected two pending transactions, got %d", len(pending))
+	}
+
+	commitTransactions(t, bc, pending...)
+	stats := bc.MempoolStats()
+	if stats.Size != 0 || stats.Admitted != 2 || stats.Included != 2 || stats.Rejected != 5 {
+		t.Fatalf("unexpected mempool stats: %+v", stats)
+	}
+}
+
+func TestPendingViewFollowsTheMempool(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+
+	create := signTestTxWithNonce(t, alicePriv, 1, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{ID: "story-1", Title: "Story", CreatorID: "alice", CreatedAt: 20}})
+	if err := bc.EnqueueTransaction(create); err != nil {
+		t.Fatalf("enqueue create_story: %v", err)
+	}
+	view := bc.pendingView
+
+	line := types.ContributionPayload{Contribution: types.Contribution{ContributorID: "alice", WalletAddress: alice.Address, StoryID: "story-1", StoryLine: "Once", Timestamp: 30}}
+	first := signTestTxWithNonce(t, alicePriv, 2, TxTypeContribution, 30, line)
+	if err := bc.EnqueueTransaction(first); err != nil {
+		t.Fatalf("enqueue contribution: %v", err)
+	}
+	if bc.pendingView != view || view.size != 2 || view.state.Nonces["alice"] != 2 {
+		t.Fatalf("expected the admission to extend the pending view, got %+v", bc.pendingView)
+	}
+
+	// A transaction leaving the mempool frees its nonce for the next admission.
+	bc.mempool.Remove(first.TxID)
+	line.Contribution.StoryLine = "Instead"
+	if err := bc.EnqueueTransaction(signTestTxWithNonce(t, alicePriv, 2, TxTypeContribution, 30, line)); err != nil {
+		t.Fatalf("enqueue replacement contribution: %v", err)
+	}
+	if bc.pendingView == view || bc.pendingView.size != 2 {
+		t.Fatalf("expected the pending view to be rebuilt, got %+v", bc.pendingView)
+	}
+
+	commitTransactions(t, bc, bc.PendingTransactions()...)
+	if bc.pendingView != nil {
+		t.Fatalf("expected a committed block to drop the pending view")
+	}
+}
@@ -0,0 +1,331 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+
+	"storytelling-blockchain/internal/types"
+)
+
+// DefaultMintThreshold is the approval threshold, in share units, that a
+// proposal needs when the story's rules do not set one: more than half of
+// the shares.
+const DefaultMintThreshold = TotalShareUnits/2 + 1
+
+var (
+	errMintProposalExpiry  = errors.New("blockchain: mint proposal must expire after the block time")
+	errMintProposalPending = errors.New("blockchain: story already has a pending mint proposal")
+	errUnknownMintProposal = errors.New("blockchain: mint proposal not found")
+	errMintProposalExpired = errors.New("blockchain: mint proposal expired")
+	errNotMintAuthor       = errors.New("blockchain: only an author of the proposed nft can propose or approve it")
+	errAlreadyApproved     = errors.New("blockchain: author already approved the mint proposal")
+)
+
+// Exported errors for mint proposals.
+var (
+	ErrMintProposalPending = errMintProposalPending
+	ErrUnknownMintProposal = errUnknownMintProposal
+	ErrMintProposalExpired = errMintProposalExpired
+	ErrNotMintAuthor       = errNotMintAuthor
+	ErrAlreadyApproved     = errAlreadyApproved
+)
+
+// MintThreshold is the approval threshold of the story's mint proposals.
+func MintThreshold(story types.StoryRecord) int64 {
+	if story.Rules.MintThreshold > 0 {
+		return story.Rules.MintThreshold
+	}
+	return DefaultMintThreshold
+}
+
+// ApprovalUnits is the weight of userID's approval of the proposal: the share
+// units they would receive. It is zero for non-authors.
+func ApprovalUnits(proposal types.MintProposal, userID string) int64 {
+	return proposal.Weights[userID]
+}
+
+// proposeMintHandler opens a mint proposal. The proposer's approval is
+// implied, so a proposer holding the threshold on their own mints at once.
+type proposeMintHandler struct{}
+
+func (proposeMintHandler) Type() string { return TxTypeProposeMint }
+
+func (proposeMintHandler) Signer(payload interface{}) string {
+	return payload.(types.ProposeMintPayload).ProposerID
+}
+
+func (proposeMintHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.ProposeMintPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (proposeMintHandler) Validate(ctx TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	proposal := decoded.(types.ProposeMintPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if proposal.ExpiresAt <= ctx.BlockTimestamp {
+		return errMintProposalExpiry
+	}
+
+	if err := validateMint(state, proposal.NFT); err != nil {
+		return err
+	}
+
+	authors, err := checkMintAuthors(ctx, state, tx, proposal.NFT)
+	if err != nil {
+		return err
+	}
+
+	if AllocateShares(authors)[proposal.ProposerID] == 0 {
+		return errNotMintAuthor
+	}
+
+	if err := checkNoPendingMint(ctx, state, proposal.NFT.StoryID); err != nil {
+		return err
+	}
+
+	wallet, ok := state.WalletRegistry[proposal.ProposerID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, wallet, tx)
+}
+
+// Apply opens the proposal with the story's threshold and the approval
+// weights derived from the state, not from the proposed NFT.
+func (proposeMintHandler) Apply(ctx TxContext, state *types.State, tx types.Transaction, decoded interface{}) error {
+	payload := decoded.(types.ProposeMintPayload)
+
+	authors, err := mintAuthors(*state, payload.NFT.StoryID, payload.NFT.MintedAt)
+	if err != nil {
+		return err
+	}
+	weights := AllocateShares(authors)
+
+	proposal := types.MintProposal{
+		ID:            tx.TxID,
+		NFT:           payload.NFT,
+		ProposerID:    payload.ProposerID,
+		Threshold:     MintThreshold(state.StoryRegistry[payload.NFT.StoryID]),
+		Weights:       weights,
+		Approvals:     []string{payload.ProposerID},
+		ApprovedUnits: weights[payload.ProposerID],
+		CreatedAt:     tx.Timestamp,
+		ExpiresAt:     payload.ExpiresAt,
+		BlockIndex:    ctx.BlockIndex,
+	}
+
+	if proposal.ApprovedUnits >= proposal.Threshold {
+		applyMint(ctx, state, proposal.NFT)
+		return nil
+	}
+
+	SetStateEntry(ctx, StateKeyMintProposal, state.MintProposals, proposal.ID, proposal)
+	return nil
+}
+
+// approveMintHandler records an author's approval of a pending proposal and
+// mints the NFT once the approvals reach the threshold.
+type approveMintHandler struct{}
+
+func (approveMintHandler) Type() string { return TxTypeApproveMint }
+
+func (approveMintHandler) Signer(payload interface{}) string {
+	return payload.(types.ApproveMintPayload).ApproverID
+}
+
+func (approveMintHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.ApproveMintPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (approveMintHandler) Validate(ctx TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	approval := decoded.(types.ApproveMintPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	proposal, ok := state.MintProposals[approval.ProposalID]
+	if !ok {
+		return errUnknownMintProposal
+	}
+
+	if proposal.ExpiresAt <= ctx.BlockTimestamp {
+		return errMintProposalExpired
+	}
+
+	units := ApprovalUnits(proposal, approval.ApproverID)
+	if units == 0 {
+		return errNotMintAuthor
+	}
+
+	if hasApproved(proposal, approval.ApproverID) {
+		return errAlreadyApproved
+	}
+
+	// The finalizing approval re-checks the mint. A proposal the chain has
+	// moved past is dropped at the end of the block that made it stale, see
+	// settleMintProposals. The authors were checked against the story's lines
+	// when the proposal was made, and the approvals are for that snapshot.
+	if proposal.ApprovedUnits+units >= proposal.Threshold {
+		if err := validateMint(state, proposal.NFT); err != nil {
+			return err
+		}
+	}
+
+	wallet, ok := state.WalletRegistry[approval.ApproverID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, wallet, tx)
+}
+
+func (approveMintHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	approval := decoded.(types.ApproveMintPayload)
+
+	proposal := state.MintProposals[approval.ProposalID]
+	proposal.ApprovedUnits += ApprovalUnits(proposal, approval.ApproverID)
+
+	if proposal.ApprovedUnits >= proposal.Threshold {
+		DeleteStateEntry(ctx, StateKeyMintProposal, state.MintProposals, proposal.ID)
+		applyMint(ctx, state, proposal.NFT)
+		return nil
+	}
+
+	// The previous proposal is kept to undo the block, so the approvals
+	// slice is rebuilt rather than appended to.
+	approvals := make([]string, 0, len(proposal.Approvals)+1)
+	approvals = append(approvals, proposal.Approvals...)
+	approvals = append(approvals, approval.ApproverID)
+	sort.Strings(approvals)
+	proposal.Approvals = approvals
+
+	SetStateEntry(ctx, StateKeyMintProposal, state.MintProposals, proposal.ID, proposal)
+	return nil
+}
+
+func hasApproved(proposal types.MintProposal, userID string) bool {
+	i := sort.SearchStrings(proposal.Approvals, userID)
+	return i < len(proposal.Approvals) && proposal.Approvals[i] == userID
+}
+
+// checkNoPendingMint rejects a mint of a story while a proposal to mint it
+// is pending. It is shared by mint_nft and propose_mint.
+func checkNoPendingMint(ctx TxContext, state types.State, storyID string) error {
+	for _, pending := range state.MintProposals {
+		if pending.NFT.StoryID == storyID && pending.ExpiresAt > ctx.BlockTimestamp {
+			return errMintProposalPending
+		}
+	}
+	return nil
+}
+
+// settleMintProposals drops the proposals that expire by timestamp and those
+// that can no longer be finalized, for instance because their token ID was
+// minted meanwhile, so they stop blocking new mints of the story. It runs
+// after the transactions of every block, at the block's timestamp.
+func settleMintProposals(ctx TxContext, state *types.State, timestamp int64) {
+	for id, proposal := range state.MintProposals {
+		if proposal.ExpiresAt <= timestamp || validateMint(*state, proposal.NFT) != nil {
+			DeleteStateEntry(ctx, StateKeyMintProposal, state.MintProposals, id)
+		}
+	}
+}
+
+// indexMintProposalLocked follows proposals and approvals so that the mint
+// provenance names the transaction that finalized the NFT. The weights are
+// taken from the committed NFT, whose authors propose_mint checked against
+// the ones the state derives, and story rules never change, so the index
+// counts approvals as the state did.
+func (bc *Blockchain) indexMintProposalLocked(block types.Block, tx types.Transaction, payload interface{}) {
+	switch p := payload.(type) {
+	case types.ProposeMintPayload:
+		weights := AllocateShares(nftAuthors(p.NFT))
+		proposal := types.MintProposal{
+			ID:            tx.TxID,
+			NFT:           p.NFT,
+			Threshold:     MintThreshold(bc.state.StoryRegistry[p.NFT.StoryID]),
+			Weights:       weights,
+			ApprovedUnits: weights[p.ProposerID],
+		}
+		if proposal.ApprovedUnits >= proposal.Threshold {
+			bc.recordMintLocked(block, tx, proposal.NFT)
+			return
+		}
+		bc.pendingMints[proposal.ID] = proposal
+
+	case types.ApproveMintPayload:
+		proposal, ok := bc.pendingMints[p.ProposalID]
+		if !ok {
+			return
+		}
+		proposal.ApprovedUnits += ApprovalUnits(proposal, p.ApproverID)
+		if proposal.ApprovedUnits >= proposal.Threshold {
+			delete(bc.pendingMints, proposal.ID)
+			bc.recordMintLocked(block, tx, proposal.NFT)
+			return
+		}
+		bc.pendingMints[proposal.ID] = proposal
+	}
+}
+
+// pruneMintProposalsLocked forgets the proposals the block dropped.
+func (bc *Blockchain) pruneMintProposalsLocked() {
+	for id := range bc.pendingMints {
+		if _, ok := bc.state.MintProposals[id]; !ok {
+			delete(bc.pendingMints, id)
+		}
+	}
+}
+
+// MintProposal returns a pending mint proposal.
+func (bc *Blockchain) MintProposal(id string) (types.MintProposal, bool) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	proposal, ok := bc.state.MintProposals[id]
+	return proposal, ok
+}
+
+// MintProposalsByStory returns the pending mint proposals of a story, oldest
+// first.
+func (bc *Blockchain) MintProposalsByStory(storyID string) []types.MintProposal {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	var proposals []types.MintProposal
+	for _, proposal := range bc.state.MintProposals {
+		if proposal.NFT.StoryID == storyID {
+			proposals = append(proposals, proposal)
+		}
+	}
+
+	sort.Slice(proposals, func(i, j int) bool {
+		if proposals[i].CreatedAt == proposals[j].CreatedAt {
+			return proposals[i].ID < proposals[j].ID
+		}
+		return proposals[i].CreatedAt < proposals[j].CreatedAt
+	})
+
+	return proposals
+}
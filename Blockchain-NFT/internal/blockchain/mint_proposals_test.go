@@ -0,0 +1,216 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func newProposeMintTx(t *testing.T, priv, proposerID string, nft types.NFT, expiresAt int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeProposeMint, nft.MintedAt, types.ProposeMintPayload{NFT: nft, ProposerID: proposerID, ExpiresAt: expiresAt})
+}
+
+func newApproveMintTx(t *testing.T, priv, approverID, proposalID string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeApproveMint, timestamp, types.ApproveMintPayload{ProposalID: proposalID, ApproverID: approverID})
+}
+
+func TestMintProposalLifecycle(t *testing.T) {
+	now := int64(1000)
+	originalNow := types.NowUnix
+	types.NowUnix = func() int64 { return now }
+	t.Cleanup(func() { types.NowUnix = originalNow })
+
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	carol, carolPriv := newKeyedWallet(t, "carol")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10), newCreateWalletTx(t, carol, 10))
+	commitTransactions(t, bc, newOpenMintStoryTx(t, alicePriv, "alice", "story-1", 20))
+
+	// Four lines by alice and three each by bob and carol.
+	for i := 0; i < 3; i++ {
+		commitTransactions(t, bc,
+			newContributionTx(t, alicePriv, alice, "story-1", "Alice writes", 25),
+			newContributionTx(t, bobPriv, bob, "story-1", "Bob writes", 25),
+			newContributionTx(t, carolPriv, carol, "story-1", "Carol writes", 25))
+	}
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Alice ends", 25))
+
+	edition := func(tokenID string, number int, supersedes string) types.NFT {
+		nft := newStoryNFT(t, bc, "story-1", tokenID, 30)
+		nft.Edition = number
+		nft.Supersedes = supersedes
+		return nft
+	}
+
+	first := edition("nft-1", 1, "")
+	if _, err := bc.BuildBlock([]types.Transaction{newProposeMintTx(t, carolPriv, "carol", first, 1000)}); !errors.Is(err, errMintProposalExpiry) {
+		t.Fatalf("expected proposal expiring at the block time to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newProposeMintTx(t, carolPriv, "dave", first, 2000)}); !errors.Is(err, ErrNotMintAuthor) {
+		t.Fatalf("expected proposal by a non-author to fail, got %v", err)
+	}
+
+	// Weights favouring the proposer are not the story's.
+	inflated := first
+	inflated.CoAuthors = append([]types.Author(nil), first.CoAuthors...)
+	inflated.MainAuthor.Weight, inflated.CoAuthors[1].Weight = 1000, 6000
+	if _, err := bc.BuildBlock([]types.Transaction{newProposeMintTx(t, carolPriv, "carol", inflated, 2000)}); !errors.Is(err, ErrMintAuthorsMismatch) {
+		t.Fatalf("expected made-up weights to fail, got %v", err)
+	}
+
+	// Nobody mints alone through mint_nft.
+	direct, err := NewTransaction(TxTypeMintNFT, first.MintedAt, types.MintNFTPayload{NFT: first})
+	if err != nil {
+		t.Fatalf("build mint: %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{direct}); !errors.Is(err, ErrMintWithoutTrigger) {
+		t.Fatalf("expected a mint_nft without triggers to fail, got %v", err)
+	}
+
+	propose := newProposeMintTx(t, carolPriv, "carol", first, 2000)
+	commitTransactions(t, bc, propose)
+
+	proposal, ok := bc.MintProposal(propose.TxID)
+	if !ok || proposal.ApprovedUnits != 3000 || len(proposal.Approvals) != 1 || proposal.Approvals[0] != "carol" {
+		t.Fatalf("unexpected pending proposal: %+v", proposal)
+	}
+	if proposal.Threshold != DefaultMintThreshold || proposal.Weights["alice"] != 4000 || proposal.Weights["bob"] != 3000 || proposal.Weights["carol"] != 3000 {
+		t.Fatalf("expected the story's threshold and derived weights, got %+v", proposal)
+	}
+	if _, minted := bc.GetNFT("nft-1"); minted {
+		t.Fatalf("expected the nft to wait for approvals")
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{newProposeMintTx(t, alicePriv, "alice", edition("nft-1b", 1, ""), 2000)}); !errors.Is(err, ErrMintProposalPending) {
+		t.Fatalf("expected a second proposal for the story to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newApproveMintTx(t, carolPriv, "carol", propose.TxID, 40)}); !errors.Is(err, ErrAlreadyApproved) {
+		t.Fatalf("expected a repeated approval to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newApproveMintTx(t, bobPriv, "bob", "missing", 40)}); !errors.Is(err, ErrUnknownMintProposal) {
+		t.Fatalf("expected approval of an unknown proposal to fail, got %v", err)
+	}
+
+	approve := newApproveMintTx(t, bobPriv, "bob", propose.TxID, 40)
+	commitTransactions(t, bc, approve)
+
+	if _, pending := bc.MintProposal(propose.TxID); pending {
+		t.Fatalf("expected the proposal to be finalized")
+	}
+	nft, ok := bc.GetNFT("nft-1")
+	if !ok || nft.OwnerID != "alice" {
+		t.Fatalf("expected the nft to be minted, got %+v", nft)
+	}
+	if history := bc.NFTHistory("nft-1"); len(history) != 1 || history[0].TxID != approve.TxID || history[0].Type != TxTypeApproveMint {
+		t.Fatalf("expected the finalizing approval in the provenance, got %+v", history)
+	}
+
+	// Expired proposals are dropped by the first block past their expiry.
+	expiring := newProposeMintTx(t, bobPriv, "bob", edition("nft-2", 2, "nft-1"), 1500)
+	commitTransactions(t, bc, expiring)
+	if proposals := bc.MintProposalsByStory("story-1"); len(proposals) != 1 {
+		t.Fatalf("expected one pending proposal, got %+v", proposals)
+	}
+
+	now = 1500
+	if _, err := bc.BuildBlock([]types.Transaction{newApproveMintTx(t, alicePriv, "alice", expiring.TxID, 60)}); !errors.Is(err, ErrMintProposalExpired) {
+		t.Fatalf("expected approval at the expiry to fail, got %v", err)
+	}
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Later", 50))
+	if _, pending := bc.MintProposal(expiring.TxID); pending {
+		t.Fatalf("expected the expired proposal to be dropped")
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newApproveMintTx(t, alicePriv, "alice", expiring.TxID, 60)}); !errors.Is(err, ErrUnknownMintProposal) {
+		t.Fatalf("expected approval of an expired proposal to fail, got %v", err)
+	}
+}
+
+func TestStaleMintProposalIsDropped(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Milestones",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{MintAtContributions: 2, MintWhileOpen: true},
+		CreatedAt: 20,
+	}}))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "One", 25), newContributionTx(t, bobPriv, bob, "story-1", "Two", 25))
+	commitTransactions(t, bc, newOpenMintStoryTx(t, alicePriv, "alice", "story-2", 26))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-2", "Solo", 27))
+
+	nft := newStoryNFT(t, bc, "story-1", "nft-1", 30)
+	nft.Edition = 1
+	propose := newProposeMintTx(t, bobPriv, "bob", nft, types.NowUnix()+3600)
+	commitTransactions(t, bc, propose)
+
+	// A triggered mint_nft waits for the pending proposal like propose_mint.
+	triggered, err := NewTransaction(TxTypeMintNFT, 30, types.MintNFTPayload{NFT: nft, Triggers: []string{MintTriggerContributions}})
+	if err != nil {
+		t.Fatalf("build mint: %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{triggered}); !errors.Is(err, ErrMintProposalPending) {
+		t.Fatalf("expected a triggered mint to wait for the proposal, got %v", err)
+	}
+
+	// Another story takes the proposed token ID, so the proposal can no
+	// longer be finalized and is dropped with the block.
+	taken := newStoryNFT(t, bc, "story-2", "nft-1", 35)
+	taken.Edition = 1
+	commitTransactions(t, bc, newProposeMintTx(t, alicePriv, "alice", taken, types.NowUnix()+3600))
+	if _, pending := bc.MintProposal(propose.TxID); pending {
+		t.Fatalf("expected the stale proposal to be dropped")
+	}
+
+	retry := newStoryNFT(t, bc, "story-1", "nft-1b", 40)
+	retry.Edition = 1
+	commitTransactions(t, bc, newProposeMintTx(t, bobPriv, "bob", retry, types.NowUnix()+3600))
+	if proposals := bc.MintProposalsByStory("story-1"); len(proposals) != 1 || proposals[0].NFT.TokenID != "nft-1b" {
+		t.Fatalf("expected a new proposal once the stale one is dropped, got %+v", proposals)
+	}
+}
+
+func TestMintThresholdFromStoryRules(t *testing.T) {
+	now := int64(1000)
+	originalNow := types.NowUnix
+	types.NowUnix = func() int64 { return now }
+	t.Cleanup(func() { types.NowUnix = originalNow })
+
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Low bar",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{MintThreshold: 4000, MintWhileOpen: true},
+		CreatedAt: 20,
+	}}))
+	commitTransactions(t, bc,
+		newContributionTx(t, alicePriv, alice, "story-1", "Alice writes", 25),
+		newContributionTx(t, bobPriv, bob, "story-1", "Bob writes", 25))
+
+	if story, _ := bc.GetStory("story-1"); MintThreshold(story) != 4000 {
+		t.Fatalf("expected the rule's threshold, got %d", MintThreshold(story))
+	}
+
+	// Half of the shares reach the story's threshold on their own.
+	nft := newStoryNFT(t, bc, "story-1", "nft-1", 30)
+	nft.Edition = 1
+	commitTransactions(t, bc, newProposeMintTx(t, bobPriv, "bob", nft, 2000))
+	if _, ok := bc.GetNFT("nft-1"); !ok {
+		t.Fatalf("expected the proposal to mint at once")
+	}
+}
@@ -0,0 +1,120 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+
+	"storytelling-blockchain/internal/types"
+)
+
+// Mint triggers a story can configure through its rules.
+const (
+	MintTriggerContributions = "contributions"
+	MintTriggerAuthors       = "authors"
+	MintTriggerClose         = "close"
+)
+
+var (
+	errMintTriggerNotDue  = errors.New("blockchain: mint trigger not reached or already fired")
+	errMintWithoutTrigger = errors.New("blockchain: mint_nft must fire a mint trigger, authors mint through propose_mint")
+)
+
+// Exported errors for automatic mints. ErrMintTriggerNotDue is returned for
+// mints naming a trigger the story has not reached, or one that already
+// minted an edition; ErrMintWithoutTrigger for a mint_nft naming none.
+var (
+	ErrMintTriggerNotDue  = errMintTriggerNotDue
+	ErrMintWithoutTrigger = errMintWithoutTrigger
+)
+
+// DueMintTriggers returns the triggers of the story's rules that are reached
+// and have not fired yet, in a fixed order.
+func DueMintTriggers(story types.StoryRecord) []string {
+	rules := story.Rules
+
+	var due []string
+	if rules.MintAtContributions > 0 && story.Progress.LineCount >= rules.MintAtContributions {
+		due = append(due, MintTriggerContributions)
+	}
+	if rules.MintAtAuthors > 0 && len(story.Progress.Contributors) >= rules.MintAtAuthors {
+		due = append(due, MintTriggerAuthors)
+	}
+	if rules.MintOnClose && story.Status == types.StoryStatusClosed {
+		due = append(due, MintTriggerClose)
+	}
+
+	fired := story.FiredMintTriggers
+	pending := due[:0]
+	for _, trigger := range due {
+		i := sort.SearchStrings(fired, trigger)
+		if i < len(fired) && fired[i] == trigger {
+			continue
+		}
+		pending = append(pending, trigger)
+	}
+	return pending
+}
+
+// checkMintTriggers makes sure every trigger of an automatic mint is due.
+func checkMintTriggers(story types.StoryRecord, triggers []string) error {
+	due := DueMintTriggers(story)
+	seen := make(map[string]bool, len(triggers))
+	for _, trigger := range triggers {
+		if seen[trigger] || !containsString(due, trigger) {
+			return errMintTriggerNotDue
+		}
+		seen[trigger] = true
+	}
+	return nil
+}
+
+// fireMintTriggers records that the triggers minted an edition. The list is
+// rebuilt rather than appended to because cloned states share it.
+func fireMintTriggers(story types.StoryRecord, triggers []string) types.StoryRecord {
+	fired := make([]string, 0, len(story.FiredMintTriggers)+len(triggers))
+	fired = append(fired, story.FiredMintTriggers...)
+	fired = append(fired, triggers...)
+	sort.Strings(fired)
+	story.FiredMintTriggers = fired
+	return story
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// DueMint is a story whose mint triggers are due.
+type DueMint struct {
+	StoryID  string   `json:"story_id"`
+	Triggers []string `json:"triggers"`
+}
+
+// DueMints returns the stories with due mint triggers, ordered by story ID.
+// Stories waiting on a mint proposal are left out until it settles.
+func (bc *Blockchain) DueMints() []DueMint {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	proposed := make(map[string]bool, len(bc.state.MintProposals))
+	for _, proposal := range bc.state.MintProposals {
+		proposed[proposal.NFT.StoryID] = true
+	}
+
+	var due []DueMint
+	for id, story := range bc.state.StoryRegistry {
+		if proposed[id] {
+			continue
+		}
+		if triggers := DueMintTriggers(story); len(triggers) > 0 {
+			due = append(due, DueMint{StoryID: id, Triggers: triggers})
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].StoryID < due[j].StoryID })
+	return due
+}
@@ -0,0 +1,111 @@
+package blockchain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestDueMintTriggers(t *testing.T) {
+	story := types.StoryRecord{
+		Rules:    types.StoryRules{MintAtContributions: 3, MintAtAuthors: 2, MintOnClose: true},
+		Status:   types.StoryStatusOpen,
+		Progress: types.StoryProgress{LineCount: 2, Contributors: []string{"alice"}},
+	}
+	if due := DueMintTriggers(story); len(due) != 0 {
+		t.Fatalf("expected no due triggers, got %v", due)
+	}
+
+	story.Progress = types.StoryProgress{LineCount: 3, Contributors: []string{"alice", "bob"}}
+	story.Status = types.StoryStatusClosed
+	if due := DueMintTriggers(story); !reflect.DeepEqual(due, []string{MintTriggerContributions, MintTriggerAuthors, MintTriggerClose}) {
+		t.Fatalf("unexpected due triggers: %v", due)
+	}
+
+	story.FiredMintTriggers = []string{MintTriggerAuthors, MintTriggerContributions}
+	if due := DueMintTriggers(story); !reflect.DeepEqual(due, []string{MintTriggerClose}) {
+		t.Fatalf("expected fired triggers to be skipped, got %v", due)
+	}
+}
+
+func TestTriggeredMintFiresOnce(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Milestones",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{MintAtContributions: 2, MintWhileOpen: true},
+		CreatedAt: 20,
+	}}))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "One", 30))
+
+	triggered := func(tokenID string, edition int, supersedes string) types.Transaction {
+		nft := newStoryNFT(t, bc, "story-1", tokenID, 40)
+		nft.Edition = edition
+		nft.Supersedes = supersedes
+		tx, err := NewTransaction(TxTypeMintNFT, 40, types.MintNFTPayload{NFT: nft, Triggers: []string{MintTriggerContributions}})
+		if err != nil {
+			t.Fatalf("build mint: %v", err)
+		}
+		return tx
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{triggered("nft-1", 1, "")}); !errors.Is(err, ErrMintTriggerNotDue) {
+		t.Fatalf("expected a mint before the milestone to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Two", 35))
+	if due := bc.DueMints(); len(due) != 1 || due[0].StoryID != "story-1" {
+		t.Fatalf("expected the story to be due, got %+v", due)
+	}
+
+	commitTransactions(t, bc, triggered("nft-1", 1, ""))
+	if story, _ := bc.GetStory("story-1"); story.Edition != 1 || !reflect.DeepEqual(story.FiredMintTriggers, []string{MintTriggerContributions}) {
+		t.Fatalf("expected the trigger to fire, got %+v", story)
+	}
+	if due := bc.DueMints(); len(due) != 0 {
+		t.Fatalf("expected nothing due after the mint, got %+v", due)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{triggered("nft-2", 2, "nft-1")}); !errors.Is(err, ErrMintTriggerNotDue) {
+		t.Fatalf("expected the trigger to fire only once, got %v", err)
+	}
+}
+
+func TestTriggeredMintRequiresMainAuthorAsOwner(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	mallory, _ := newKeyedWallet(t, "mallory")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, mallory, 10))
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Milestones",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{MintAtContributions: 1, MintWhileOpen: true},
+		CreatedAt: 20,
+	}}))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "One", 30))
+
+	// Anyone may submit a due mint, but not name themselves as its owner.
+	nft := newStoryNFT(t, bc, "story-1", "nft-1", 40)
+	nft.Edition = 1
+	nft.OwnerID, nft.OwnerAddress = "mallory", mallory.Address
+	tx, err := NewTransaction(TxTypeMintNFT, 40, types.MintNFTPayload{NFT: nft, Triggers: []string{MintTriggerContributions}})
+	if err != nil {
+		t.Fatalf("build mint: %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{tx}); !errors.Is(err, ErrMintOwnerMismatch) {
+		t.Fatalf("expected a mint owned by another wallet to fail, got %v", err)
+	}
+
+	// Nor may a proposal.
+	if _, err := bc.BuildBlock([]types.Transaction{newProposeMintTx(t, alicePriv, "alice", nft, types.NowUnix()+3600)}); !errors.Is(err, ErrMintOwnerMismatch) {
+		t.Fatalf("expected a proposal owned by another wallet to fail, got %v", err)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
 
 	"storytelling-blockchain/internal/storage"
 	"storytelling-blockchain/internal/types"
@@ -45,7 +44,17 @@ func MintNFT(story types.Story, ipfs storage.IPFSClient) (types.NFT, error) {
 		return types.NFT{}, errNoContributions
 	}
 
-	authors := AggregateAuthors(contributions)
+	policy, err := AuthorshipPolicyByName(story.AuthorshipPolicy)
+	if err != nil {
+		return types.NFT{}, err
+	}
+
+	if story.Edition == 0 {
+		story.Edition = 1
+	}
+
+	mintedAt := types.NowUnix()
+	authors := StoryAuthors(story, policy, mintedAt)
 	if len(authors) == 0 {
 		return types.NFT{}, errNoContributions
 	}
@@ -55,25 +64,29 @@ func MintNFT(story types.Story, ipfs storage.IPFSClient) (types.NFT, error) {
 		return types.NFT{}, err
 	}
 
-	metadataCID, err := uploadMetadata(story, authors, imageCID, ipfs)
+	metadataCID, err := uploadMetadata(story, authors, policy.Name(), imageCID, mintedAt, ipfs)
 	if err != nil {
 		return types.NFT{}, err
 	}
 
 	tokenID := fmt.Sprintf("nft_%s_%s", story.ID, utils.ComputeSHA256([]byte(metadataCID))[:12])
-	mintedAt := types.NowUnix()
 
 	nft := types.NFT{
-		TokenID:         tokenID,
-		StoryID:         story.ID,
-		Title:           story.Title,
-		Summary:         story.Summary,
-		MainAuthor:      authors[0],
-		CoAuthors:       authors[1:],
-		ImageIPFSCID:    imageCID,
-		MetadataIPFSCID: metadataCID,
-		MintedAt:        mintedAt,
-		BlockIndex:      -1,
+		TokenID:          tokenID,
+		StoryID:          story.ID,
+		Title:            story.Title,
+		Summary:          story.Summary,
+		MainAuthor:       authors[0],
+		CoAuthors:        authors[1:],
+		ImageIPFSCID:     imageCID,
+		MetadataIPFSCID:  metadataCID,
+		OwnerID:          authors[0].SupabaseUserID,
+		OwnerAddress:     authors[0].WalletAddress,
+		AuthorshipPolicy: policy.Name(),
+		Edition:          story.Edition,
+		Supersedes:       story.Supersedes,
+		MintedAt:         mintedAt,
+		BlockIndex:       -1,
 	}
 
 	return nft, nil
@@ -117,64 +130,40 @@ func GenerateNFTImage(story types.Story, ipfs storage.IPFSClient) (string, error
 	return cid, nil
 }
 
-func uploadMetadata(story types.Story, authors []types.Author, imageCID string, ipfs storage.IPFSClient) (string, error) {
+func uploadMetadata(story types.Story, authors []types.Author, policy, imageCID string, mintedAt int64, ipfs storage.IPFSClient) (string, error) {
 	metadata := struct {
-		StoryID       string               `json:"story_id"`
-		Title         string               `json:"title"`
-		Summary       string               `json:"summary"`
-		ImageCID      string               `json:"image_cid"`
-		Authors       []types.Author       `json:"authors"`
-		Contributions []types.Contribution `json:"contributions"`
-		MintedAt      int64                `json:"minted_at"`
-		TokenHint     string               `json:"token_hint"`
+		StoryID          string               `json:"story_id"`
+		Title            string               `json:"title"`
+		Summary          string               `json:"summary"`
+		ImageCID         string               `json:"image_cid"`
+		AuthorshipPolicy string               `json:"authorship_policy"`
+		Lineage          *types.StoryLineage  `json:"lineage,omitempty"`
+		Edition          int                  `json:"edition"`
+		Supersedes       string               `json:"supersedes,omitempty"`
+		Authors          []types.Author       `json:"authors"`
+		Contributions    []types.Contribution `json:"contributions"`
+		MintedAt         int64                `json:"minted_at"`
+		TokenHint        string               `json:"token_hint"`
 	}{
-		StoryID:       story.ID,
-		Title:         story.Title,
-		Summary:       story.Summary,
-		ImageCID:      imageCID,
-		Authors:       authors,
-		Contributions: story.Contributions,
-		MintedAt:      types.NowUnix(),
-		TokenHint:     utils.ComputeSHA256([]byte(story.ID + story.Title))[:16],
+		StoryID:          story.ID,
+		Title:            story.Title,
+		Summary:          story.Summary,
+		ImageCID:         imageCID,
+		AuthorshipPolicy: policy,
+		Lineage:          story.Lineage,
+		Edition:          story.Edition,
+		Supersedes:       story.Supersedes,
+		Authors:          authors,
+		Contributions:    story.Contributions,
+		MintedAt:         mintedAt,
+		TokenHint:        utils.ComputeSHA256([]byte(story.ID + story.Title))[:16],
 	}
 
 	return ipfs.UploadJSON(metadata)
 }
 
+// AggregateAuthors weighs every contribution equally. It is the count policy
+// of AggregateAuthorsWithPolicy.
 func AggregateAuthors(contributions []types.Contribution) []types.Author {
-	if len(contributions) == 0 {
-		return nil
-	}
-
-	total := len(contributions)
-	counts := make(map[string]*types.Author)
-
-	for _, c := range contributions {
-		author, ok := counts[c.ContributorID]
-		if !ok {
-			author = &types.Author{
-				SupabaseUserID: c.ContributorID,
-				WalletAddress:  c.WalletAddress,
-			}
-			counts[c.ContributorID] = author
-		}
-
-		author.ContributionCount++
-		author.GithubCommitStyleContributions = append(author.GithubCommitStyleContributions, c)
-	}
-
-	authors := make([]types.Author, 0, len(counts))
-	for _, author := range counts {
-		author.OwnershipPercentage = float64(author.ContributionCount) / float64(total) * 100
-		authors = append(authors, *author)
-	}
-
-	sort.Slice(authors, func(i, j int) bool {
-		if authors[i].ContributionCount == authors[j].ContributionCount {
-			return authors[i].SupabaseUserID < authors[j].SupabaseUserID
-		}
-		return authors[i].ContributionCount > authors[j].ContributionCount
-	})
-
-	return authors
+	return AggregateAuthorsWithPolicy(contributions, countPolicy{}, 0)
 }
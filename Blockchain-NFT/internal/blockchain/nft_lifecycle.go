@@ -0,0 +1,256 @@
+package blockchain
+
+import (
+	"errors"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/canonical"
+	"storytelling-blockchain/pkg/utils"
+)
+
+var (
+	errNFTBurned                = errors.New("blockchain: nft is burned")
+	errMetadataFrozen           = errors.New("blockchain: nft metadata is frozen")
+	errMissingMetadataCID       = errors.New("blockchain: image and metadata cids required")
+	errMissingBurnReason        = errors.New("blockchain: moderation burn requires a reason")
+	errBurnNotAuthorized        = errors.New("blockchain: burn needs the owner's signature or a validator quorum")
+	errInvalidValidatorApproval = errors.New("blockchain: invalid validator burn approval")
+)
+
+// Exported errors for the NFT lifecycle.
+var (
+	ErrNFTBurned                = errNFTBurned
+	ErrMetadataFrozen           = errMetadataFrozen
+	ErrBurnNotAuthorized        = errBurnNotAuthorized
+	ErrInvalidValidatorApproval = errInvalidValidatorApproval
+)
+
+// BurnApprovalBytes is what validators sign to approve the moderation burn
+// of a token on the chain.
+func BurnApprovalBytes(chainID, tokenID, reason string) []byte {
+	approval := canonical.NewEncoder(canonical.TagBurnApproval).
+		String(tokenID).
+		String(reason).
+		Encoded()
+	return canonical.SigningBytes(canonical.PurposeBurnApproval, chainID, approval)
+}
+
+// ValidatorQuorum is the number of validators out of n that must approve a
+// moderation burn: 2f+1 for the largest f with 3f+1 <= n, as in PBFT.
+func ValidatorQuorum(n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return n - (n-1)/3
+}
+
+// activeNFT returns a token that has not been burned.
+func activeNFT(state types.State, tokenID string) (types.NFT, error) {
+	if tokenID == "" {
+		return types.NFT{}, errMissingTokenID
+	}
+
+	nft, ok := state.NFTRegistry[tokenID]
+	if !ok {
+		return types.NFT{}, errUnknownToken
+	}
+
+	if nft.Status == types.NFTStatusBurned {
+		return types.NFT{}, errNFTBurned
+	}
+
+	return nft, nil
+}
+
+// checkOwnerAction validates a lifecycle transaction signed by the owner of
+// an active token.
+func checkOwnerAction(state types.State, tx types.Transaction, tokenID, ownerID string) (types.NFT, error) {
+	if tx.Signature == "" {
+		return types.NFT{}, errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return types.NFT{}, errMissingTimestamp
+	}
+
+	nft, err := activeNFT(state, tokenID)
+	if err != nil {
+		return types.NFT{}, err
+	}
+
+	if ownerID == "" || ownerID != nft.OwnerID {
+		return types.NFT{}, errNotTokenOwner
+	}
+
+	owner, ok := state.WalletRegistry[ownerID]
+	if !ok {
+		return types.NFT{}, errMissingWallet
+	}
+
+	return nft, verifyWalletSignature(state.ChainID, owner, tx)
+}
+
+// checkValidatorQuorum verifies the validator approvals of a moderation burn.
+func checkValidatorQuorum(state types.State, burn types.BurnNFTPayload) error {
+	if burn.Reason == "" {
+		return errMissingBurnReason
+	}
+
+	message := BurnApprovalBytes(state.ChainID, burn.TokenID, burn.Reason)
+	for validatorID, signature := range burn.ValidatorApprovals {
+		validator, ok := state.Validators[validatorID]
+		if !ok || validator.PublicKey == "" {
+			return errInvalidValidatorApproval
+		}
+		if ok, err := utils.VerifyEd25519(validator.PublicKey, message, signature); err != nil || !ok {
+			return errInvalidValidatorApproval
+		}
+	}
+
+	quorum := ValidatorQuorum(len(state.Validators))
+	if quorum == 0 || len(burn.ValidatorApprovals) < quorum {
+		return errBurnNotAuthorized
+	}
+
+	return nil
+}
+
+// burnNFTHandler retires a token. The NFT stays in the registry, marked
+// burned, and can no longer be transferred or changed; its share balances
+// are kept as a record.
+type burnNFTHandler struct{}
+
+func (burnNFTHandler) Type() string { return TxTypeBurnNFT }
+
+func (burnNFTHandler) Signer(payload interface{}) string {
+	return payload.(types.BurnNFTPayload).OwnerID
+}
+
+func (burnNFTHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.BurnNFTPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (burnNFTHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	burn := decoded.(types.BurnNFTPayload)
+
+	if burn.OwnerID != "" {
+		if len(burn.ValidatorApprovals) > 0 {
+			return errBurnNotAuthorized
+		}
+		_, err := checkOwnerAction(state, tx, burn.TokenID, burn.OwnerID)
+		return err
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if _, err := activeNFT(state, burn.TokenID); err != nil {
+		return err
+	}
+
+	return checkValidatorQuorum(state, burn)
+}
+
+func (burnNFTHandler) Apply(ctx TxContext, state *types.State, tx types.Transaction, decoded interface{}) error {
+	burn := decoded.(types.BurnNFTPayload)
+
+	nft := state.NFTRegistry[burn.TokenID]
+	nft.Status = types.NFTStatusBurned
+	nft.BurnedAt = tx.Timestamp
+	nft.BurnReason = burn.Reason
+	SetStateEntry(ctx, StateKeyNFT, state.NFTRegistry, burn.TokenID, nft)
+	return nil
+}
+
+// freezeMetadataHandler pins the metadata CIDs of a token for good.
+type freezeMetadataHandler struct{}
+
+func (freezeMetadataHandler) Type() string { return TxTypeFreezeMetadata }
+
+func (freezeMetadataHandler) Signer(payload interface{}) string {
+	return payload.(types.FreezeMetadataPayload).OwnerID
+}
+
+func (freezeMetadataHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.FreezeMetadataPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (freezeMetadataHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	freeze := decoded.(types.FreezeMetadataPayload)
+
+	nft, err := checkOwnerAction(state, tx, freeze.TokenID, freeze.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if nft.MetadataFrozen {
+		return errMetadataFrozen
+	}
+
+	return nil
+}
+
+func (freezeMetadataHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	freeze := decoded.(types.FreezeMetadataPayload)
+
+	nft := state.NFTRegistry[freeze.TokenID]
+	nft.MetadataFrozen = true
+	SetStateEntry(ctx, StateKeyNFT, state.NFTRegistry, freeze.TokenID, nft)
+	return nil
+}
+
+// updateMetadataHandler points a token whose metadata is not frozen at new
+// IPFS content.
+type updateMetadataHandler struct{}
+
+func (updateMetadataHandler) Type() string { return TxTypeUpdateMetadata }
+
+func (updateMetadataHandler) Signer(payload interface{}) string {
+	return payload.(types.UpdateMetadataPayload).OwnerID
+}
+
+func (updateMetadataHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.UpdateMetadataPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (updateMetadataHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	update := decoded.(types.UpdateMetadataPayload)
+
+	nft, err := checkOwnerAction(state, tx, update.TokenID, update.OwnerID)
+	if err != nil {
+		return err
+	}
+
+	if nft.MetadataFrozen {
+		return errMetadataFrozen
+	}
+
+	if update.ImageIPFSCID == "" || update.MetadataIPFSCID == "" {
+		return errMissingMetadataCID
+	}
+
+	return nil
+}
+
+func (updateMetadataHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	update := decoded.(types.UpdateMetadataPayload)
+
+	nft := state.NFTRegistry[update.TokenID]
+	nft.ImageIPFSCID = update.ImageIPFSCID
+	nft.MetadataIPFSCID = update.MetadataIPFSCID
+	SetStateEntry(ctx, StateKeyNFT, state.NFTRegistry, update.TokenID, nft)
+	return nil
+}
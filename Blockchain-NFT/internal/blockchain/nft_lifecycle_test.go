@@ -0,0 +1,144 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+func newBurnApproval(t *testing.T, priv, tokenID, reason string) string {
+	t.Helper()
+
+	signature, err := utils.SignEd25519(priv, BurnApprovalBytes(DefaultChainID, tokenID, reason))
+	if err != nil {
+		t.Fatalf("sign burn approval: %v", err)
+	}
+	return signature
+}
+
+func TestValidatorQuorum(t *testing.T) {
+	for n, want := range map[int]int{0: 0, 1: 1, 3: 3, 4: 3, 5: 4, 7: 5} {
+		if got := ValidatorQuorum(n); got != want {
+			t.Fatalf("ValidatorQuorum(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestNFTOwnerLifecycle(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, newOpenMintStoryTx(t, alicePriv, "alice", "story-1", 20))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Once upon a time", 25))
+
+	_, mint := newEditionTx(t, bc, alicePriv, "nft-1", 1, "", 30)
+	commitTransactions(t, bc, mint)
+
+	nft, ok := bc.GetNFT("nft-1")
+	if !ok || nft.Status != types.NFTStatusActive || nft.MetadataFrozen {
+		t.Fatalf("expected an active nft, got %+v", nft)
+	}
+
+	update := types.UpdateMetadataPayload{TokenID: "nft-1", OwnerID: "alice", ImageIPFSCID: "image-2", MetadataIPFSCID: "meta-2"}
+	if _, err := bc.BuildBlock([]types.Transaction{signTestTx(t, bobPriv, TxTypeUpdateMetadata, 40, types.UpdateMetadataPayload{TokenID: "nft-1", OwnerID: "bob", ImageIPFSCID: "x", MetadataIPFSCID: "y"})}); !errors.Is(err, errNotTokenOwner) {
+		t.Fatalf("expected update by a non-owner to fail, got %v", err)
+	}
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeUpdateMetadata, 40, update))
+	if nft, _ := bc.GetNFT("nft-1"); nft.ImageIPFSCID != "image-2" || nft.MetadataIPFSCID != "meta-2" {
+		t.Fatalf("expected updated metadata, got %+v", nft)
+	}
+
+	freeze := types.FreezeMetadataPayload{TokenID: "nft-1", OwnerID: "alice"}
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeFreezeMetadata, 50, freeze))
+	if nft, _ := bc.GetNFT("nft-1"); !nft.MetadataFrozen {
+		t.Fatalf("expected frozen metadata, got %+v", nft)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{signTestTx(t, alicePriv, TxTypeFreezeMetadata, 55, freeze)}); !errors.Is(err, ErrMetadataFrozen) {
+		t.Fatalf("expected a second freeze to fail, got %v", err)
+	}
+	update.ImageIPFSCID = "image-3"
+	if _, err := bc.BuildBlock([]types.Transaction{signTestTx(t, alicePriv, TxTypeUpdateMetadata, 55, update)}); !errors.Is(err, ErrMetadataFrozen) {
+		t.Fatalf("expected update of frozen metadata to fail, got %v", err)
+	}
+
+	burn := types.BurnNFTPayload{TokenID: "nft-1", OwnerID: "alice", Reason: "withdrawn"}
+	if _, err := bc.BuildBlock([]types.Transaction{signTestTx(t, bobPriv, TxTypeBurnNFT, 60, burn)}); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected burn signed by another wallet to fail, got %v", err)
+	}
+	burnTx := signTestTx(t, alicePriv, TxTypeBurnNFT, 60, burn)
+	commitTransactions(t, bc, burnTx)
+
+	nft, ok = bc.GetNFT("nft-1")
+	if !ok || nft.Status != types.NFTStatusBurned || nft.BurnedAt != 60 || nft.BurnReason != "withdrawn" {
+		t.Fatalf("expected the nft to stay in the registry as burned, got %+v", nft)
+	}
+	if history := bc.NFTHistory("nft-1"); len(history) != 2 || history[1].TxID != burnTx.TxID || history[1].FromID != "alice" {
+		t.Fatalf("expected the burn in the provenance, got %+v", history)
+	}
+
+	transfer := newTransferTx(t, alicePriv, 70, types.TransferNFTPayload{TokenID: "nft-1", FromID: "alice", ToID: "bob", ToAddress: bob.Address})
+	if _, err := bc.BuildBlock([]types.Transaction{transfer}); !errors.Is(err, ErrNFTBurned) {
+		t.Fatalf("expected transfer of a burned nft to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{signTestTx(t, alicePriv, TxTypeBurnNFT, 70, burn)}); !errors.Is(err, ErrNFTBurned) {
+		t.Fatalf("expected a second burn to fail, got %v", err)
+	}
+}
+
+func TestNFTModerationBurn(t *testing.T) {
+	validatorKeys := make(map[string]string)
+	genesis := DefaultGenesis()
+	for _, id := range []string{"node-1", "node-2", "node-3", "node-4"} {
+		pub, priv, err := utils.GenerateEd25519Keypair()
+		if err != nil {
+			t.Fatalf("generate keypair: %v", err)
+		}
+		validatorKeys[id] = priv
+		genesis.Validators = append(genesis.Validators, types.Validator{ID: id, PublicKey: pub})
+	}
+
+	bc, err := NewBlockchainFromGenesis(genesis)
+	if err != nil {
+		t.Fatalf("new blockchain: %v", err)
+	}
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+	commitTransactions(t, bc, newOpenMintStoryTx(t, alicePriv, "alice", "story-1", 20))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Once upon a time", 25))
+	_, mint := newEditionTx(t, bc, alicePriv, "nft-1", 1, "", 30)
+	commitTransactions(t, bc, mint)
+
+	const reason = "copyright claim"
+	burnTx := func(approvals map[string]string) types.Transaction {
+		tx, err := NewTransaction(TxTypeBurnNFT, 40, types.BurnNFTPayload{TokenID: "nft-1", Reason: reason, ValidatorApprovals: approvals})
+		if err != nil {
+			t.Fatalf("build burn: %v", err)
+		}
+		return tx
+	}
+
+	approvals := map[string]string{
+		"node-1": newBurnApproval(t, validatorKeys["node-1"], "nft-1", reason),
+		"node-2": newBurnApproval(t, validatorKeys["node-2"], "nft-1", reason),
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{burnTx(approvals)}); !errors.Is(err, ErrBurnNotAuthorized) {
+		t.Fatalf("expected burn short of the quorum to fail, got %v", err)
+	}
+
+	approvals["node-3"] = newBurnApproval(t, validatorKeys["node-3"], "nft-1", "another reason")
+	if _, err := bc.BuildBlock([]types.Transaction{burnTx(approvals)}); !errors.Is(err, ErrInvalidValidatorApproval) {
+		t.Fatalf("expected an approval of another reason to fail, got %v", err)
+	}
+
+	approvals["node-3"] = newBurnApproval(t, validatorKeys["node-3"], "nft-1", reason)
+	commitTransactions(t, bc, burnTx(approvals))
+
+	if nft, _ := bc.GetNFT("nft-1"); nft.Status != types.NFTStatusBurned || nft.BurnReason != reason {
+		t.Fatalf("expected the moderation burn to apply, got %+v", nft)
+	}
+}
@@ -0,0 +1,108 @@
+package blockchain
+
+import (
+	"errors"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errMissingTokenID       = errors.New("blockchain: nft token id required")
+	errMissingNFTOwner      = errors.New("blockchain: nft owner required")
+	errOwnerAddressMismatch = errors.New("blockchain: nft owner address does not match wallet")
+	errUnknownToken         = errors.New("blockchain: nft not found")
+	errNotTokenOwner        = errors.New("blockchain: sender does not own the nft")
+	errSelfTransfer         = errors.New("blockchain: nft already owned by recipient")
+)
+
+// Exported errors for transfer validation.
+var (
+	ErrUnknownToken  = errUnknownToken
+	ErrNotTokenOwner = errNotTokenOwner
+)
+
+// transferNFTHandler moves an NFT to another wallet on behalf of its current owner.
+type transferNFTHandler struct{}
+
+func (transferNFTHandler) Type() string { return TxTypeTransferNFT }
+
+func (transferNFTHandler) Signer(payload interface{}) string {
+	return payload.(types.TransferNFTPayload).FromID
+}
+
+func (transferNFTHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.TransferNFTPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (transferNFTHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	transfer := decoded.(types.TransferNFTPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	nft, err := activeNFT(state, transfer.TokenID)
+	if err != nil {
+		return err
+	}
+
+	if transfer.FromID == "" || transfer.FromID != nft.OwnerID {
+		return errNotTokenOwner
+	}
+
+	if transfer.ToID == transfer.FromID {
+		return errSelfTransfer
+	}
+
+	owner, ok := state.WalletRegistry[transfer.FromID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	recipient, ok := state.WalletRegistry[transfer.ToID]
+	if !ok {
+		return errMissingWallet
+	}
+	if recipient.Address != transfer.ToAddress {
+		return errOwnerAddressMismatch
+	}
+
+	return verifyWalletSignature(state.ChainID, owner, tx)
+}
+
+func (transferNFTHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	transfer := decoded.(types.TransferNFTPayload)
+
+	nft := state.NFTRegistry[transfer.TokenID]
+	nft.OwnerID = transfer.ToID
+	nft.OwnerAddress = transfer.ToAddress
+	SetStateEntry(ctx, StateKeyNFT, state.NFTRegistry, transfer.TokenID, nft)
+	return nil
+}
+
+// NFTHistory returns the provenance chain of a token, from its mint through every transfer.
+func (bc *Blockchain) NFTHistory(tokenID string) []types.NFTProvenance {
+	if tokenID == "" {
+		return nil
+	}
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	indexed := bc.nftHistory[tokenID]
+	if len(indexed) == 0 {
+		return nil
+	}
+
+	history := make([]types.NFTProvenance, len(indexed))
+	copy(history, indexed)
+	return history
+}
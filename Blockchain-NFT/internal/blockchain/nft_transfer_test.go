@@ -0,0 +1,159 @@
+package blockchain
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+func newKeyedWallet(t *testing.T, userID string) (types.Wallet, string) {
+	t.Helper()
+
+	pub, priv, err := utils.GenerateEd25519Keypair()
+	if err != nil {
+		t.Fatalf("generate keypair: %v", err)
+	}
+
+	return types.Wallet{
+		Address:             "0x" + userID,
+		SupabaseUserID:      userID,
+		PublicKey:           pub,
+		PrivateKeyEncrypted: "enc",
+		CreatedAt:           1,
+	}, priv
+}
+
+func commitTransactions(t *testing.T, bc *Blockchain, txs ...types.Transaction) types.Block {
+	t.Helper()
+
+	block, err := bc.BuildBlock(txs)
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+	testNonces.committed(block.Transactions)
+	return block
+}
+
+// testNonces numbers the transactions signed by test keys. A key signs with
+// the nonce after its last transaction committed through commitTransactions,
+// so transactions a test expects to be rejected do not use one up. Blocks
+// holding several transactions of one key sign them with explicit nonces.
+var testNonces = &nonceTracker{last: make(map[string]int64), keys: make(map[string]string)}
+
+type nonceTracker struct {
+	mu   sync.Mutex
+	last map[string]int64  // last committed nonce by private key
+	keys map[string]string // private key by transaction ID
+}
+
+func (n *nonceTracker) next(priv string) int64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.last[priv] + 1
+}
+
+func (n *nonceTracker) signed(priv string, tx types.Transaction) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.keys[tx.TxID] = priv
+}
+
+func (n *nonceTracker) committed(txs []types.Transaction) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for _, tx := range txs {
+		if priv, ok := n.keys[tx.TxID]; ok && tx.Nonce > n.last[priv] {
+			n.last[priv] = tx.Nonce
+		}
+	}
+}
+
+func newTransferTx(t *testing.T, priv string, timestamp int64, payload types.TransferNFTPayload) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeTransferNFT, timestamp, payload)
+}
+
+func TestTransferNFTUpdatesOwnerAndHistory(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 15))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "The end", 15))
+	commitTransactions(t, bc, newCloseStoryTx(t, alicePriv, "alice", "story-1", 16))
+
+	nft := newStoryNFT(t, bc, "story-1", "nft-1", 20)
+	nft.Title = "Tale"
+	nft.Edition = 1
+	mintTx := newProposeMintTx(t, alicePriv, "alice", nft, types.NowUnix()+3600)
+	commitTransactions(t, bc, mintTx)
+
+	forged := newTransferTx(t, bobPriv, 30, types.TransferNFTPayload{TokenID: "nft-1", FromID: "alice", ToID: "bob", ToAddress: bob.Address})
+	if _, err := bc.BuildBlock([]types.Transaction{forged}); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected transfer signed by non-owner to fail, got %v", err)
+	}
+
+	notOwner := newTransferTx(t, bobPriv, 30, types.TransferNFTPayload{TokenID: "nft-1", FromID: "bob", ToID: "alice", ToAddress: alice.Address})
+	if _, err := bc.BuildBlock([]types.Transaction{notOwner}); !errors.Is(err, ErrNotTokenOwner) {
+		t.Fatalf("expected non-owner transfer to fail, got %v", err)
+	}
+
+	transfer := newTransferTx(t, alicePriv, 30, types.TransferNFTPayload{TokenID: "nft-1", FromID: "alice", ToID: "bob", ToAddress: bob.Address})
+	transferBlock := commitTransactions(t, bc, transfer)
+
+	owned, ok := bc.GetNFT("nft-1")
+	if !ok || owned.OwnerID != "bob" || owned.OwnerAddress != bob.Address {
+		t.Fatalf("expected bob to own the nft, got %+v", owned)
+	}
+
+	replay := newTransferTx(t, alicePriv, 31, types.TransferNFTPayload{TokenID: "nft-1", FromID: "alice", ToID: "bob", ToAddress: bob.Address})
+	if _, err := bc.BuildBlock([]types.Transaction{replay}); !errors.Is(err, ErrNotTokenOwner) {
+		t.Fatalf("expected previous owner to lose transfer rights, got %v", err)
+	}
+
+	history := bc.NFTHistory("nft-1")
+	if len(history) != 2 {
+		t.Fatalf("expected mint and transfer in history, got %+v", history)
+	}
+
+	if history[0].Type != TxTypeProposeMint || history[0].ToID != "alice" || history[0].TxID != mintTx.TxID {
+		t.Fatalf("unexpected mint provenance: %+v", history[0])
+	}
+
+	want := types.NFTProvenance{
+		TxID:        transfer.TxID,
+		Type:        TxTypeTransferNFT,
+		FromID:      "alice",
+		FromAddress: alice.Address,
+		ToID:        "bob",
+		ToAddress:   bob.Address,
+		BlockIndex:  transferBlock.Index,
+		Timestamp:   30,
+	}
+	if history[1] != want {
+		t.Fatalf("unexpected transfer provenance: %+v", history[1])
+	}
+}
+
+func TestMintNFTRequiresRegisteredOwner(t *testing.T) {
+	bc := NewBlockchain()
+
+	nft := types.NFT{TokenID: "nft-1", StoryID: "story-1", OwnerID: "ghost", OwnerAddress: "0xghost", MintedAt: 20}
+	mintTx, err := NewTransaction(TxTypeMintNFT, nft.MintedAt, types.MintNFTPayload{NFT: nft, Triggers: []string{MintTriggerClose}})
+	if err != nil {
+		t.Fatalf("build mint: %v", err)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{mintTx}); !errors.Is(err, errMissingWallet) {
+		t.Fatalf("expected unregistered owner to be rejected, got %v", err)
+	}
+}
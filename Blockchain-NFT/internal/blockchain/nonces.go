@@ -0,0 +1,84 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errNonceReplay     = errors.New("blockchain: nonce already used")
+	errNonceGap        = errors.New("blockchain: nonce skips ahead of the next nonce")
+	errUnexpectedNonce = errors.New("blockchain: transaction not signed by a wallet cannot carry a nonce")
+	errTxCommitted     = errors.New("blockchain: transaction already committed")
+)
+
+// Exported errors for replay protection.
+var (
+	ErrNonceReplay = errNonceReplay
+	ErrNonceGap    = errNonceGap
+	ErrTxCommitted = errTxCommitted
+)
+
+// SignedTxHandler is implemented by handlers whose transactions are signed by
+// a wallet. Signer returns the Supabase user ID of the signing wallet for a
+// decoded payload, or "" when that transaction carries no wallet signature.
+// Transactions of other handlers must not carry a nonce.
+type SignedTxHandler interface {
+	Signer(payload interface{}) string
+}
+
+func txSigner(handler TransactionHandler, payload interface{}) string {
+	signed, ok := handler.(SignedTxHandler)
+	if !ok {
+		return ""
+	}
+	return signed.Signer(payload)
+}
+
+// checkNonce makes sure a signed transaction carries the next nonce of its
+// signer, so that it can be committed once and in order.
+func checkNonce(state types.State, signer string, tx types.Transaction) error {
+	if signer == "" {
+		if tx.Nonce != 0 {
+			return errUnexpectedNonce
+		}
+		return nil
+	}
+
+	next := state.Nonces[signer] + 1
+	switch {
+	case tx.Nonce < next:
+		return fmt.Errorf("%w: got %d, want %d", errNonceReplay, tx.Nonce, next)
+	case tx.Nonce > next:
+		return fmt.Errorf("%w: got %d, want %d", errNonceGap, tx.Nonce, next)
+	}
+
+	return nil
+}
+
+// NextNonce returns the nonce the next signed transaction of userID must
+// carry, counting the transactions of userID waiting in the mempool.
+func (bc *Blockchain) NextNonce(userID string) int64 {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	next := bc.state.Nonces[userID] + 1
+	if pending, ok := bc.mempool.LastNonce(userID); ok && pending >= next {
+		next = pending + 1
+	}
+	return next
+}
+
+// checkUncommittedLocked rejects transactions whose ID was committed in an
+// earlier block. Nonces only cover signed transactions; this also stops the
+// unsigned ones, such as create_wallet, from being replayed.
+func (bc *Blockchain) checkUncommittedLocked(txs []types.Transaction) error {
+	for _, tx := range txs {
+		if index, ok := bc.txBlocks[tx.TxID]; ok {
+			return fmt.Errorf("%w: %s in block %d", errTxCommitted, tx.TxID, index)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestWalletNonces(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+	if got := bc.NextNonce("alice"); got != 1 {
+		t.Fatalf("expected a new wallet to start at nonce 1, got %d", got)
+	}
+
+	create := newCreateStoryTx(t, alicePriv, "alice", "story-1", 20)
+	if create.Nonce != 1 {
+		t.Fatalf("expected the first transaction to carry nonce 1, got %d", create.Nonce)
+	}
+	commitTransactions(t, bc, create)
+	if got := bc.NextNonce("alice"); got != 2 {
+		t.Fatalf("expected the nonce to advance, got %d", got)
+	}
+
+	line := func(nonce int64, text string) types.Transaction {
+		return signTestTxWithNonce(t, alicePriv, nonce, TxTypeContribution, 30, types.ContributionPayload{Contribution: types.Contribution{
+			ContributorID: "alice", WalletAddress: alice.Address, StoryID: "story-1", StoryLine: text, Timestamp: 30,
+		}})
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{line(3, "Too early")}); !errors.Is(err, ErrNonceGap) {
+		t.Fatalf("expected a nonce gap to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{line(1, "Reused")}); !errors.Is(err, ErrNonceReplay) {
+		t.Fatalf("expected a used nonce to fail, got %v", err)
+	}
+
+	first := line(2, "Once upon a time")
+	commitTransactions(t, bc, first)
+	if _, err := bc.BuildBlock([]types.Transaction{first}); !errors.Is(err, ErrTxCommitted) {
+		t.Fatalf("expected a replayed transaction to fail, got %v", err)
+	}
+
+	// Consecutive nonces may share a block, in order.
+	if _, err := bc.BuildBlock([]types.Transaction{line(4, "Fourth"), line(3, "Third")}); !errors.Is(err, ErrNonceGap) {
+		t.Fatalf("expected out of order nonces to fail, got %v", err)
+	}
+	commitTransactions(t, bc, line(3, "Third"), line(4, "Fourth"))
+	if got := bc.NextNonce("alice"); got != 5 {
+		t.Fatalf("expected nonce 5 after four transactions, got %d", got)
+	}
+
+	proof, err := bc.StateProof(bc.LatestBlock().Index, StateKeyNonce+"alice")
+	if err != nil || string(proof.Value) != "4" {
+		t.Fatalf("expected the nonce in the state tree, got %+v, %v", proof, err)
+	}
+
+	unsigned, err := NewSignedTransaction(TxTypeCreateWallet, 40, 1, types.CreateWalletPayload{Wallet: types.Wallet{Address: "0xbob", SupabaseUserID: "bob", PublicKey: alice.PublicKey, PrivateKeyEncrypted: "enc"}})
+	if err != nil {
+		t.Fatalf("build wallet: %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{unsigned}); !errors.Is(err, errUnexpectedNonce) {
+		t.Fatalf("expected a nonce on an unsigned transaction to fail, got %v", err)
+	}
+}
+
+func TestCommittedUnsignedTransactionCannotBeReplayed(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, _ := newKeyedWallet(t, "alice")
+	create := newCreateWalletTx(t, alice, 10)
+	commitTransactions(t, bc, create)
+
+	if _, err := bc.BuildBlock([]types.Transaction{create}); !errors.Is(err, ErrTxCommitted) {
+		t.Fatalf("expected a committed wallet creation to fail, got %v", err)
+	}
+	if err := bc.EnqueueTransaction(create); !errors.Is(err, ErrTxCommitted) {
+		t.Fatalf("expected the mempool to refuse a committed transaction, got %v", err)
+	}
+
+	// A block assembled without the chain's check must still be refused.
+	prev := bc.LatestBlock()
+	state := bc.State()
+	block, err := newBlockWithState(bc.registry, prev, &state, bc.stateTrees[len(bc.stateTrees)-1], []types.Transaction{create})
+	if err != nil {
+		t.Fatalf("build replay block: %v", err)
+	}
+	if err := bc.AddBlock(block); !errors.Is(err, ErrTxCommitted) {
+		t.Fatalf("expected the replay block to fail, got %v", err)
+	}
+
+	wallet, ok := bc.State().WalletRegistry["alice"]
+	if !ok || wallet.BlockIndex != 1 {
+		t.Fatalf("expected the wallet to stay at block 1, got %+v", wallet)
+	}
+	if receipt, ok := bc.Receipt(create.TxID); !ok || receipt.BlockIndex != 1 {
+		t.Fatalf("expected the receipt to stay at block 1, got %+v", receipt)
+	}
+}
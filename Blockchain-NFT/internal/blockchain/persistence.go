@@ -2,6 +2,8 @@ package blockchain
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"storytelling-blockchain/internal/storage"
 	"storytelling-blockchain/internal/types"
@@ -15,8 +17,30 @@ type BlockStateStore interface {
 	GetState() (types.State, error)
 }
 
+// PendingTxStore is implemented by stores that also keep the mempool, so that
+// queued transactions survive a restart. The chain uses it when the attached
+// BlockStateStore provides it.
+type PendingTxStore interface {
+	SavePendingTransaction(pending types.PendingTransaction) error
+	DeletePendingTransactions(txIDs ...string) error
+	PendingTransactions() ([]types.PendingTransaction, error)
+}
+
+// ReceiptStore is implemented by stores that also keep transaction receipts:
+// the index from transaction ID to the committing block, written with each
+// block, and the rejection receipts. The chain reads committed receipts from
+// the index and reloads rejections on boot when the attached BlockStateStore
+// provides it.
+type ReceiptStore interface {
+	GetTransactionBlockIndex(txID string) (int, error)
+	SaveRejectedReceipt(rejected types.RejectedReceipt) error
+	DeleteRejectedReceipts(txIDs ...string) error
+	RejectedReceipts() ([]types.RejectedReceipt, error)
+}
+
 // WithStorage attaches the provided persistence layer to the blockchain and
-// synchronises all existing blocks and state to disk.
+// synchronises all existing blocks, state, pending transactions and rejection
+// receipts to disk.
 func (bc *Blockchain) WithStorage(store BlockStateStore) error {
 	if store == nil {
 		return errors.New("blockchain: storage is nil")
@@ -34,29 +58,59 @@ func (bc *Blockchain) WithStorage(store BlockStateStore) error {
 		}
 	}
 
-	if err := store.SaveState(cloneStateMaps(bc.walletRegistry, bc.nftRegistry)); err != nil {
+	if err := store.SaveState(cloneState(bc.state)); err != nil {
 		bc.store = nil
 		return err
 	}
 
+	if pendingStore, ok := store.(PendingTxStore); ok {
+		queuedAt := time.Now().UnixNano()
+		for _, tx := range bc.mempool.Transactions() {
+			if err := pendingStore.SavePendingTransaction(types.PendingTransaction{Transaction: tx, QueuedAt: queuedAt}); err != nil {
+				bc.store = nil
+				return err
+			}
+		}
+	}
+
+	if receiptStore, ok := store.(ReceiptStore); ok {
+		for _, rejected := range bc.rejections.entries() {
+			if err := receiptStore.SaveRejectedReceipt(rejected); err != nil {
+				bc.store = nil
+				return err
+			}
+		}
+	}
+
 	return nil
 }
 
 // LoadBlockchain reconstructs a blockchain instance from the supplied storage
-// backend. When no blocks are present, a genesis block is created and persisted.
-func LoadBlockchain(store BlockStateStore) (*Blockchain, error) {
+// backend using the default development genesis.
+func LoadBlockchain(store BlockStateStore, opts ...Option) (*Blockchain, error) {
+	return LoadBlockchainWithGenesis(store, DefaultGenesis(), opts...)
+}
+
+// LoadBlockchainWithGenesis reconstructs a blockchain instance from the supplied
+// storage backend. When no blocks are present, the genesis block is created and
+// persisted. Stored chains built from a different genesis are rejected with
+// ErrGenesisMismatch. Stored rejection receipts are reloaded, and pending
+// transactions kept by the store are re-admitted to the mempool against the
+// recovered state.
+func LoadBlockchainWithGenesis(store BlockStateStore, genesis Genesis, opts ...Option) (*Blockchain, error) {
 	if store == nil {
 		return nil, errors.New("blockchain: storage is nil")
 	}
 
-	bc := &Blockchain{
-		blocks:              make([]types.Block, 0),
-		walletRegistry:      make(map[string]types.Wallet),
-		nftRegistry:         make(map[string]types.NFT),
-		pendingTransactions: make([]types.Transaction, 0),
-		store:               store,
+	genesisBlock, err := genesis.Block()
+	if err != nil {
+		return nil, err
 	}
 
+	bc := newBlockchain(genesis, genesisBlock, opts)
+	bc.blocks = make([]types.Block, 0)
+	bc.store = store
+
 	for idx := 0; ; idx++ {
 		block, err := store.GetBlock(idx)
 		if err != nil {
@@ -69,46 +123,46 @@ func LoadBlockchain(store BlockStateStore) (*Blockchain, error) {
 	}
 
 	if len(bc.blocks) == 0 {
-		genesis := NewBlock(0, "", nil)
-		bc.blocks = append(bc.blocks, genesis)
-		if err := store.SaveBlock(genesis); err != nil {
+		bc.blocks = append(bc.blocks, genesisBlock)
+		if err := store.SaveBlock(genesisBlock); err != nil {
 			return nil, err
 		}
-		if err := store.SaveState(cloneStateMaps(bc.walletRegistry, bc.nftRegistry)); err != nil {
+		if err := store.SaveState(cloneState(bc.state)); err != nil {
 			return nil, err
 		}
 		return bc, nil
 	}
 
-	genesis := bc.blocks[0]
-	if genesis.Index != 0 || genesis.PrevHash != "" || genesis.Hash != CalculateHash(genesis) {
+	stored := bc.blocks[0]
+	if stored.Index != 0 || stored.PrevHash != "" || stored.Hash != CalculateHash(stored) {
 		return nil, errors.New("blockchain: invalid genesis block in storage")
 	}
 
-	state := types.State{
-		WalletRegistry: make(map[string]types.Wallet),
-		NFTRegistry:    make(map[string]types.NFT),
+	if stored.Hash != genesisBlock.Hash {
+		return nil, fmt.Errorf("%w: stored %s, expected %s", errGenesisMismatch, stored.Hash, genesisBlock.Hash)
 	}
 
 	for i := 1; i < len(bc.blocks); i++ {
-		updated, err := ValidateBlock(bc.blocks[i], bc.blocks[i-1], state)
+		if err := bc.checkUncommittedLocked(bc.blocks[i].Transactions); err != nil {
+			return nil, err
+		}
+		tree, _, payloads, err := validateBlock(bc.registry, bc.blocks[i], bc.blocks[i-1], &bc.state, bc.stateTrees[i-1])
 		if err != nil {
 			return nil, err
 		}
-		state = updated
+		bc.stateTrees = append(bc.stateTrees, tree)
+		bc.indexPayloadsLocked(bc.blocks[i], payloads)
 	}
 
-	if state.WalletRegistry == nil {
-		state.WalletRegistry = make(map[string]types.Wallet)
-	}
-	if state.NFTRegistry == nil {
-		state.NFTRegistry = make(map[string]types.NFT)
+	if err := store.SaveState(cloneState(bc.state)); err != nil {
+		return nil, err
 	}
 
-	bc.walletRegistry = state.WalletRegistry
-	bc.nftRegistry = state.NFTRegistry
+	if err := bc.restoreRejectionsLocked(); err != nil {
+		return nil, err
+	}
 
-	if err := store.SaveState(cloneStateMaps(bc.walletRegistry, bc.nftRegistry)); err != nil {
+	if err := bc.restorePendingLocked(); err != nil {
 		return nil, err
 	}
 
@@ -124,19 +178,5 @@ func (bc *Blockchain) persistLocked(block types.Block) error {
 		return err
 	}
 
-	return bc.store.SaveState(cloneStateMaps(bc.walletRegistry, bc.nftRegistry))
-}
-
-func cloneStateMaps(wallets map[string]types.Wallet, nfts map[string]types.NFT) types.State {
-	copiedWallets := make(map[string]types.Wallet, len(wallets))
-	for k, v := range wallets {
-		copiedWallets[k] = v
-	}
-
-	copiedNFTs := make(map[string]types.NFT, len(nfts))
-	for k, v := range nfts {
-		copiedNFTs[k] = v
-	}
-
-	return types.State{WalletRegistry: copiedWallets, NFTRegistry: copiedNFTs}
+	return bc.store.SaveState(cloneState(bc.state))
 }
@@ -1,12 +1,12 @@
 package blockchain
 
 import (
-	"encoding/json"
+	"errors"
 	"testing"
+	"time"
 
 	"storytelling-blockchain/internal/storage"
 	"storytelling-blockchain/internal/types"
-	"storytelling-blockchain/pkg/utils"
 )
 
 func TestLoadBlockchainReconstructsState(t *testing.T) {
@@ -34,26 +34,13 @@ func TestLoadBlockchainReconstructsState(t *testing.T) {
 		BlockIndex:          -1,
 	}
 
-	payload := struct {
-		Wallet    types.Wallet `json:"wallet"`
-		Timestamp int64        `json:"timestamp"`
-	}{Wallet: userWallet, Timestamp: 6000}
+	tx := newCreateWalletTx(t, userWallet, 6000)
 
-	payloadBytes, err := json.Marshal(payload)
+	block, err := bc.BuildBlock([]types.Transaction{tx})
 	if err != nil {
-		t.Fatalf("marshal payload failed: %v", err)
+		t.Fatalf("build block failed: %v", err)
 	}
 
-	tx := types.Transaction{
-		TxID:      utils.ComputeSHA256(payloadBytes),
-		Type:      "create_wallet",
-		Data:      userWallet,
-		Timestamp: payload.Timestamp,
-	}
-
-	prev := bc.LatestBlock()
-	block := NewBlock(prev.Index+1, prev.Hash, []types.Transaction{tx})
-
 	if err := bc.AddBlock(block); err != nil {
 		t.Fatalf("add block failed: %v", err)
 	}
@@ -80,3 +67,119 @@ func TestLoadBlockchainReconstructsState(t *testing.T) {
 		t.Fatalf("expected wallet block index 1, got %d", stored.BlockIndex)
 	}
 }
+
+func TestLoadBlockchainRestoresMempool(t *testing.T) {
+	store, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	bc := NewBlockchain()
+	if err := bc.WithStorage(store); err != nil {
+		t.Fatalf("attach storage failed: %v", err)
+	}
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+
+	create := signTestTxWithNonce(t, alicePriv, 1, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{ID: "story-1", Title: "Story", CreatorID: "alice", CreatedAt: 20}})
+	line := types.ContributionPayload{Contribution: types.Contribution{ContributorID: "alice", WalletAddress: alice.Address, StoryID: "story-1", StoryLine: "Once", Timestamp: 30}}
+	contribution := signTestTxWithNonce(t, alicePriv, 2, TxTypeContribution, 30, line)
+	for _, tx := range []types.Transaction{create, contribution} {
+		if err := bc.EnqueueTransaction(tx); err != nil {
+			t.Fatalf("enqueue %s: %v", tx.Type, err)
+		}
+	}
+
+	// Committing a block deletes its transactions from the stored mempool.
+	commitTransactions(t, bc, create)
+
+	// Entries left behind by a crash: one already committed, one that no
+	// longer applies to the recovered state.
+	line.Contribution.StoryLine, line.Contribution.Timestamp = "Replayed", 31
+	stale := signTestTxWithNonce(t, alicePriv, 1, TxTypeContribution, 31, line)
+	queuedAt := time.Now().UnixNano()
+	for _, tx := range []types.Transaction{create, stale} {
+		if err := store.SavePendingTransaction(types.PendingTransaction{Transaction: tx, QueuedAt: queuedAt}); err != nil {
+			t.Fatalf("save pending %s: %v", tx.TxID, err)
+		}
+	}
+
+	restored, err := LoadBlockchain(store)
+	if err != nil {
+		t.Fatalf("load blockchain failed: %v", err)
+	}
+
+	pending := restored.PendingTransactions()
+	if len(pending) != 1 || pending[0].TxID != contribution.TxID {
+		t.Fatalf("expected only the contribution to be restored, got %+v", pending)
+	}
+	if next := restored.NextNonce("alice"); next != 3 {
+		t.Fatalf("expected the restored transaction to count towards the nonce, got %d", next)
+	}
+
+	kept, err := store.PendingTransactions()
+	if err != nil {
+		t.Fatalf("list pending failed: %v", err)
+	}
+	if len(kept) != 1 || kept[0].Transaction.TxID != contribution.TxID {
+		t.Fatalf("expected invalid entries to be pruned from storage, got %+v", kept)
+	}
+
+	// A withdrawn transaction leaves both the mempool and the store.
+	if !restored.WithdrawTransaction(contribution.TxID) || restored.WithdrawTransaction(contribution.TxID) {
+		t.Fatalf("expected the contribution to be withdrawn exactly once")
+	}
+	if kept, err = store.PendingTransactions(); err != nil || len(kept) != 0 || len(restored.PendingTransactions()) != 0 {
+		t.Fatalf("expected the withdrawn transaction to be gone, got %+v (%v)", kept, err)
+	}
+}
+
+func TestLoadBlockchainRestoresReceipts(t *testing.T) {
+	store, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	bc := NewBlockchain()
+	if err := bc.WithStorage(store); err != nil {
+		t.Fatalf("attach storage failed: %v", err)
+	}
+	bc.rejections.limit = 1
+
+	alice, _ := newKeyedWallet(t, "alice")
+	_, bobPriv := newKeyedWallet(t, "bob")
+	create := newCreateWalletTx(t, alice, 10)
+	committed := commitTransactions(t, bc, create)
+
+	forgotten := newCreateStoryTx(t, bobPriv, "alice", "story-1", 20)
+	forged := newCreateStoryTx(t, bobPriv, "alice", "story-2", 21)
+	for _, tx := range []types.Transaction{forgotten, forged} {
+		if err := bc.EnqueueTransaction(tx); !errors.Is(err, errInvalidSignature) {
+			t.Fatalf("expected a forged signature to be rejected, got %v", err)
+		}
+	}
+
+	restored, err := LoadBlockchain(store)
+	if err != nil {
+		t.Fatalf("load blockchain failed: %v", err)
+	}
+
+	// Committed receipts come from the stored transaction index.
+	delete(restored.txBlocks, create.TxID)
+	if receipt, ok := restored.Receipt(create.TxID); !ok || receipt.Status != types.TxStatusCommitted || receipt.BlockIndex != committed.Index {
+		t.Fatalf("expected a committed receipt from storage, got %+v", receipt)
+	}
+	if proof, ok := restored.TransactionProof(create.TxID); !ok || proof.BlockIndex != committed.Index {
+		t.Fatalf("expected a proof from storage, got %+v", proof)
+	}
+
+	if receipt, ok := restored.Receipt(forged.TxID); !ok || receipt.Status != types.TxStatusRejected || receipt.Reason != errInvalidSignature.Error() {
+		t.Fatalf("expected the rejection to survive a restart, got %+v", receipt)
+	}
+	if _, ok := restored.Receipt(forgotten.TxID); ok {
+		t.Fatalf("expected the receipt beyond the limit to be forgotten")
+	}
+}
@@ -1,12 +1,32 @@
 package blockchain
 
 import (
-	"encoding/json"
-
 	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
 )
 
-// StoryContributions returns all contribution transactions matching the story ID.
+// TransactionProof ties a transaction to a block hash through the Merkle path
+// from its transaction ID to the header's TxRoot.
+type TransactionProof struct {
+	TxID       string                  `json:"tx_id"`
+	BlockIndex int                     `json:"block_index"`
+	BlockHash  string                  `json:"block_hash"`
+	Header     types.BlockHeader       `json:"header"`
+	Proof      []utils.MerkleProofStep `json:"proof"`
+}
+
+// Verify checks the Merkle path against the header and the header against the block hash.
+func (p TransactionProof) Verify() bool {
+	if HashHeader(p.Header) != p.BlockHash {
+		return false
+	}
+
+	return utils.VerifyMerkleProof(p.TxID, p.Proof, p.Header.TxRoot)
+}
+
+// StoryContributions returns the effective main line of a story: amended
+// lines carry their latest text and retracted lines are left out. For forks
+// the lines inherited from the parent, up to the branch point, come first.
 func (bc *Blockchain) StoryContributions(storyID string) []types.Contribution {
 	if storyID == "" {
 		return nil
@@ -16,36 +36,171 @@ func (bc *Blockchain) StoryContributions(storyID string) []types.Contribution {
 	defer bc.mu.RUnlock()
 
 	var results []types.Contribution
+	for _, contribution := range bc.storyContributionsLocked(storyID) {
+		if bc.state.Contributions[contribution.TxID].Retracted {
+			continue
+		}
+		results = append(results, contribution)
+	}
+	return results
+}
 
-	envelope := struct {
-		Contribution types.Contribution `json:"contribution"`
-		Timestamp    int64              `json:"timestamp"`
-	}{}
+// storyContributionsLocked returns the main line of a story, retracted lines
+// included.
+func (bc *Blockchain) storyContributionsLocked(storyID string) []types.Contribution {
+	var results []types.Contribution
 
-	for _, block := range bc.blocks {
-		for _, tx := range block.Transactions {
-			if tx.Type != "contribution" {
-				continue
-			}
+	if lineage := bc.state.StoryRegistry[storyID].Lineage; lineage != nil {
+		for _, txID := range stateLineAncestry(bc.state, lineage.BranchTxID) {
+			location := bc.contributionIndex[txID]
+			results = append(results, bc.storyContributions[location.storyID][location.position])
+		}
+	}
 
-			payload, err := json.Marshal(tx.Data)
-			if err != nil {
-				continue
-			}
+	indexed := bc.storyContributions[storyID]
+	byTxID := make(map[string]types.Contribution, len(indexed))
+	for _, contribution := range indexed {
+		byTxID[contribution.TxID] = contribution
+	}
 
-			if err := json.Unmarshal(payload, &envelope); err != nil {
-				continue
-			}
+	for _, txID := range mainLine(indexedLineNodes(indexed)) {
+		results = append(results, byTxID[txID])
+	}
+	return results
+}
 
-			if envelope.Contribution.StoryID != storyID {
-				continue
+// indexPayloadsLocked records the decoded payloads of a committed block in the
+// query indexes so that lookups never decode transactions again.
+func (bc *Blockchain) indexPayloadsLocked(block types.Block, payloads []interface{}) {
+	for i, payload := range payloads {
+		tx := block.Transactions[i]
+		bc.txBlocks[tx.TxID] = block.Index
+
+		switch p := payload.(type) {
+		case types.ContributionPayload:
+			contribution := p.Contribution
+			contribution.TxID = tx.TxID
+			storyID := contribution.StoryID
+			// The state records the parent an empty parent resolved to.
+			contribution.ParentTxID = bc.state.Contributions[tx.TxID].ParentTxID
+			bc.contributionIndex[tx.TxID] = contributionLocation{storyID: storyID, position: len(bc.storyContributions[storyID])}
+			bc.storyContributions[storyID] = append(bc.storyContributions[storyID], contribution)
+			bc.contributionHistory[tx.TxID] = []types.ContributionRevision{{
+				TxID:       tx.TxID,
+				Type:       tx.Type,
+				StoryLine:  contribution.StoryLine,
+				BlockIndex: block.Index,
+				Timestamp:  tx.Timestamp,
+			}}
+
+		case types.AmendContributionPayload:
+			if location, ok := bc.contributionIndex[p.TxID]; ok {
+				bc.storyContributions[location.storyID][location.position].StoryLine = p.StoryLine
 			}
+			bc.appendRevisionLocked(p.TxID, types.ContributionRevision{
+				TxID:       tx.TxID,
+				Type:       tx.Type,
+				StoryLine:  p.StoryLine,
+				BlockIndex: block.Index,
+				Timestamp:  tx.Timestamp,
+			})
+
+		case types.RetractContributionPayload:
+			bc.appendRevisionLocked(p.TxID, types.ContributionRevision{
+				TxID:       tx.TxID,
+				Type:       tx.Type,
+				BlockIndex: block.Index,
+				Timestamp:  tx.Timestamp,
+			})
+
+		case types.MintNFTPayload:
+			bc.recordMintLocked(block, tx, p.NFT)
+
+		case types.ProposeMintPayload, types.ApproveMintPayload:
+			bc.indexMintProposalLocked(block, tx, p)
+
+		case types.TransferNFTPayload:
+			history := bc.nftHistory[p.TokenID]
+			entry := types.NFTProvenance{
+				TxID:       tx.TxID,
+				Type:       tx.Type,
+				FromID:     p.FromID,
+				ToID:       p.ToID,
+				ToAddress:  p.ToAddress,
+				BlockIndex: block.Index,
+				Timestamp:  tx.Timestamp,
+			}
+			if len(history) > 0 {
+				entry.FromAddress = history[len(history)-1].ToAddress
+			}
+			bc.nftHistory[p.TokenID] = append(history, entry)
+
+		case types.BurnNFTPayload:
+			history := bc.nftHistory[p.TokenID]
+			entry := types.NFTProvenance{
+				TxID:       tx.TxID,
+				Type:       tx.Type,
+				BlockIndex: block.Index,
+				Timestamp:  tx.Timestamp,
+			}
+			if len(history) > 0 {
+				entry.FromID = history[len(history)-1].ToID
+				entry.FromAddress = history[len(history)-1].ToAddress
+			}
+			bc.nftHistory[p.TokenID] = append(history, entry)
+		}
+	}
 
-			results = append(results, envelope.Contribution)
+	bc.pruneMintProposalsLocked()
+}
+
+// recordMintLocked starts the provenance of an NFT minted by tx.
+func (bc *Blockchain) recordMintLocked(block types.Block, tx types.Transaction, nft types.NFT) {
+	bc.nftHistory[nft.TokenID] = append(bc.nftHistory[nft.TokenID], types.NFTProvenance{
+		TxID:       tx.TxID,
+		Type:       tx.Type,
+		ToID:       nft.OwnerID,
+		ToAddress:  nft.OwnerAddress,
+		BlockIndex: block.Index,
+		Timestamp:  tx.Timestamp,
+	})
+}
+
+// TransactionProof locates a committed transaction and builds its inclusion proof.
+func (bc *Blockchain) TransactionProof(txID string) (TransactionProof, bool) {
+	if txID == "" {
+		return TransactionProof{}, false
+	}
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	index, ok := bc.committedBlockLocked(txID)
+	if !ok {
+		return TransactionProof{}, false
+	}
+
+	block := bc.blocks[index]
+	for i, tx := range block.Transactions {
+		if tx.TxID != txID {
+			continue
+		}
+
+		path, err := utils.MerkleProof(transactionIDs(block.Transactions), i)
+		if err != nil {
+			return TransactionProof{}, false
 		}
+
+		return TransactionProof{
+			TxID:       txID,
+			BlockIndex: block.Index,
+			BlockHash:  block.Hash,
+			Header:     block.Header(),
+			Proof:      path,
+		}, true
 	}
 
-	return results
+	return TransactionProof{}, false
 }
 
 // GetNFT retrieves the NFT for the provided token ID from the chain state.
@@ -57,7 +212,7 @@ func (bc *Blockchain) GetNFT(tokenID string) (types.NFT, bool) {
 	bc.mu.RLock()
 	defer bc.mu.RUnlock()
 
-	nft, ok := bc.nftRegistry[tokenID]
+	nft, ok := bc.state.NFTRegistry[tokenID]
 	return nft, ok
 }
 
@@ -71,7 +226,7 @@ func (bc *Blockchain) NFTsByStory(storyID string) []types.NFT {
 	defer bc.mu.RUnlock()
 
 	var results []types.NFT
-	for _, nft := range bc.nftRegistry {
+	for _, nft := range bc.state.NFTRegistry {
 		if nft.StoryID == storyID {
 			results = append(results, nft)
 		}
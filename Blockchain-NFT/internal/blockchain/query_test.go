@@ -0,0 +1,59 @@
+package blockchain
+
+import (
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestTransactionProofVerifiesAgainstBlockHash(t *testing.T) {
+	bc := newTestChain(t)
+
+	txs := []types.Transaction{
+		{TxID: "tx-1", Type: "test", Timestamp: 1},
+		{TxID: "tx-2", Type: "test", Timestamp: 1},
+		{TxID: "tx-3", Type: "test", Timestamp: 1},
+	}
+
+	block, err := bc.BuildBlock(txs)
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+
+	proof, ok := bc.TransactionProof("tx-2")
+	if !ok {
+		t.Fatalf("expected proof for committed transaction")
+	}
+
+	if proof.BlockIndex != 1 || proof.BlockHash != block.Hash {
+		t.Fatalf("unexpected proof location: %+v", proof)
+	}
+
+	if !proof.Verify() {
+		t.Fatalf("expected proof to verify")
+	}
+
+	proof.TxID = "tx-forged"
+	if proof.Verify() {
+		t.Fatalf("expected forged transaction id to fail verification")
+	}
+
+	if _, ok := bc.TransactionProof("tx-missing"); ok {
+		t.Fatalf("expected no proof for unknown transaction")
+	}
+}
+
+func TestValidateBlockRejectsTxRootMismatch(t *testing.T) {
+	bc := newTestChain(t)
+	prev := bc.LatestBlock()
+
+	block := NewBlock(prev.Index+1, prev.Hash, []types.Transaction{{TxID: "tx-1", Type: "test", Timestamp: 1}})
+	block.Transactions = append(block.Transactions, types.Transaction{TxID: "tx-smuggled", Type: "test", Timestamp: 1})
+
+	if err := bc.AddBlock(block); err == nil {
+		t.Fatalf("expected tx root mismatch to be rejected")
+	}
+}
@@ -0,0 +1,223 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"storytelling-blockchain/internal/mempool"
+	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+)
+
+// DefaultRejectedReceipts is the number of rejection receipts a chain keeps.
+// The oldest are forgotten first.
+const DefaultRejectedReceipts = 10000
+
+// rejectionLog keeps the most recent rejection receipts. It has its own lock
+// so that rejections can be recorded under the chain's read lock.
+type rejectionLog struct {
+	mu       sync.Mutex
+	limit    int
+	receipts map[string]types.RejectedReceipt
+	order    []string
+}
+
+func newRejectionLog(limit int) *rejectionLog {
+	return &rejectionLog{limit: limit, receipts: make(map[string]types.RejectedReceipt)}
+}
+
+// record keeps rejected and returns the IDs of the receipts it forgot to
+// make room.
+func (l *rejectionLog) record(rejected types.RejectedReceipt) []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	txID := rejected.Receipt.TxID
+	if _, exists := l.receipts[txID]; !exists {
+		l.order = append(l.order, txID)
+	}
+	l.receipts[txID] = rejected
+
+	var evicted []string
+	for len(l.order) > l.limit {
+		evicted = append(evicted, l.order[0])
+		delete(l.receipts, l.order[0])
+		l.order = l.order[1:]
+	}
+	return evicted
+}
+
+func (l *rejectionLog) get(txID string) (types.Receipt, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	rejected, ok := l.receipts[txID]
+	return rejected.Receipt, ok
+}
+
+// entries returns the kept receipts, oldest first.
+func (l *rejectionLog) entries() []types.RejectedReceipt {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]types.RejectedReceipt, len(l.order))
+	for i, txID := range l.order {
+		entries[i] = l.receipts[txID]
+	}
+	return entries
+}
+
+// Receipt reports the status of a transaction. A committed transaction
+// reports its block. Otherwise a transaction waiting in the mempool is
+// pending, and one refused at admission, expired from the mempool or dropped
+// from a failed block is rejected with the reason.
+func (bc *Blockchain) Receipt(txID string) (types.Receipt, bool) {
+	if txID == "" {
+		return types.Receipt{}, false
+	}
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	if index, ok := bc.committedBlockLocked(txID); ok {
+		block := bc.blocks[index]
+		receipt := types.Receipt{TxID: txID, Status: types.TxStatusCommitted, BlockIndex: block.Index, BlockHash: block.Hash}
+		for _, tx := range block.Transactions {
+			if tx.TxID == txID {
+				receipt.Type = tx.Type
+				break
+			}
+		}
+		return receipt, true
+	}
+
+	bc.expirePendingLocked()
+	if tx, ok := bc.mempool.Get(txID); ok {
+		return types.Receipt{TxID: txID, Type: tx.Type, Status: types.TxStatusPending, BlockIndex: -1}, true
+	}
+
+	return bc.rejections.get(txID)
+}
+
+// RejectFailedBlock records receipts for a block that failed to commit. Its
+// transactions are re-checked in order against the committed state; those
+// that no longer apply are rejected with their own reason and leave the
+// mempool, while the others stay pending for a later block. It returns the
+// rejection receipts.
+func (bc *Blockchain) RejectFailedBlock(block types.Block) []types.Receipt {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	prev := bc.blocks[len(bc.blocks)-1]
+	ctx := TxContext{BlockIndex: prev.Index + 1, BlockTimestamp: block.Timestamp}
+	state := cloneState(bc.liveStateLocked())
+
+	var receipts []types.Receipt
+	var rejected []types.Transaction
+	for _, tx := range block.Transactions {
+		if _, committed := bc.txBlocks[tx.TxID]; committed {
+			continue
+		}
+		if _, err := bc.registry.apply(ctx, &state, tx); err != nil {
+			bc.mempool.Remove(tx.TxID)
+			rejected = append(rejected, tx)
+			receipts = append(receipts, bc.rejectLocked(tx, err))
+		}
+	}
+
+	bc.dropPendingLocked(rejected)
+	return receipts
+}
+
+// expirePendingLocked drops the transactions that waited past the mempool's
+// time to live and records their rejection.
+func (bc *Blockchain) expirePendingLocked() {
+	expired := bc.mempool.Expire()
+	bc.dropPendingLocked(expired)
+	for _, tx := range expired {
+		bc.rejectLocked(tx, mempool.ErrExpired)
+	}
+}
+
+// rejectLocked records a rejection receipt for tx and announces it.
+func (bc *Blockchain) rejectLocked(tx types.Transaction, reason error) types.Receipt {
+	receipt := types.Receipt{
+		TxID:       tx.TxID,
+		Type:       tx.Type,
+		Status:     types.TxStatusRejected,
+		BlockIndex: -1,
+		Reason:     reason.Error(),
+	}
+	bc.recordRejectionLocked(types.RejectedReceipt{Receipt: receipt, RejectedAt: time.Now().UnixNano()})
+
+	bc.emitEvent(observer.Event{
+		Type:      observer.EventTransactionRejected,
+		Timestamp: time.Now().UTC(),
+		Data:      receipt,
+	})
+	return receipt
+}
+
+// recordRejectionLocked keeps a rejection receipt and, when the store keeps
+// receipts, writes it there and deletes the receipts the log forgot. A failed
+// write is not fatal: the receipt is still served until the node restarts.
+func (bc *Blockchain) recordRejectionLocked(rejected types.RejectedReceipt) {
+	evicted := bc.rejections.record(rejected)
+
+	store, ok := bc.store.(ReceiptStore)
+	if !ok {
+		return
+	}
+	_ = store.SaveRejectedReceipt(rejected)
+	_ = store.DeleteRejectedReceipts(evicted...)
+}
+
+// restoreRejectionsLocked reloads the stored rejection receipts, oldest
+// first, and deletes those beyond the log's limit.
+func (bc *Blockchain) restoreRejectionsLocked() error {
+	store, ok := bc.store.(ReceiptStore)
+	if !ok {
+		return nil
+	}
+
+	stored, err := store.RejectedReceipts()
+	if err != nil {
+		return err
+	}
+
+	sort.Slice(stored, func(i, j int) bool {
+		if stored[i].RejectedAt == stored[j].RejectedAt {
+			return stored[i].Receipt.TxID < stored[j].Receipt.TxID
+		}
+		return stored[i].RejectedAt < stored[j].RejectedAt
+	})
+
+	var evicted []string
+	for _, rejected := range stored {
+		evicted = append(evicted, bc.rejections.record(rejected)...)
+	}
+
+	return store.DeleteRejectedReceipts(evicted...)
+}
+
+// committedBlockLocked returns the index of the block that committed txID.
+// A store that keeps the transaction index answers from disk. The index
+// rebuilt in memory answers otherwise, and when the store is unreadable or
+// ahead of the chain after a failed commit.
+func (bc *Blockchain) committedBlockLocked(txID string) (int, bool) {
+	if store, ok := bc.store.(ReceiptStore); ok {
+		index, err := store.GetTransactionBlockIndex(txID)
+		switch {
+		case err == nil && index < len(bc.blocks):
+			return index, true
+		case errors.Is(err, storage.ErrNotFound):
+			return 0, false
+		}
+	}
+
+	index, ok := bc.txBlocks[txID]
+	return index, ok
+}
@@ -0,0 +1,83 @@
+package blockchain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/types"
+)
+
+func TestReceiptsTrackTransactionStatus(t *testing.T) {
+	bc := NewBlockchain()
+	bus := observer.NewBus()
+	bc.SetObserver(bus)
+	t.Cleanup(bus.Close)
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	_, bobPriv := newKeyedWallet(t, "bob")
+	carol, _ := newKeyedWallet(t, "carol")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+
+	if _, ok := bc.Receipt("unknown"); ok {
+		t.Fatalf("expected no receipt for an unknown transaction")
+	}
+
+	forged := newCreateStoryTx(t, bobPriv, "alice", "story-0", 15)
+	if err := bc.EnqueueTransaction(forged); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected a forged signature to be rejected, got %v", err)
+	}
+	if receipt, ok := bc.Receipt(forged.TxID); !ok || receipt.Status != types.TxStatusRejected || receipt.Reason != errInvalidSignature.Error() {
+		t.Fatalf("expected an admission rejection receipt, got %+v", receipt)
+	}
+
+	wallet := newCreateWalletTx(t, carol, 20)
+	create := signTestTxWithNonce(t, alicePriv, 1, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{ID: "story-1", Title: "Story", CreatorID: "alice", CreatedAt: 20}})
+	for _, tx := range []types.Transaction{wallet, create} {
+		if err := bc.EnqueueTransaction(tx); err != nil {
+			t.Fatalf("enqueue %s: %v", tx.Type, err)
+		}
+	}
+	if receipt, _ := bc.Receipt(create.TxID); receipt.Status != types.TxStatusPending || receipt.BlockIndex != -1 {
+		t.Fatalf("expected a pending receipt, got %+v", receipt)
+	}
+
+	// Another block takes alice's first nonce before the proposed block
+	// commits, so the proposed block fails and its story no longer applies.
+	proposed, err := bc.BuildBlock([]types.Transaction{wallet, create})
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+	rival := signTestTxWithNonce(t, alicePriv, 1, TxTypeCreateStory, 21, types.CreateStoryPayload{Story: types.StoryRecord{ID: "story-2", Title: "Rival", CreatorID: "alice", CreatedAt: 21}})
+	committed := commitTransactions(t, bc, rival)
+	if err := bc.AddBlock(proposed); err == nil {
+		t.Fatalf("expected the stale block to fail")
+	}
+
+	id, ch := bus.Subscribe(4)
+	t.Cleanup(func() { bus.Unsubscribe(id) })
+
+	rejected := bc.RejectFailedBlock(proposed)
+	if len(rejected) != 1 || rejected[0].TxID != create.TxID || !strings.HasPrefix(rejected[0].Reason, errNonceReplay.Error()) {
+		t.Fatalf("expected only the story to be rejected, got %+v", rejected)
+	}
+	if ev := <-ch; ev.Type != observer.EventTransactionRejected {
+		t.Fatalf("expected a transaction rejected event, got %s", ev.Type)
+	}
+
+	if receipt, _ := bc.Receipt(create.TxID); receipt.Status != types.TxStatusRejected {
+		t.Fatalf("expected the story to be rejected, got %+v", receipt)
+	}
+	if receipt, _ := bc.Receipt(wallet.TxID); receipt.Status != types.TxStatusPending {
+		t.Fatalf("expected the wallet to stay pending, got %+v", receipt)
+	}
+	if pending := bc.PendingTransactions(); len(pending) != 1 || pending[0].TxID != wallet.TxID {
+		t.Fatalf("expected the rejected story to leave the mempool, got %+v", pending)
+	}
+
+	receipt, ok := bc.Receipt(rival.TxID)
+	if !ok || receipt.Status != types.TxStatusCommitted || receipt.BlockIndex != committed.Index || receipt.BlockHash != committed.Hash || receipt.Type != TxTypeCreateStory {
+		t.Fatalf("expected a committed receipt for block %d, got %+v", committed.Index, receipt)
+	}
+}
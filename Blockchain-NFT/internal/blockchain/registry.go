@@ -0,0 +1,177 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errNilTxHandler           = errors.New("blockchain: transaction handler is nil")
+	errDuplicateTxHandler     = errors.New("blockchain: transaction handler already registered")
+	errUnknownTransactionType = errors.New("blockchain: unknown transaction type")
+)
+
+// ErrUnknownTransactionType is returned for transactions whose type has no registered handler.
+var ErrUnknownTransactionType = errUnknownTransactionType
+
+// TxContext carries the block-level information available to transaction handlers.
+type TxContext struct {
+	BlockIndex     int
+	BlockTimestamp int64
+
+	journal *stateJournal
+}
+
+// TransactionHandler implements the rules for a single transaction type.
+// Decode extracts the typed payload once; Validate checks it against the state
+// without mutating it; Apply performs the state transition and is only called
+// after Validate succeeds. Apply writes through SetStateEntry and
+// DeleteStateEntry.
+type TransactionHandler interface {
+	Type() string
+	Decode(tx types.Transaction) (interface{}, error)
+	Validate(ctx TxContext, state types.State, tx types.Transaction, payload interface{}) error
+	Apply(ctx TxContext, state *types.State, tx types.Transaction, payload interface{}) error
+}
+
+// TxRegistry maps transaction types to their handlers. Transactions of
+// unregistered types are rejected. Every replica must run with the same set of
+// handlers, so registration should complete before the chain is built.
+type TxRegistry struct {
+	mu       sync.RWMutex
+	handlers map[string]TransactionHandler
+}
+
+// defaultTxRegistry backs the package-level helpers and chains built without a registry.
+var defaultTxRegistry = DefaultTxRegistry()
+
+// NewTxRegistry builds a registry from the provided handlers.
+func NewTxRegistry(handlers ...TransactionHandler) (*TxRegistry, error) {
+	registry := &TxRegistry{handlers: make(map[string]TransactionHandler, len(handlers))}
+	for _, handler := range handlers {
+		if err := registry.Register(handler); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// DefaultTxRegistry returns a new registry holding the built-in transaction types.
+// Callers may register additional handlers on the returned registry.
+func DefaultTxRegistry() *TxRegistry {
+	registry, _ := NewTxRegistry(builtinTxHandlers()...) // built-in types are unique
+	return registry
+}
+
+// Register adds a handler for its transaction type.
+func (r *TxRegistry) Register(handler TransactionHandler) error {
+	if handler == nil {
+		return errNilTxHandler
+	}
+
+	txType := handler.Type()
+	if txType == "" {
+		return errEmptyTransactionType
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.handlers[txType]; exists {
+		return fmt.Errorf("%w: %s", errDuplicateTxHandler, txType)
+	}
+
+	r.handlers[txType] = handler
+	return nil
+}
+
+// Handler returns the handler registered for the transaction type.
+func (r *TxRegistry) Handler(txType string) (TransactionHandler, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	handler, ok := r.handlers[txType]
+	return handler, ok
+}
+
+// Types lists the registered transaction types in sorted order.
+func (r *TxRegistry) Types() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	txTypes := make([]string, 0, len(r.handlers))
+	for txType := range r.handlers {
+		txTypes = append(txTypes, txType)
+	}
+	sort.Strings(txTypes)
+	return txTypes
+}
+
+// apply runs a transaction through its handler against state and returns the
+// decoded payload so callers never decode a transaction twice.
+func (r *TxRegistry) apply(ctx TxContext, state *types.State, tx types.Transaction) (interface{}, error) {
+	checked, err := r.check(ctx, *state, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checked.apply(ctx, state); err != nil {
+		return nil, err
+	}
+
+	return checked.payload, nil
+}
+
+// checkedTx is a decoded transaction that its handler validated against a
+// state, ready to be applied to that state.
+type checkedTx struct {
+	tx      types.Transaction
+	handler TransactionHandler
+	payload interface{}
+	signer  string
+}
+
+// check decodes and validates a transaction against state without changing it.
+func (r *TxRegistry) check(ctx TxContext, state types.State, tx types.Transaction) (checkedTx, error) {
+	if tx.Type == "" {
+		return checkedTx{}, errEmptyTransactionType
+	}
+
+	handler, ok := r.Handler(tx.Type)
+	if !ok {
+		return checkedTx{}, fmt.Errorf("%w: %s", errUnknownTransactionType, tx.Type)
+	}
+
+	payload, err := handler.Decode(tx)
+	if err != nil {
+		return checkedTx{}, err
+	}
+
+	if err := handler.Validate(ctx, state, tx, payload); err != nil {
+		return checkedTx{}, err
+	}
+
+	signer := txSigner(handler, payload)
+	if err := checkNonce(state, signer, tx); err != nil {
+		return checkedTx{}, err
+	}
+
+	return checkedTx{tx: tx, handler: handler, payload: payload, signer: signer}, nil
+}
+
+// apply applies the transaction to the state it was checked against.
+func (c checkedTx) apply(ctx TxContext, state *types.State) error {
+	if err := c.handler.Apply(ctx, state, c.tx, c.payload); err != nil {
+		return err
+	}
+
+	if c.signer != "" {
+		SetStateEntry(ctx, StateKeyNonce, state.Nonces, c.signer, c.tx.Nonce)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package blockchain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+// noopTxHandler accepts any transaction of its type without touching state.
+type noopTxHandler struct {
+	txType string
+}
+
+func (h noopTxHandler) Type() string { return h.txType }
+
+func (noopTxHandler) Decode(tx types.Transaction) (interface{}, error) { return tx.Payload, nil }
+
+func (noopTxHandler) Validate(TxContext, types.State, types.Transaction, interface{}) error {
+	return nil
+}
+
+func (noopTxHandler) Apply(TxContext, *types.State, types.Transaction, interface{}) error {
+	return nil
+}
+
+// newTestChain builds a chain that additionally accepts "test" transactions.
+func newTestChain(t *testing.T) *Blockchain {
+	t.Helper()
+
+	registry := DefaultTxRegistry()
+	if err := registry.Register(noopTxHandler{txType: "test"}); err != nil {
+		t.Fatalf("register test handler: %v", err)
+	}
+
+	return NewBlockchain(WithTxRegistry(registry))
+}
+
+func TestDefaultTxRegistryTypes(t *testing.T) {
+	got := DefaultTxRegistry().Types()
+	want := []string{TxTypeAcceptInvite, TxTypeAmendContribution, TxTypeApproveMint, TxTypeBurnNFT, TxTypeCloseStory, TxTypeContribution, TxTypeCreateStory, TxTypeCreateWallet, TxTypeForkStory, TxTypeFreezeMetadata, TxTypeInviteContributor, TxTypeMintNFT, TxTypeProposeMint, TxTypeRetractContribution, TxTypeRevokeContributor, TxTypeTransferNFT, TxTypeTransferShares, TxTypeUpdateMetadata}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("unexpected built-in types: %v", got)
+	}
+}
+
+func TestTxRegistryRejectsInvalidRegistrations(t *testing.T) {
+	registry := DefaultTxRegistry()
+
+	if err := registry.Register(nil); !errors.Is(err, errNilTxHandler) {
+		t.Fatalf("expected nil handler error, got %v", err)
+	}
+
+	if err := registry.Register(noopTxHandler{}); !errors.Is(err, errEmptyTransactionType) {
+		t.Fatalf("expected empty type error, got %v", err)
+	}
+
+	if err := registry.Register(noopTxHandler{txType: TxTypeMintNFT}); !errors.Is(err, errDuplicateTxHandler) {
+		t.Fatalf("expected duplicate handler error, got %v", err)
+	}
+}
+
+func TestUnknownTransactionTypeRejected(t *testing.T) {
+	bc := NewBlockchain()
+	prev := bc.LatestBlock()
+
+	block := NewBlock(prev.Index+1, prev.Hash, []types.Transaction{{TxID: "tx-1", Type: "test", Timestamp: 1}})
+	if err := bc.AddBlock(block); !errors.Is(err, ErrUnknownTransactionType) {
+		t.Fatalf("expected unknown transaction type, got %v", err)
+	}
+
+	if _, err := bc.BuildBlock(block.Transactions); !errors.Is(err, ErrUnknownTransactionType) {
+		t.Fatalf("expected unknown transaction type when building, got %v", err)
+	}
+}
+
+func TestCustomHandlerPlugsIntoChain(t *testing.T) {
+	bc := newTestChain(t)
+
+	block, err := bc.BuildBlock([]types.Transaction{{TxID: "tx-1", Type: "test", Timestamp: 1}})
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+
+	if _, err := NewBlockWithState(block, bc.State(), []types.Transaction{{TxID: "tx-2", Type: "test", Timestamp: 1}}); !errors.Is(err, ErrUnknownTransactionType) {
+		t.Fatalf("package helpers should only accept built-in types, got %v", err)
+	}
+}
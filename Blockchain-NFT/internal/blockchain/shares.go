@@ -0,0 +1,233 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+
+	"storytelling-blockchain/internal/types"
+)
+
+// TotalShareUnits is the number of share units created for every minted NFT.
+const TotalShareUnits int64 = 10000
+
+var (
+	errNoShareholders     = errors.New("blockchain: nft requires at least one author")
+	errInvalidShareholder = errors.New("blockchain: nft author invalid")
+	errInvalidShareAmount = errors.New("blockchain: share amount must be positive")
+	errInsufficientShares = errors.New("blockchain: insufficient share balance")
+	errSelfShareTransfer  = errors.New("blockchain: shares already held by recipient")
+	errInvalidMintTime    = errors.New("blockchain: nft minted_at must match the transaction timestamp and not follow the block")
+	errMintAuthors        = errors.New("blockchain: nft authors do not match the committed contributions")
+	errMintOwner          = errors.New("blockchain: nft owner must be its main author")
+)
+
+// Exported errors for share balances and the authors they are derived from.
+var (
+	ErrInsufficientShares  = errInsufficientShares
+	ErrInvalidMintTime     = errInvalidMintTime
+	ErrMintAuthorsMismatch = errMintAuthors
+	ErrMintOwnerMismatch   = errMintOwner
+)
+
+// AllocateShares splits TotalShareUnits between the authors in proportion to
+// their authorship weights. Units left over after flooring go one each to the
+// authors with the largest remainders, ties broken by Supabase user ID, so
+// every replica derives the same balances.
+func AllocateShares(authors []types.Author) map[string]int64 {
+	return allocateUnits(authors, TotalShareUnits)
+}
+
+// allocateUnits splits total units by weight with largest-remainder rounding.
+func allocateUnits(authors []types.Author, total int64) map[string]int64 {
+	var weight int64
+	for _, author := range authors {
+		weight += author.Weight
+	}
+	if weight <= 0 {
+		return nil
+	}
+
+	type allocation struct {
+		holder    string
+		remainder int64
+	}
+
+	balances := make(map[string]int64, len(authors))
+	allocations := make([]allocation, 0, len(authors))
+	allocated := int64(0)
+
+	for _, author := range authors {
+		scaled := total * author.Weight
+		balances[author.SupabaseUserID] = scaled / weight
+		allocated += scaled / weight
+		allocations = append(allocations, allocation{holder: author.SupabaseUserID, remainder: scaled % weight})
+	}
+
+	sort.Slice(allocations, func(i, j int) bool {
+		if allocations[i].remainder == allocations[j].remainder {
+			return allocations[i].holder < allocations[j].holder
+		}
+		return allocations[i].remainder > allocations[j].remainder
+	})
+
+	for i := int64(0); i < total-allocated; i++ {
+		balances[allocations[i].holder]++
+	}
+
+	for holder, units := range balances {
+		if units == 0 {
+			delete(balances, holder)
+		}
+	}
+
+	return balances
+}
+
+// nftAuthors returns the main author followed by the co-authors.
+func nftAuthors(nft types.NFT) []types.Author {
+	if nft.MainAuthor.SupabaseUserID == "" && len(nft.CoAuthors) == 0 {
+		return nil
+	}
+	return append([]types.Author{nft.MainAuthor}, nft.CoAuthors...)
+}
+
+func validateShareholders(authors []types.Author) error {
+	if len(authors) == 0 {
+		return errNoShareholders
+	}
+
+	seen := make(map[string]struct{}, len(authors))
+	for _, author := range authors {
+		if author.SupabaseUserID == "" || author.Weight <= 0 {
+			return errInvalidShareholder
+		}
+		if _, exists := seen[author.SupabaseUserID]; exists {
+			return errInvalidShareholder
+		}
+		seen[author.SupabaseUserID] = struct{}{}
+	}
+
+	return nil
+}
+
+// transferSharesHandler moves share units of an NFT between wallets.
+type transferSharesHandler struct{}
+
+func (transferSharesHandler) Type() string { return TxTypeTransferShares }
+
+func (transferSharesHandler) Signer(payload interface{}) string {
+	return payload.(types.TransferSharesPayload).FromID
+}
+
+func (transferSharesHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.TransferSharesPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (transferSharesHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	transfer := decoded.(types.TransferSharesPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if _, err := activeNFT(state, transfer.TokenID); err != nil {
+		return err
+	}
+
+	if transfer.Amount <= 0 {
+		return errInvalidShareAmount
+	}
+
+	if transfer.FromID == "" {
+		return errMissingWalletID
+	}
+
+	if transfer.ToID == transfer.FromID {
+		return errSelfShareTransfer
+	}
+
+	if state.ShareBalances[transfer.TokenID][transfer.FromID] < transfer.Amount {
+		return errInsufficientShares
+	}
+
+	sender, ok := state.WalletRegistry[transfer.FromID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	recipient, ok := state.WalletRegistry[transfer.ToID]
+	if !ok {
+		return errMissingWallet
+	}
+	if recipient.Address != transfer.ToAddress {
+		return errOwnerAddressMismatch
+	}
+
+	return verifyWalletSignature(state.ChainID, sender, tx)
+}
+
+func (transferSharesHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	transfer := decoded.(types.TransferSharesPayload)
+
+	balances := make(map[string]int64, len(state.ShareBalances[transfer.TokenID])+1)
+	for holder, units := range state.ShareBalances[transfer.TokenID] {
+		balances[holder] = units
+	}
+	balances[transfer.FromID] -= transfer.Amount
+	if balances[transfer.FromID] == 0 {
+		delete(balances, transfer.FromID)
+	}
+	balances[transfer.ToID] += transfer.Amount
+	SetStateEntry(ctx, StateKeyShares, state.ShareBalances, transfer.TokenID, balances)
+	return nil
+}
+
+// ShareBalance returns the share units of a token held by a Supabase user.
+func (bc *Blockchain) ShareBalance(tokenID, userID string) int64 {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	return bc.state.ShareBalances[tokenID][userID]
+}
+
+// ShareHoldings returns the live share balances of a token, largest holding first.
+func (bc *Blockchain) ShareHoldings(tokenID string) []types.ShareHolding {
+	if tokenID == "" {
+		return nil
+	}
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	balances := bc.state.ShareBalances[tokenID]
+	if len(balances) == 0 {
+		return nil
+	}
+
+	holdings := make([]types.ShareHolding, 0, len(balances))
+	for holder, units := range balances {
+		holdings = append(holdings, types.ShareHolding{
+			SupabaseUserID:      holder,
+			WalletAddress:       bc.state.WalletRegistry[holder].Address,
+			Shares:              units,
+			OwnershipPercentage: float64(units) / float64(TotalShareUnits) * 100,
+		})
+	}
+
+	sort.Slice(holdings, func(i, j int) bool {
+		if holdings[i].Shares == holdings[j].Shares {
+			return holdings[i].SupabaseUserID < holdings[j].SupabaseUserID
+		}
+		return holdings[i].Shares > holdings[j].Shares
+	})
+
+	return holdings
+}
@@ -0,0 +1,184 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestAllocateSharesLargestRemainder(t *testing.T) {
+	cases := []struct {
+		Name    string
+		Authors []types.Author
+		Want    map[string]int64
+	}{
+		{
+			Name: "equal thirds",
+			Authors: []types.Author{
+				{SupabaseUserID: "carol", ContributionCount: 1, Weight: 1},
+				{SupabaseUserID: "alice", ContributionCount: 1, Weight: 1},
+				{SupabaseUserID: "bob", ContributionCount: 1, Weight: 1},
+			},
+			Want: map[string]int64{"alice": 3334, "bob": 3333, "carol": 3333},
+		},
+		{
+			Name: "uneven weights",
+			Authors: []types.Author{
+				{SupabaseUserID: "alice", ContributionCount: 4, Weight: 4},
+				{SupabaseUserID: "bob", ContributionCount: 2, Weight: 2},
+				{SupabaseUserID: "carol", ContributionCount: 1, Weight: 1},
+			},
+			Want: map[string]int64{"alice": 5714, "bob": 2857, "carol": 1429},
+		},
+		{
+			Name:    "single author",
+			Authors: []types.Author{{SupabaseUserID: "alice", ContributionCount: 7, Weight: 7}},
+			Want:    map[string]int64{"alice": TotalShareUnits},
+		},
+	}
+
+	for _, tc := range cases {
+		got := AllocateShares(tc.Authors)
+
+		var total int64
+		for _, units := range got {
+			total += units
+		}
+		if total != TotalShareUnits {
+			t.Fatalf("%s: expected %d units, got %d", tc.Name, TotalShareUnits, total)
+		}
+
+		if len(got) != len(tc.Want) {
+			t.Fatalf("%s: unexpected allocation %v", tc.Name, got)
+		}
+		for holder, units := range tc.Want {
+			if got[holder] != units {
+				t.Fatalf("%s: expected %s to hold %d, got %v", tc.Name, holder, units, got)
+			}
+		}
+	}
+}
+
+func TestTransferSharesMovesBalances(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 15))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "One", 15), newContributionTx(t, bobPriv, bob, "story-1", "Two", 15))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Three", 15))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Four", 15))
+	commitTransactions(t, bc, newCloseStoryTx(t, alicePriv, "alice", "story-1", 16))
+
+	nft := newStoryNFT(t, bc, "story-1", "nft-1", 20)
+	nft.Edition = 1
+	commitTransactions(t, bc, newProposeMintTx(t, alicePriv, "alice", nft, types.NowUnix()+3600))
+
+	if got := bc.ShareBalance("nft-1", "alice"); got != 7500 {
+		t.Fatalf("expected alice to hold 7500 units, got %d", got)
+	}
+
+	newSharesTx := func(priv, from, to, toAddress string, amount int64) types.Transaction {
+		return signTestTx(t, priv, TxTypeTransferShares, 30, types.TransferSharesPayload{
+			TokenID: "nft-1", FromID: from, ToID: to, ToAddress: toAddress, Amount: amount,
+		})
+	}
+
+	rejected := []struct {
+		Name string
+		Tx   types.Transaction
+		Want error
+	}{
+		{Name: "overdraw", Tx: newSharesTx(bobPriv, "bob", "alice", alice.Address, 2501), Want: errInsufficientShares},
+		{Name: "zero amount", Tx: newSharesTx(bobPriv, "bob", "alice", alice.Address, 0), Want: errInvalidShareAmount},
+		{Name: "negative amount", Tx: newSharesTx(bobPriv, "bob", "alice", alice.Address, -5), Want: errInvalidShareAmount},
+		{Name: "wrong signer", Tx: newSharesTx(bobPriv, "alice", "bob", bob.Address, 10), Want: errInvalidSignature},
+		{Name: "self transfer", Tx: newSharesTx(alicePriv, "alice", "alice", alice.Address, 10), Want: errSelfShareTransfer},
+	}
+	for _, tc := range rejected {
+		if _, err := bc.BuildBlock([]types.Transaction{tc.Tx}); !errors.Is(err, tc.Want) {
+			t.Fatalf("%s: expected %v, got %v", tc.Name, tc.Want, err)
+		}
+	}
+
+	before := bc.LatestBlock().StateRoot
+	commitTransactions(t, bc, newSharesTx(bobPriv, "bob", "alice", alice.Address, 2500))
+	if bc.LatestBlock().StateRoot == before {
+		t.Fatalf("expected share transfer to change the state root")
+	}
+
+	holdings := bc.ShareHoldings("nft-1")
+	if len(holdings) != 1 || holdings[0].SupabaseUserID != "alice" || holdings[0].Shares != TotalShareUnits {
+		t.Fatalf("expected alice to hold every unit, got %+v", holdings)
+	}
+	if holdings[0].OwnershipPercentage != 100 || holdings[0].WalletAddress != alice.Address {
+		t.Fatalf("unexpected holding: %+v", holdings[0])
+	}
+}
+
+func TestMintAuthorsDerivedFromContributions(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	mallory, _ := newKeyedWallet(t, "mallory")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10), newCreateWalletTx(t, mallory, 10))
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 15, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Words",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{AuthorshipPolicy: AuthorshipWords, MintWhileOpen: true},
+		CreatedAt: 15,
+	}}))
+	first := newContributionTx(t, alicePriv, alice, "story-1", "One", 16)
+	commitTransactions(t, bc, first, newContributionTx(t, bobPriv, bob, "story-1", "Two more words", 16))
+
+	mint := func(nft types.NFT) error {
+		_, err := bc.BuildBlock([]types.Transaction{newProposeMintTx(t, alicePriv, "alice", nft, types.NowUnix()+3600)})
+		return err
+	}
+
+	derived := func() types.NFT {
+		nft := newStoryNFT(t, bc, "story-1", "nft-1", 20)
+		nft.Edition = 1
+		return nft
+	}
+
+	hijacked := derived()
+	hijacked.MainAuthor = types.Author{SupabaseUserID: "mallory", WalletAddress: mallory.Address, ContributionCount: 1, Weight: 1}
+	hijacked.CoAuthors = nil
+	hijacked.OwnerID, hijacked.OwnerAddress = "mallory", mallory.Address
+	if err := mint(hijacked); !errors.Is(err, ErrMintAuthorsMismatch) {
+		t.Fatalf("expected a mint crediting a non-author to fail, got %v", err)
+	}
+
+	inflated := derived()
+	inflated.CoAuthors[0].Weight = 99
+	if err := mint(inflated); !errors.Is(err, ErrMintAuthorsMismatch) {
+		t.Fatalf("expected a mint with inflated weights to fail, got %v", err)
+	}
+
+	backdated := derived()
+	backdated.MintedAt = 19
+	tx := signTestTx(t, alicePriv, TxTypeProposeMint, 20, types.ProposeMintPayload{NFT: backdated, ProposerID: "alice", ExpiresAt: types.NowUnix() + 3600})
+	if _, err := bc.BuildBlock([]types.Transaction{tx}); !errors.Is(err, ErrInvalidMintTime) {
+		t.Fatalf("expected minted_at differing from the transaction to fail, got %v", err)
+	}
+
+	// Amendments change the committed text the words policy weighs.
+	commitTransactions(t, bc, newAmendTx(t, alicePriv, "alice", first.TxID, "One two three four five six", 18))
+	if err := mint(inflated); !errors.Is(err, ErrMintAuthorsMismatch) {
+		t.Fatalf("expected the authors to follow the amendment, got %v", err)
+	}
+
+	nft := derived()
+	if err := mint(nft); err != nil {
+		t.Fatalf("expected a mint derived from the chain to pass, got %v", err)
+	}
+	if nft.OwnerID != "alice" || nft.MainAuthor.Weight != 6 || nft.CoAuthors[0].Weight != 3 {
+		t.Fatalf("unexpected derived authors: %+v %+v", nft.MainAuthor, nft.CoAuthors)
+	}
+}
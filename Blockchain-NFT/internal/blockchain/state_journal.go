@@ -0,0 +1,66 @@
+package blockchain
+
+// stateJournal records the state keys a block changes, with a step to undo
+// each change. Blocks are applied to the live state in place: the journal
+// restores it when the block is rejected, and the state tree is updated from
+// the recorded keys only.
+type stateJournal struct {
+	keys    []string
+	touched map[string]struct{}
+	undo    []func()
+}
+
+func newStateJournal() *stateJournal {
+	return &stateJournal{touched: make(map[string]struct{})}
+}
+
+func (j *stateJournal) record(key string, undo func()) {
+	if _, ok := j.touched[key]; !ok {
+		j.touched[key] = struct{}{}
+		j.keys = append(j.keys, key)
+	}
+	j.undo = append(j.undo, undo)
+}
+
+// revert undoes every recorded change, newest first.
+func (j *stateJournal) revert() {
+	for i := len(j.undo) - 1; i >= 0; i-- {
+		j.undo[i]()
+	}
+	j.keys, j.undo = nil, nil
+	j.touched = make(map[string]struct{})
+}
+
+// SetStateEntry sets m[id] to value, where m is the state map stored under the
+// key namespace prefix. Transaction handlers write the state through
+// SetStateEntry and DeleteStateEntry so the block can be undone and the
+// change reaches the state root. Nested maps must be replaced, not mutated.
+func SetStateEntry[V any](ctx TxContext, prefix string, m map[string]V, id string, value V) {
+	if ctx.journal != nil {
+		ctx.journal.record(prefix+id, restoreStateEntry(m, id))
+	}
+	m[id] = value
+}
+
+// DeleteStateEntry removes id from m, the state map stored under the key
+// namespace prefix.
+func DeleteStateEntry[V any](ctx TxContext, prefix string, m map[string]V, id string) {
+	if _, ok := m[id]; !ok {
+		return
+	}
+	if ctx.journal != nil {
+		ctx.journal.record(prefix+id, restoreStateEntry(m, id))
+	}
+	delete(m, id)
+}
+
+func restoreStateEntry[V any](m map[string]V, id string) func() {
+	old, ok := m[id]
+	return func() {
+		if ok {
+			m[id] = old
+		} else {
+			delete(m, id)
+		}
+	}
+}
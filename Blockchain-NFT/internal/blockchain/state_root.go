@@ -0,0 +1,278 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/canonical"
+	"storytelling-blockchain/pkg/utils"
+)
+
+// State key namespaces used as leaf keys in the state tree.
+const (
+	StateKeyWallet       = "wallet/"
+	StateKeyNFT          = "nft/"
+	StateKeyShares       = "shares/"
+	StateKeyStory        = "story/"
+	StateKeyACL          = "acl/"
+	StateKeyContribution = "contribution/"
+	StateKeyMintProposal = "proposal/"
+	StateKeyNonce        = "nonce/"
+)
+
+var (
+	errStateRootMismatch = errors.New("blockchain: state root mismatch")
+	errHeightOutOfRange  = errors.New("blockchain: height out of range")
+	errStateKeyNotFound  = errors.New("blockchain: state key not found")
+)
+
+// Exported errors for state proof lookups.
+var (
+	ErrHeightOutOfRange = errHeightOutOfRange
+	ErrStateKeyNotFound = errStateKeyNotFound
+)
+
+// StateProof proves that a key held a value in the state committed by a block.
+type StateProof struct {
+	Key        string                  `json:"key"`
+	Value      json.RawMessage         `json:"value"`
+	ValueHash  string                  `json:"value_hash"`
+	BlockIndex int                     `json:"block_index"`
+	BlockHash  string                  `json:"block_hash"`
+	Header     types.BlockHeader       `json:"header"`
+	Proof      []utils.MerkleProofStep `json:"proof"`
+}
+
+// Verify checks the value against its leaf, the leaf against the header's
+// StateRoot and the header against the block hash.
+func (p StateProof) Verify() bool {
+	if HashHeader(p.Header) != p.BlockHash {
+		return false
+	}
+
+	if stateValueHash(p.Key, p.Value) != p.ValueHash {
+		return false
+	}
+
+	return utils.VerifyMerkleProof(stateLeaf(p.Key, p.ValueHash), p.Proof, p.Header.StateRoot)
+}
+
+// CalculateStateRoot returns the root of the sparse Merkle tree over the
+// committed chain state. Every wallet, NFT, share ledger, story, story ACL,
+// contribution, mint proposal and nonce becomes a leaf "<namespace><id>=<value hash>",
+// where the value hash is the canonical encoding of the key and its JSON value.
+// Wallets registered locally but not yet committed (negative BlockIndex) are
+// excluded because other replicas cannot know about them.
+func CalculateStateRoot(state types.State) string {
+	tree, err := newStateTree(state)
+	if err != nil {
+		return ""
+	}
+
+	return tree.Root()
+}
+
+// NewBlockWithState builds the block following prev, committing to the state
+// produced by applying transactions to state with the built-in transaction types.
+func NewBlockWithState(prev types.Block, state types.State, transactions []types.Transaction) (types.Block, error) {
+	tree, err := newStateTree(state)
+	if err != nil {
+		return types.Block{}, err
+	}
+
+	scratch := cloneState(state)
+	return newBlockWithState(defaultTxRegistry, prev, &scratch, tree, transactions)
+}
+
+// newBlockWithState applies the transactions to state in place to compute the
+// state root, then undoes them.
+func newBlockWithState(registry *TxRegistry, prev types.Block, state *types.State, tree *stateTree, transactions []types.Transaction) (types.Block, error) {
+	block := NewBlock(prev.Index+1, prev.Hash, transactions)
+
+	journal, _, err := applyBlockTransactions(registry, block, state)
+	if err != nil {
+		return types.Block{}, err
+	}
+	defer journal.revert()
+
+	nextTree, err := tree.update(*state, journal.keys)
+	if err != nil {
+		return types.Block{}, err
+	}
+
+	block.StateRoot = nextTree.Root()
+	block.Hash = CalculateHash(block)
+	return block, nil
+}
+
+// BuildBlock assembles the next block on top of the chain head from the provided transactions.
+// The transactions are applied to the live state and undone, so it takes the
+// write lock.
+func (bc *Blockchain) BuildBlock(transactions []types.Transaction) (types.Block, error) {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	if err := bc.checkUncommittedLocked(transactions); err != nil {
+		return types.Block{}, err
+	}
+
+	prev := bc.blocks[len(bc.blocks)-1]
+	return newBlockWithState(bc.registry, prev, &bc.state, bc.stateTrees[len(bc.stateTrees)-1], transactions)
+}
+
+// StateProof builds an inclusion proof for key in the state committed at
+// height. The chain keeps the state tree of every height, so no blocks are
+// replayed.
+func (bc *Blockchain) StateProof(height int, key string) (StateProof, error) {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	if height < 0 || height >= len(bc.blocks) {
+		return StateProof{}, fmt.Errorf("%w: %d", errHeightOutOfRange, height)
+	}
+
+	entry, path, ok := bc.stateTrees[height].prove(key)
+	if !ok {
+		return StateProof{}, errStateKeyNotFound
+	}
+
+	block := bc.blocks[height]
+	return StateProof{
+		Key:        key,
+		Value:      entry.value,
+		ValueHash:  entry.valueHash,
+		BlockIndex: block.Index,
+		BlockHash:  block.Hash,
+		Header:     block.Header(),
+		Proof:      path,
+	}, nil
+}
+
+// liveStateLocked exposes the current state without copying; callers must
+// not mutate the returned maps.
+func (bc *Blockchain) liveStateLocked() types.State {
+	return bc.state
+}
+
+type stateEntry struct {
+	key       string
+	value     json.RawMessage
+	valueHash string
+}
+
+func (e stateEntry) leaf() string {
+	return stateLeaf(e.key, e.valueHash)
+}
+
+func stateLeaf(key, valueHash string) string {
+	return key + "=" + valueHash
+}
+
+// stateEntries lists every entry of state that belongs in the state tree.
+func stateEntries(state types.State) ([]stateEntry, error) {
+	var entries []stateEntry
+	add := func(key string, value interface{}) error {
+		entry, err := newStateEntry(key, value)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+		return nil
+	}
+
+	for id, wallet := range state.WalletRegistry {
+		if committedWallet(wallet) {
+			if err := add(StateKeyWallet+id, wallet); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := addStateEntries(add, StateKeyNFT, state.NFTRegistry); err != nil {
+		return nil, err
+	}
+	if err := addStateEntries(add, StateKeyShares, state.ShareBalances); err != nil {
+		return nil, err
+	}
+	if err := addStateEntries(add, StateKeyStory, state.StoryRegistry); err != nil {
+		return nil, err
+	}
+	if err := addStateEntries(add, StateKeyACL, state.StoryMembers); err != nil {
+		return nil, err
+	}
+	if err := addStateEntries(add, StateKeyContribution, state.Contributions); err != nil {
+		return nil, err
+	}
+	if err := addStateEntries(add, StateKeyMintProposal, state.MintProposals); err != nil {
+		return nil, err
+	}
+	if err := addStateEntries(add, StateKeyNonce, state.Nonces); err != nil {
+		return nil, err
+	}
+
+	// Validators are left out: they only come from the genesis document,
+	// which the genesis block hash already commits to.
+
+	return entries, nil
+}
+
+func addStateEntries[V any](add func(string, interface{}) error, prefix string, m map[string]V) error {
+	for id, value := range m {
+		if err := add(prefix+id, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// lookupStateValue returns the value stored under a state tree key, or false
+// when the key is absent from the tree.
+func lookupStateValue(state types.State, key string) (interface{}, bool) {
+	switch {
+	case strings.HasPrefix(key, StateKeyWallet):
+		wallet, ok := state.WalletRegistry[strings.TrimPrefix(key, StateKeyWallet)]
+		return wallet, ok && committedWallet(wallet)
+	case strings.HasPrefix(key, StateKeyNFT):
+		return lookupStateMap(state.NFTRegistry, strings.TrimPrefix(key, StateKeyNFT))
+	case strings.HasPrefix(key, StateKeyShares):
+		return lookupStateMap(state.ShareBalances, strings.TrimPrefix(key, StateKeyShares))
+	case strings.HasPrefix(key, StateKeyStory):
+		return lookupStateMap(state.StoryRegistry, strings.TrimPrefix(key, StateKeyStory))
+	case strings.HasPrefix(key, StateKeyACL):
+		return lookupStateMap(state.StoryMembers, strings.TrimPrefix(key, StateKeyACL))
+	case strings.HasPrefix(key, StateKeyContribution):
+		return lookupStateMap(state.Contributions, strings.TrimPrefix(key, StateKeyContribution))
+	case strings.HasPrefix(key, StateKeyMintProposal):
+		return lookupStateMap(state.MintProposals, strings.TrimPrefix(key, StateKeyMintProposal))
+	case strings.HasPrefix(key, StateKeyNonce):
+		return lookupStateMap(state.Nonces, strings.TrimPrefix(key, StateKeyNonce))
+	}
+	return nil, false
+}
+
+func lookupStateMap[V any](m map[string]V, id string) (interface{}, bool) {
+	value, ok := m[id]
+	return value, ok
+}
+
+// committedWallet reports whether a wallet was committed on-chain; wallets
+// registered locally have a negative BlockIndex.
+func committedWallet(wallet types.Wallet) bool {
+	return wallet.BlockIndex >= 0
+}
+
+func newStateEntry(key string, value interface{}) (stateEntry, error) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return stateEntry{}, err
+	}
+
+	return stateEntry{key: key, value: encoded, valueHash: stateValueHash(key, encoded)}, nil
+}
+
+// stateValueHash binds the JSON value to its key with the canonical encoder.
+func stateValueHash(key string, value []byte) string {
+	return canonical.NewEncoder(canonical.TagStateValue).String(key).Bytes(value).Hash()
+}
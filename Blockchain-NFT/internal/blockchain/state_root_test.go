@@ -0,0 +1,107 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func newCreateWalletTx(t *testing.T, wallet types.Wallet, timestamp int64) types.Transaction {
+	t.Helper()
+
+	tx, err := NewTransaction(TxTypeCreateWallet, timestamp, types.CreateWalletPayload{Wallet: wallet})
+	if err != nil {
+		t.Fatalf("build transaction: %v", err)
+	}
+	return tx
+}
+
+func TestStateRootCommittedInBlocks(t *testing.T) {
+	bc := NewBlockchain()
+	genesis := bc.LatestBlock()
+
+	if genesis.StateRoot != CalculateStateRoot(bc.Genesis().State()) {
+		t.Fatalf("genesis should commit to the genesis state")
+	}
+
+	wallet := types.Wallet{Address: "0xabc", SupabaseUserID: "user-1", PublicKey: "pub", PrivateKeyEncrypted: "enc", CreatedAt: 10}
+	block, err := bc.BuildBlock([]types.Transaction{newCreateWalletTx(t, wallet, 20)})
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+
+	if block.StateRoot == genesis.StateRoot {
+		t.Fatalf("state root should change when a wallet is created")
+	}
+
+	forged := block
+	forged.StateRoot = genesis.StateRoot
+	forged.Hash = CalculateHash(forged)
+	if err := bc.AddBlock(forged); !errors.Is(err, errStateRootMismatch) {
+		t.Fatalf("expected state root mismatch, got %v", err)
+	}
+
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+
+	if !bc.ValidateChain() {
+		t.Fatalf("expected chain to validate")
+	}
+}
+
+func TestStateRootIgnoresLocalWallets(t *testing.T) {
+	bc := NewBlockchain()
+	before := CalculateStateRoot(bc.liveStateLocked())
+
+	bc.RegisterWallet(types.Wallet{Address: "0xlocal", SupabaseUserID: "user-local", PublicKey: "pub", BlockIndex: -1})
+
+	if after := CalculateStateRoot(bc.liveStateLocked()); after != before {
+		t.Fatalf("uncommitted wallets must not affect the state root")
+	}
+}
+
+func TestStateProofAtHeight(t *testing.T) {
+	bc := NewBlockchain()
+
+	wallet := types.Wallet{Address: "0xabc", SupabaseUserID: "user-1", PublicKey: "pub", PrivateKeyEncrypted: "enc", CreatedAt: 10}
+	block, err := bc.BuildBlock([]types.Transaction{newCreateWalletTx(t, wallet, 20)})
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+
+	proof, err := bc.StateProof(1, StateKeyWallet+"user-1")
+	if err != nil {
+		t.Fatalf("state proof: %v", err)
+	}
+
+	if proof.BlockHash != block.Hash || !proof.Verify() {
+		t.Fatalf("expected proof to verify against block %s", block.Hash)
+	}
+
+	var proven types.Wallet
+	if err := json.Unmarshal(proof.Value, &proven); err != nil {
+		t.Fatalf("decode proven wallet: %v", err)
+	}
+	if proven.Address != wallet.Address || proven.BlockIndex != 1 {
+		t.Fatalf("unexpected proven wallet: %+v", proven)
+	}
+
+	proof.Value = json.RawMessage(`{"address":"0xforged"}`)
+	if proof.Verify() {
+		t.Fatalf("expected tampered value to fail verification")
+	}
+
+	if _, err := bc.StateProof(0, StateKeyWallet+"user-1"); !errors.Is(err, ErrStateKeyNotFound) {
+		t.Fatalf("expected wallet to be absent at genesis, got %v", err)
+	}
+
+	if _, err := bc.StateProof(2, StateKeyWallet+"user-1"); !errors.Is(err, ErrHeightOutOfRange) {
+		t.Fatalf("expected height out of range, got %v", err)
+	}
+}
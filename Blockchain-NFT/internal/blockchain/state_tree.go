@@ -0,0 +1,198 @@
+package blockchain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+// emptyStateHash stands in for an empty subtree and is the root of an empty state.
+var emptyStateHash = make([]byte, sha256.Size)
+
+// stateTree is an immutable sparse Merkle tree over the state entries. A leaf
+// sits at the shortest prefix of SHA-256(key) that no other key shares, so the
+// shape only depends on the set of keys. Updates copy the path to the changed
+// leaves and share every other node with the previous tree, which lets the
+// chain keep the tree of every height.
+type stateTree struct {
+	root *stateNode
+}
+
+type stateNode struct {
+	hash        []byte
+	left, right *stateNode
+	entry       *stateEntry
+	keyHash     [sha256.Size]byte
+}
+
+// newStateTree builds the tree over every entry of state.
+func newStateTree(state types.State) (*stateTree, error) {
+	entries, err := stateEntries(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var root *stateNode
+	for i := range entries {
+		root = insertStateNode(root, 0, newStateLeaf(&entries[i]))
+	}
+
+	return &stateTree{root: root}, nil
+}
+
+// update returns the tree for state, given that t is the tree of a state that
+// only differed under keys. Only those keys are re-encoded.
+func (t *stateTree) update(state types.State, keys []string) (*stateTree, error) {
+	root := t.root
+	for _, key := range keys {
+		value, ok := lookupStateValue(state, key)
+		if !ok {
+			root = deleteStateNode(root, 0, stateKeyHash(key))
+			continue
+		}
+
+		entry, err := newStateEntry(key, value)
+		if err != nil {
+			return nil, err
+		}
+		root = insertStateNode(root, 0, newStateLeaf(&entry))
+	}
+
+	return &stateTree{root: root}, nil
+}
+
+// Root returns the hex root hash of the tree.
+func (t *stateTree) Root() string {
+	return hex.EncodeToString(stateNodeHash(t.root))
+}
+
+// prove returns the entry stored under key and its audit path, ordered from
+// the leaf up to the root as utils.VerifyMerkleProof expects.
+func (t *stateTree) prove(key string) (stateEntry, []utils.MerkleProofStep, bool) {
+	keyHash := stateKeyHash(key)
+
+	var path []utils.MerkleProofStep
+	node := t.root
+	for depth := 0; node != nil && node.entry == nil; depth++ {
+		if stateKeyBit(keyHash, depth) == 0 {
+			path = append(path, utils.MerkleProofStep{Hash: hex.EncodeToString(stateNodeHash(node.right)), Position: utils.MerkleRight})
+			node = node.left
+		} else {
+			path = append(path, utils.MerkleProofStep{Hash: hex.EncodeToString(stateNodeHash(node.left)), Position: utils.MerkleLeft})
+			node = node.right
+		}
+	}
+
+	if node == nil || node.entry.key != key {
+		return stateEntry{}, nil, false
+	}
+
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return *node.entry, path, true
+}
+
+func newStateLeaf(entry *stateEntry) *stateNode {
+	return &stateNode{
+		hash:    utils.MerkleLeafHash(entry.leaf()),
+		entry:   entry,
+		keyHash: stateKeyHash(entry.key),
+	}
+}
+
+func newStateBranch(left, right *stateNode) *stateNode {
+	return &stateNode{
+		hash:  utils.MerkleNodeHash(stateNodeHash(left), stateNodeHash(right)),
+		left:  left,
+		right: right,
+	}
+}
+
+func insertStateNode(node *stateNode, depth int, leaf *stateNode) *stateNode {
+	switch {
+	case node == nil:
+		return leaf
+	case node.entry != nil:
+		if node.entry.key == leaf.entry.key {
+			return leaf
+		}
+		return splitStateLeaves(node, leaf, depth)
+	case stateKeyBit(leaf.keyHash, depth) == 0:
+		return newStateBranch(insertStateNode(node.left, depth+1, leaf), node.right)
+	default:
+		return newStateBranch(node.left, insertStateNode(node.right, depth+1, leaf))
+	}
+}
+
+// splitStateLeaves places two leaves below the first bit where their key
+// hashes differ.
+func splitStateLeaves(a, b *stateNode, depth int) *stateNode {
+	bitA, bitB := stateKeyBit(a.keyHash, depth), stateKeyBit(b.keyHash, depth)
+	switch {
+	case bitA == bitB && bitA == 0:
+		return newStateBranch(splitStateLeaves(a, b, depth+1), nil)
+	case bitA == bitB:
+		return newStateBranch(nil, splitStateLeaves(a, b, depth+1))
+	case bitA == 0:
+		return newStateBranch(a, b)
+	default:
+		return newStateBranch(b, a)
+	}
+}
+
+func deleteStateNode(node *stateNode, depth int, keyHash [sha256.Size]byte) *stateNode {
+	if node == nil {
+		return nil
+	}
+
+	if node.entry != nil {
+		if node.keyHash == keyHash {
+			return nil
+		}
+		return node
+	}
+
+	left, right := node.left, node.right
+	if stateKeyBit(keyHash, depth) == 0 {
+		left = deleteStateNode(left, depth+1, keyHash)
+		if left == node.left {
+			return node
+		}
+	} else {
+		right = deleteStateNode(right, depth+1, keyHash)
+		if right == node.right {
+			return node
+		}
+	}
+
+	// A lone leaf moves up so that it stays at its shortest unique prefix.
+	switch {
+	case left == nil && right == nil:
+		return nil
+	case left == nil && right.entry != nil:
+		return right
+	case right == nil && left.entry != nil:
+		return left
+	}
+
+	return newStateBranch(left, right)
+}
+
+func stateNodeHash(node *stateNode) []byte {
+	if node == nil {
+		return emptyStateHash
+	}
+	return node.hash
+}
+
+func stateKeyHash(key string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(key))
+}
+
+func stateKeyBit(keyHash [sha256.Size]byte, depth int) byte {
+	return (keyHash[depth/8] >> (7 - uint(depth%8))) & 1
+}
@@ -0,0 +1,146 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+func TestStateTreeUpdateMatchesRebuild(t *testing.T) {
+	prev := cloneState(types.State{})
+	tree, err := newStateTree(prev)
+	if err != nil {
+		t.Fatalf("build tree: %v", err)
+	}
+	if tree.Root() != CalculateStateRoot(prev) {
+		t.Fatalf("empty tree root mismatch")
+	}
+
+	for round := 0; round < 8; round++ {
+		next := cloneState(prev)
+		journal := newStateJournal()
+		ctx := TxContext{journal: journal}
+		for i := 0; i < 40; i++ {
+			SetStateEntry(ctx, StateKeyNonce, next.Nonces, fmt.Sprintf("user-%d-%d", round, i), int64(i))
+		}
+		for id, nonce := range prev.Nonces {
+			if len(id)%3 == round%3 {
+				DeleteStateEntry(ctx, StateKeyNonce, next.Nonces, id)
+			} else {
+				SetStateEntry(ctx, StateKeyNonce, next.Nonces, id, nonce+1)
+			}
+		}
+
+		tree, err = tree.update(next, journal.keys)
+		if err != nil {
+			t.Fatalf("update tree: %v", err)
+		}
+		if tree.Root() != CalculateStateRoot(next) {
+			t.Fatalf("round %d: incremental root differs from a rebuilt tree", round)
+		}
+
+		for id, nonce := range next.Nonces {
+			entry, path, ok := tree.prove(StateKeyNonce + id)
+			if !ok {
+				t.Fatalf("round %d: missing key %s", round, id)
+			}
+			if entry.valueHash != stateValueHash(entry.key, []byte(fmt.Sprint(nonce))) {
+				t.Fatalf("round %d: unexpected value for %s", round, id)
+			}
+			if !utils.VerifyMerkleProof(entry.leaf(), path, tree.Root()) {
+				t.Fatalf("round %d: proof for %s does not verify", round, id)
+			}
+		}
+		prev = next
+	}
+
+	if _, _, ok := tree.prove(StateKeyNonce + "absent"); ok {
+		t.Fatalf("expected absent key to have no proof")
+	}
+}
+
+func TestStateProofUsesTreeOfHeight(t *testing.T) {
+	bc := NewBlockchain()
+
+	for i := 1; i <= 3; i++ {
+		wallet := types.Wallet{Address: fmt.Sprintf("0x%d", i), SupabaseUserID: fmt.Sprintf("user-%d", i), PublicKey: "pub", PrivateKeyEncrypted: "enc", CreatedAt: 10}
+		block, err := bc.BuildBlock([]types.Transaction{newCreateWalletTx(t, wallet, int64(20+i))})
+		if err != nil {
+			t.Fatalf("build block: %v", err)
+		}
+		if err := bc.AddBlock(block); err != nil {
+			t.Fatalf("add block: %v", err)
+		}
+	}
+
+	for height := 1; height <= 3; height++ {
+		proof, err := bc.StateProof(height, StateKeyWallet+"user-1")
+		if err != nil {
+			t.Fatalf("state proof at %d: %v", height, err)
+		}
+		if proof.Header.StateRoot != bc.blocks[height].StateRoot || !proof.Verify() {
+			t.Fatalf("expected proof at height %d to verify against its own block", height)
+		}
+	}
+
+	if _, err := bc.StateProof(1, StateKeyWallet+"user-3"); err == nil {
+		t.Fatalf("expected user-3 to be absent at height 1")
+	}
+}
+
+func TestBlockJournalKeepsStateRootInSync(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commit := func(txs ...types.Transaction) {
+		t.Helper()
+		block := commitTransactions(t, bc, txs...)
+		if block.StateRoot != CalculateStateRoot(bc.State()) {
+			t.Fatalf("block %d: journaled root differs from a rebuilt tree", block.Index)
+		}
+	}
+
+	commit(newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commit(newCreateStoryTx(t, alicePriv, "alice", "story-1", 15))
+	first := newContributionTx(t, alicePriv, alice, "story-1", "One", 15)
+	commit(first, newContributionTx(t, bobPriv, bob, "story-1", "Two", 15))
+	commit(newContributionTx(t, alicePriv, alice, "story-1", "Three", 15))
+	commit(newAmendTx(t, alicePriv, "alice", first.TxID, "Uno", 16))
+	commit(newCloseStoryTx(t, alicePriv, "alice", "story-1", 17))
+
+	nft := newStoryNFT(t, bc, "story-1", "nft-1", 20)
+	nft.Edition = 1
+	commit(newProposeMintTx(t, bobPriv, "bob", nft, types.NowUnix()+3600))
+	if len(bc.State().MintProposals) != 1 {
+		t.Fatalf("expected the proposal to wait for alice's approval")
+	}
+	commit(newApproveMintTx(t, alicePriv, "alice", bc.MintProposalsByStory("story-1")[0].ID, 21))
+
+	before := bc.State()
+	transfer := signTestTx(t, bobPriv, TxTypeTransferShares, 30, types.TransferSharesPayload{
+		TokenID: "nft-1", FromID: "bob", ToID: "alice", ToAddress: alice.Address, Amount: 100,
+	})
+	block, err := bc.BuildBlock([]types.Transaction{transfer})
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+	forged := block
+	forged.StateRoot = "forged"
+	forged.Hash = CalculateHash(forged)
+	if err := bc.AddBlock(forged); !errors.Is(err, errStateRootMismatch) {
+		t.Fatalf("expected state root mismatch, got %v", err)
+	}
+	if !reflect.DeepEqual(bc.State(), before) {
+		t.Fatalf("expected building and rejecting blocks to leave the state unchanged")
+	}
+
+	commit(transfer)
+	if bc.ShareBalance("nft-1", "bob") != before.ShareBalances["nft-1"]["bob"]-100 {
+		t.Fatalf("expected the transfer to move bob's units")
+	}
+}
@@ -0,0 +1,198 @@
+package blockchain
+
+import (
+	"errors"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errMissingStoryTitle        = errors.New("blockchain: story title required")
+	errMissingStoryCreator      = errors.New("blockchain: story creator required")
+	errDuplicateStory           = errors.New("blockchain: story already exists")
+	errUnknownStory             = errors.New("blockchain: story not found")
+	errStoryClosed              = errors.New("blockchain: story is closed")
+	errStoryNotClosed           = errors.New("blockchain: story must be closed before minting")
+	errNotStoryCreator          = errors.New("blockchain: only the story creator can close the story")
+	errStoryTimestampMismatch   = errors.New("blockchain: story created_at does not match transaction timestamp")
+	errAuthorshipPolicyMismatch = errors.New("blockchain: nft authorship policy does not match story rules")
+	errUnexpectedLineage        = errors.New("blockchain: create_story cannot set a lineage, use fork_story")
+)
+
+// Exported errors for story lifecycle validation.
+var (
+	ErrUnknownStory    = errUnknownStory
+	ErrStoryClosed     = errStoryClosed
+	ErrStoryNotClosed  = errStoryNotClosed
+	ErrNotStoryCreator = errNotStoryCreator
+)
+
+// createStoryHandler registers a new open story on behalf of its creator.
+type createStoryHandler struct{}
+
+func (createStoryHandler) Type() string { return TxTypeCreateStory }
+
+func (createStoryHandler) Signer(payload interface{}) string {
+	return payload.(types.CreateStoryPayload).Story.CreatorID
+}
+
+func (createStoryHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.CreateStoryPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (createStoryHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	story := decoded.(types.CreateStoryPayload).Story
+
+	if story.Lineage != nil {
+		return errUnexpectedLineage
+	}
+
+	return validateNewStory(state, tx, story)
+}
+
+func (createStoryHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	registerStory(ctx, state, decoded.(types.CreateStoryPayload).Story)
+	return nil
+}
+
+// validateNewStory checks a story registered by create_story or fork_story.
+func validateNewStory(state types.State, tx types.Transaction, story types.StoryRecord) error {
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if story.ID == "" {
+		return errMissingStoryID
+	}
+
+	if story.Title == "" {
+		return errMissingStoryTitle
+	}
+
+	if story.CreatorID == "" {
+		return errMissingStoryCreator
+	}
+
+	if story.CreatedAt != tx.Timestamp {
+		return errStoryTimestampMismatch
+	}
+
+	if err := ValidateStoryRules(story.Rules, story.CreatedAt); err != nil {
+		return err
+	}
+
+	if _, exists := state.StoryRegistry[story.ID]; exists {
+		return errDuplicateStory
+	}
+
+	creator, ok := state.WalletRegistry[story.CreatorID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, creator, tx)
+}
+
+// registerStory stores a new open story; fields derived by the chain are
+// reset so the payload cannot preset them.
+func registerStory(ctx TxContext, state *types.State, story types.StoryRecord) {
+	story.Status = types.StoryStatusOpen
+	story.ClosedAt = 0
+	story.BlockIndex = ctx.BlockIndex
+	story.Progress = types.StoryProgress{}
+	SetStateEntry(ctx, StateKeyStory, state.StoryRegistry, story.ID, story)
+}
+
+// closeStoryHandler stops a story from accepting contributions so it can be minted.
+type closeStoryHandler struct{}
+
+func (closeStoryHandler) Type() string { return TxTypeCloseStory }
+
+func (closeStoryHandler) Signer(payload interface{}) string {
+	return payload.(types.CloseStoryPayload).ClosedBy
+}
+
+func (closeStoryHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.CloseStoryPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (closeStoryHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	closing := decoded.(types.CloseStoryPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	story, err := openStory(state, closing.StoryID)
+	if err != nil {
+		return err
+	}
+
+	if closing.ClosedBy != story.CreatorID {
+		return errNotStoryCreator
+	}
+
+	creator, ok := state.WalletRegistry[story.CreatorID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, creator, tx)
+}
+
+func (closeStoryHandler) Apply(ctx TxContext, state *types.State, tx types.Transaction, decoded interface{}) error {
+	closing := decoded.(types.CloseStoryPayload)
+
+	story := state.StoryRegistry[closing.StoryID]
+	story.Status = types.StoryStatusClosed
+	story.ClosedAt = tx.Timestamp
+	SetStateEntry(ctx, StateKeyStory, state.StoryRegistry, closing.StoryID, story)
+	return nil
+}
+
+// openStory returns the story when it exists and still accepts contributions.
+func openStory(state types.State, storyID string) (types.StoryRecord, error) {
+	if storyID == "" {
+		return types.StoryRecord{}, errMissingStoryID
+	}
+
+	story, ok := state.StoryRegistry[storyID]
+	if !ok {
+		return types.StoryRecord{}, errUnknownStory
+	}
+
+	if story.Status != types.StoryStatusOpen {
+		return types.StoryRecord{}, errStoryClosed
+	}
+
+	return story, nil
+}
+
+// GetStory returns the on-chain record of a story.
+func (bc *Blockchain) GetStory(storyID string) (types.StoryRecord, bool) {
+	if storyID == "" {
+		return types.StoryRecord{}, false
+	}
+
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	story, ok := bc.state.StoryRegistry[storyID]
+	return story, ok
+}
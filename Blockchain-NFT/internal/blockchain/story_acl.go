@@ -0,0 +1,279 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errNotStoryOwner       = errors.New("blockchain: only the story creator can manage contributors")
+	errAlreadyInvited      = errors.New("blockchain: user already invited to the story")
+	errNoPendingInvite     = errors.New("blockchain: no pending invite for the story")
+	errNotStoryMember      = errors.New("blockchain: user is not invited to the story")
+	errNotStoryContributor = errors.New("blockchain: contributor is not a member of the story")
+)
+
+// Exported errors for story access control.
+var (
+	ErrNotStoryOwner       = errNotStoryOwner
+	ErrNotStoryContributor = errNotStoryContributor
+)
+
+// StoryMember is one entry of a story ACL.
+type StoryMember struct {
+	UserID string `json:"user_id"`
+	Status string `json:"status"`
+}
+
+// canContribute reports whether the user may add lines to the story. Open
+// stories accept everyone; invite-only stories accept the creator and
+// members who accepted their invite.
+func canContribute(state types.State, story types.StoryRecord, userID string) bool {
+	if !story.Rules.InviteOnly || userID == story.CreatorID {
+		return true
+	}
+	return state.StoryMembers[story.ID][userID] == types.MemberStatusActive
+}
+
+// ownedOpenStory returns the open story when actor is its creator.
+func ownedOpenStory(state types.State, storyID, actor string) (types.StoryRecord, error) {
+	story, err := openStory(state, storyID)
+	if err != nil {
+		return types.StoryRecord{}, err
+	}
+
+	if actor == "" || actor != story.CreatorID {
+		return types.StoryRecord{}, errNotStoryOwner
+	}
+
+	return story, nil
+}
+
+// inviteContributorHandler adds a pending invite to a story ACL.
+type inviteContributorHandler struct{}
+
+func (inviteContributorHandler) Type() string { return TxTypeInviteContributor }
+
+func (inviteContributorHandler) Signer(payload interface{}) string {
+	return payload.(types.InviteContributorPayload).InvitedBy
+}
+
+func (inviteContributorHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.InviteContributorPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (inviteContributorHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	invite := decoded.(types.InviteContributorPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	story, err := ownedOpenStory(state, invite.StoryID, invite.InvitedBy)
+	if err != nil {
+		return err
+	}
+
+	if invite.InviteeID == "" {
+		return errMissingWalletID
+	}
+
+	if invite.InviteeID == story.CreatorID {
+		return errAlreadyInvited
+	}
+
+	if _, exists := state.StoryMembers[story.ID][invite.InviteeID]; exists {
+		return errAlreadyInvited
+	}
+
+	if _, ok := state.WalletRegistry[invite.InviteeID]; !ok {
+		return errMissingWallet
+	}
+
+	creator, ok := state.WalletRegistry[story.CreatorID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, creator, tx)
+}
+
+func (inviteContributorHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	invite := decoded.(types.InviteContributorPayload)
+
+	members := copyStoryMembers(state.StoryMembers[invite.StoryID])
+	members[invite.InviteeID] = types.MemberStatusInvited
+	SetStateEntry(ctx, StateKeyACL, state.StoryMembers, invite.StoryID, members)
+	return nil
+}
+
+// acceptInviteHandler turns a pending invite into membership.
+type acceptInviteHandler struct{}
+
+func (acceptInviteHandler) Type() string { return TxTypeAcceptInvite }
+
+func (acceptInviteHandler) Signer(payload interface{}) string {
+	return payload.(types.AcceptInvitePayload).UserID
+}
+
+func (acceptInviteHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.AcceptInvitePayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (acceptInviteHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	accept := decoded.(types.AcceptInvitePayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if _, err := openStory(state, accept.StoryID); err != nil {
+		return err
+	}
+
+	if state.StoryMembers[accept.StoryID][accept.UserID] != types.MemberStatusInvited {
+		return errNoPendingInvite
+	}
+
+	invitee, ok := state.WalletRegistry[accept.UserID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, invitee, tx)
+}
+
+func (acceptInviteHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	accept := decoded.(types.AcceptInvitePayload)
+
+	members := copyStoryMembers(state.StoryMembers[accept.StoryID])
+	members[accept.UserID] = types.MemberStatusActive
+	SetStateEntry(ctx, StateKeyACL, state.StoryMembers, accept.StoryID, members)
+	return nil
+}
+
+// revokeContributorHandler removes an invite or a membership from a story ACL.
+type revokeContributorHandler struct{}
+
+func (revokeContributorHandler) Type() string { return TxTypeRevokeContributor }
+
+func (revokeContributorHandler) Signer(payload interface{}) string {
+	return payload.(types.RevokeContributorPayload).RevokedBy
+}
+
+func (revokeContributorHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.RevokeContributorPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (revokeContributorHandler) Validate(_ TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	revoke := decoded.(types.RevokeContributorPayload)
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	story, err := ownedOpenStory(state, revoke.StoryID, revoke.RevokedBy)
+	if err != nil {
+		return err
+	}
+
+	if _, exists := state.StoryMembers[story.ID][revoke.ContributorID]; !exists {
+		return errNotStoryMember
+	}
+
+	creator, ok := state.WalletRegistry[story.CreatorID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	return verifyWalletSignature(state.ChainID, creator, tx)
+}
+
+func (revokeContributorHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, decoded interface{}) error {
+	revoke := decoded.(types.RevokeContributorPayload)
+
+	members := copyStoryMembers(state.StoryMembers[revoke.StoryID])
+	delete(members, revoke.ContributorID)
+	if len(members) == 0 {
+		DeleteStateEntry(ctx, StateKeyACL, state.StoryMembers, revoke.StoryID)
+		return nil
+	}
+	SetStateEntry(ctx, StateKeyACL, state.StoryMembers, revoke.StoryID, members)
+	return nil
+}
+
+// copyStoryMembers copies a story ACL so the state entry is replaced rather
+// than mutated.
+func copyStoryMembers(members map[string]string) map[string]string {
+	copied := make(map[string]string, len(members)+1)
+	for userID, status := range members {
+		copied[userID] = status
+	}
+	return copied
+}
+
+// StoryMembers returns the ACL of a story ordered by user ID.
+func (bc *Blockchain) StoryMembers(storyID string) []StoryMember {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	members := bc.state.StoryMembers[storyID]
+	if len(members) == 0 {
+		return nil
+	}
+
+	list := make([]StoryMember, 0, len(members))
+	for userID, status := range members {
+		list = append(list, StoryMember{UserID: userID, Status: status})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].UserID < list[j].UserID })
+	return list
+}
+
+// CanContribute reports whether the user may currently contribute to the story.
+func (bc *Blockchain) CanContribute(storyID, userID string) bool {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	story, err := openStory(bc.state, storyID)
+	if err != nil {
+		return false
+	}
+	return canContribute(bc.state, story, userID)
+}
+
+// StoryMemberStatus returns the ACL status of a user in a story, or an empty
+// string when the user was never invited.
+func (bc *Blockchain) StoryMemberStatus(storyID, userID string) string {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	return bc.state.StoryMembers[storyID][userID]
+}
@@ -0,0 +1,92 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestInviteOnlyStoryMembership(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	carol, carolPriv := newKeyedWallet(t, "carol")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10), newCreateWalletTx(t, carol, 10))
+
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Members only",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{InviteOnly: true},
+		CreatedAt: 20,
+	}}))
+
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "The creator may always write.", 30))
+
+	if _, err := bc.BuildBlock([]types.Transaction{newContributionTx(t, bobPriv, bob, "story-1", "Uninvited", 31)}); !errors.Is(err, ErrNotStoryContributor) {
+		t.Fatalf("expected uninvited contribution to fail, got %v", err)
+	}
+
+	invite := func(priv, invitedBy, invitee string, ts int64) types.Transaction {
+		return signTestTx(t, priv, TxTypeInviteContributor, ts, types.InviteContributorPayload{StoryID: "story-1", InviteeID: invitee, InvitedBy: invitedBy})
+	}
+	accept := func(priv, userID string, ts int64) types.Transaction {
+		return signTestTx(t, priv, TxTypeAcceptInvite, ts, types.AcceptInvitePayload{StoryID: "story-1", UserID: userID})
+	}
+	revoke := func(priv, revokedBy, contributor string, ts int64) types.Transaction {
+		return signTestTx(t, priv, TxTypeRevokeContributor, ts, types.RevokeContributorPayload{StoryID: "story-1", ContributorID: contributor, RevokedBy: revokedBy})
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{invite(bobPriv, "bob", "carol", 40)}); !errors.Is(err, ErrNotStoryOwner) {
+		t.Fatalf("expected invite by non-creator to fail, got %v", err)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{accept(bobPriv, "bob", 40)}); !errors.Is(err, errNoPendingInvite) {
+		t.Fatalf("expected accept without invite to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, invite(alicePriv, "alice", "bob", 40))
+
+	if _, err := bc.BuildBlock([]types.Transaction{invite(alicePriv, "alice", "bob", 41)}); !errors.Is(err, errAlreadyInvited) {
+		t.Fatalf("expected duplicate invite to fail, got %v", err)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{newContributionTx(t, bobPriv, bob, "story-1", "Invited but pending", 41)}); !errors.Is(err, ErrNotStoryContributor) {
+		t.Fatalf("expected contribution with pending invite to fail, got %v", err)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{accept(carolPriv, "bob", 42)}); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected accept signed by another wallet to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, accept(bobPriv, "bob", 42))
+
+	members := bc.StoryMembers("story-1")
+	if len(members) != 1 || members[0].UserID != "bob" || members[0].Status != types.MemberStatusActive {
+		t.Fatalf("unexpected members: %+v", members)
+	}
+	if !bc.CanContribute("story-1", "bob") || bc.CanContribute("story-1", "carol") {
+		t.Fatalf("unexpected contribution permissions")
+	}
+
+	commitTransactions(t, bc, newContributionTx(t, bobPriv, bob, "story-1", "Now I belong.", 50))
+
+	if _, err := bc.BuildBlock([]types.Transaction{revoke(alicePriv, "alice", "carol", 60)}); !errors.Is(err, errNotStoryMember) {
+		t.Fatalf("expected revoking a stranger to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, revoke(alicePriv, "alice", "bob", 60))
+
+	if members := bc.StoryMembers("story-1"); len(members) != 0 {
+		t.Fatalf("expected empty ACL after revoke, got %+v", members)
+	}
+	if _, ok := bc.State().StoryMembers["story-1"]; ok {
+		t.Fatalf("expected empty ACL to be dropped from state")
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{newContributionTx(t, bobPriv, bob, "story-1", "Revoked", 70)}); !errors.Is(err, ErrNotStoryContributor) {
+		t.Fatalf("expected contribution after revoke to fail, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var errUnknownParentContribution = errors.New("blockchain: parent contribution not found in the story")
+
+// ErrUnknownParentContribution is returned for contributions following a line
+// that is not part of their story.
+var ErrUnknownParentContribution = errUnknownParentContribution
+
+// lineNode is a contribution's place in the story graph. sequence is the
+// order in which the chain committed the story's lines.
+type lineNode struct {
+	txID     string
+	parent   string
+	sequence int
+}
+
+// answersFirst orders replies to the same line: the reply committed first
+// wins. The order comes from the blocks, not from the timestamps signers
+// choose, so a late reply cannot be backdated onto the main line.
+func answersFirst(a, b lineNode) bool {
+	return a.sequence < b.sequence
+}
+
+// childrenOf groups nodes by the line they follow, each group ordered with
+// answersFirst.
+func childrenOf(nodes []lineNode) map[string][]lineNode {
+	children := make(map[string][]lineNode)
+	for _, node := range nodes {
+		children[node.parent] = append(children[node.parent], node)
+	}
+
+	for _, replies := range children {
+		sort.Slice(replies, func(i, j int) bool { return answersFirst(replies[i], replies[j]) })
+	}
+
+	return children
+}
+
+// mainLine linearises a story graph: starting at the story's first line it
+// follows the reply that answersFirst at every step. Replies left off the
+// main line are branches and are reported by StoryConflicts.
+func mainLine(nodes []lineNode) []string {
+	children := childrenOf(nodes)
+
+	var line []string
+	for parent := ""; ; {
+		replies := children[parent]
+		if len(replies) == 0 {
+			return line
+		}
+		parent = replies[0].txID
+		line = append(line, parent)
+	}
+}
+
+// checkParentContribution makes sure an explicit parent is a line of the
+// same story. An empty parent means the last line of the story's main line.
+func checkParentContribution(state types.State, contribution types.Contribution) error {
+	if contribution.ParentTxID == "" {
+		return nil
+	}
+
+	parent, ok := state.Contributions[contribution.ParentTxID]
+	if !ok || parent.StoryID != contribution.StoryID {
+		return errUnknownParentContribution
+	}
+
+	return nil
+}
+
+// stateLineNodes collects the graph of a story's own lines from the state.
+func stateLineNodes(state types.State, storyID string) []lineNode {
+	var nodes []lineNode
+	for txID, record := range state.Contributions {
+		if record.StoryID == storyID {
+			nodes = append(nodes, lineNode{txID: txID, parent: record.ParentTxID, sequence: record.Sequence})
+		}
+	}
+	return nodes
+}
+
+// stateLineAncestry returns the line txID and every line it follows, root
+// first. At the first line of a fork it continues in the parent story from
+// the fork's branch point.
+func stateLineAncestry(state types.State, txID string) []string {
+	var ancestry []string
+	for txID != "" {
+		record, ok := state.Contributions[txID]
+		if !ok {
+			break
+		}
+		ancestry = append(ancestry, txID)
+
+		txID = record.ParentTxID
+		if lineage := state.StoryRegistry[record.StoryID].Lineage; txID == "" && lineage != nil {
+			txID = lineage.BranchTxID
+		}
+	}
+
+	for i, j := 0, len(ancestry)-1; i < j; i, j = i+1, j-1 {
+		ancestry[i], ancestry[j] = ancestry[j], ancestry[i]
+	}
+	return ancestry
+}
+
+// storyHasLine reports whether txID is one of the story's own lines or one of
+// the lines it inherited.
+func storyHasLine(state types.State, storyID, txID string) bool {
+	record, ok := state.Contributions[txID]
+	if !ok {
+		return false
+	}
+	if record.StoryID == storyID {
+		return true
+	}
+
+	lineage := state.StoryRegistry[storyID].Lineage
+	if lineage == nil {
+		return false
+	}
+	for _, inherited := range stateLineAncestry(state, lineage.BranchTxID) {
+		if inherited == txID {
+			return true
+		}
+	}
+	return false
+}
+
+// stateStoryLines returns the main line of a story as the state records it,
+// with the lines inherited from the parent story first and retracted lines
+// included.
+func stateStoryLines(state types.State, storyID string) []types.Contribution {
+	var txIDs []string
+	if lineage := state.StoryRegistry[storyID].Lineage; lineage != nil {
+		txIDs = stateLineAncestry(state, lineage.BranchTxID)
+	}
+	txIDs = append(txIDs, mainLine(stateLineNodes(state, storyID))...)
+
+	lines := make([]types.Contribution, 0, len(txIDs))
+	for _, txID := range txIDs {
+		record := state.Contributions[txID]
+		lines = append(lines, types.Contribution{
+			TxID:          txID,
+			ParentTxID:    record.ParentTxID,
+			ContributorID: record.ContributorID,
+			WalletAddress: state.WalletRegistry[record.ContributorID].Address,
+			StoryID:       record.StoryID,
+			StoryLine:     record.StoryLine,
+			Timestamp:     record.Timestamp,
+		})
+	}
+
+	return lines
+}
+
+// StoryConflict lists the live replies to one line when there is more than
+// one. MainTxID is the reply kept on the main line; it may be retracted, in
+// which case the main line continues through it without showing its text.
+type StoryConflict struct {
+	ParentTxID string               `json:"parent_tx_id"`
+	MainTxID   string               `json:"main_tx_id"`
+	Branches   []types.Contribution `json:"branches"`
+}
+
+// StoryConflicts returns every line of the story, inherited lines excluded,
+// that received more than one live reply, earliest conflict first.
+func (bc *Blockchain) StoryConflicts(storyID string) []StoryConflict {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	indexed := bc.storyContributions[storyID]
+	byTxID := make(map[string]types.Contribution, len(indexed))
+	nodes := indexedLineNodes(indexed)
+	nodeOf := make(map[string]lineNode, len(nodes))
+	for i, contribution := range indexed {
+		byTxID[contribution.TxID] = contribution
+		nodeOf[contribution.TxID] = nodes[i]
+	}
+
+	var conflicts []StoryConflict
+	for parent, replies := range childrenOf(nodes) {
+		conflict := StoryConflict{ParentTxID: parent, MainTxID: replies[0].txID}
+		for _, reply := range replies {
+			if !bc.state.Contributions[reply.txID].Retracted {
+				conflict.Branches = append(conflict.Branches, byTxID[reply.txID])
+			}
+		}
+		if len(conflict.Branches) > 1 {
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return answersFirst(nodeOf[conflicts[i].MainTxID], nodeOf[conflicts[j].MainTxID])
+	})
+
+	return conflicts
+}
+
+// indexedLineNodes builds the graph of a story from the index, which lists
+// the story's lines in commit order.
+func indexedLineNodes(indexed []types.Contribution) []lineNode {
+	nodes := make([]lineNode, len(indexed))
+	for i, contribution := range indexed {
+		nodes[i] = lineNode{txID: contribution.TxID, parent: contribution.ParentTxID, sequence: i}
+	}
+	return nodes
+}
@@ -0,0 +1,150 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+)
+
+func newReplyTx(t *testing.T, priv string, wallet types.Wallet, storyID, parentTxID, line string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeContribution, timestamp, types.ContributionPayload{Contribution: types.Contribution{
+		ParentTxID:    parentTxID,
+		ContributorID: wallet.SupabaseUserID,
+		WalletAddress: wallet.Address,
+		StoryID:       storyID,
+		StoryLine:     line,
+		Timestamp:     timestamp,
+	}})
+}
+
+func TestStoryMainLineFollowsFirstCommittedReply(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 20))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-2", 20))
+
+	first := newContributionTx(t, alicePriv, alice, "story-1", "Once upon a time", 30)
+	commitTransactions(t, bc, first)
+	kept := newReplyTx(t, alicePriv, alice, "story-1", first.TxID, "a chain was forged", 40)
+	commitTransactions(t, bc, kept)
+
+	// A line without a parent follows the last line of the main line.
+	next := newContributionTx(t, alicePriv, alice, "story-1", "and nobody noticed", 50)
+	commitTransactions(t, bc, next)
+
+	record, ok := bc.GetContribution(next.TxID)
+	if !ok || record.ParentTxID != kept.TxID {
+		t.Fatalf("expected implicit parent %s, got %+v", kept.TxID, record)
+	}
+
+	// Bob answers the first line late and backdates his reply; the line
+	// committed first stays on the main line.
+	backdated := newReplyTx(t, bobPriv, bob, "story-1", first.TxID, "a dragon woke", 31)
+	commitTransactions(t, bc, backdated)
+
+	lines := bc.StoryContributions("story-1")
+	if len(lines) != 3 || lines[0].TxID != first.TxID || lines[1].TxID != kept.TxID || lines[2].TxID != next.TxID {
+		t.Fatalf("unexpected main line: %+v", lines)
+	}
+
+	conflicts := bc.StoryConflicts("story-1")
+	if len(conflicts) != 1 || conflicts[0].ParentTxID != first.TxID || conflicts[0].MainTxID != kept.TxID || len(conflicts[0].Branches) != 2 {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+	if conflicts[0].Branches[1].TxID != backdated.TxID {
+		t.Fatalf("expected the backdated reply as a branch, got %+v", conflicts[0].Branches)
+	}
+
+	// Retracting the losing branch resolves the conflict.
+	commitTransactions(t, bc, newRetractTx(t, bobPriv, "bob", backdated.TxID, 60))
+	if conflicts := bc.StoryConflicts("story-1"); len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts after retraction, got %+v", conflicts)
+	}
+
+	other := newContributionTx(t, alicePriv, alice, "story-2", "Elsewhere", 70)
+	commitTransactions(t, bc, other)
+
+	if _, err := bc.BuildBlock([]types.Transaction{newReplyTx(t, bobPriv, bob, "story-1", "missing", "Dangling", 80)}); !errors.Is(err, ErrUnknownParentContribution) {
+		t.Fatalf("expected dangling parent to fail, got %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{newReplyTx(t, bobPriv, bob, "story-1", other.TxID, "Crossed", 80)}); !errors.Is(err, ErrUnknownParentContribution) {
+		t.Fatalf("expected parent from another story to fail, got %v", err)
+	}
+}
+
+func TestImplicitParentFollowsMainLine(t *testing.T) {
+	store, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	bc := NewBlockchain()
+	if err := bc.WithStorage(store); err != nil {
+		t.Fatalf("attach storage failed: %v", err)
+	}
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	carol, carolPriv := newKeyedWallet(t, "carol")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10), newCreateWalletTx(t, carol, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 20))
+
+	first := newContributionTx(t, alicePriv, alice, "story-1", "Once upon a time", 30)
+	commitTransactions(t, bc, first)
+	kept := newReplyTx(t, bobPriv, bob, "story-1", first.TxID, "a chain was forged", 40)
+	commitTransactions(t, bc, kept)
+	losing := newReplyTx(t, carolPriv, carol, "story-1", first.TxID, "a dragon woke", 41)
+	commitTransactions(t, bc, losing)
+
+	// The latest line is on a losing branch; a line without a parent still
+	// continues the main line.
+	next := newContributionTx(t, alicePriv, alice, "story-1", "and nobody noticed", 50)
+	commitTransactions(t, bc, next)
+
+	restored, err := LoadBlockchain(store)
+	if err != nil {
+		t.Fatalf("load blockchain failed: %v", err)
+	}
+
+	for name, chain := range map[string]*Blockchain{"live": bc, "restored": restored} {
+		if record, ok := chain.GetContribution(next.TxID); !ok || record.ParentTxID != kept.TxID {
+			t.Fatalf("%s: expected implicit parent %s, got %+v", name, kept.TxID, record)
+		}
+
+		lines := chain.StoryContributions("story-1")
+		if len(lines) != 3 || lines[0].TxID != first.TxID || lines[1].TxID != kept.TxID || lines[2].TxID != next.TxID {
+			t.Fatalf("%s: unexpected main line: %+v", name, lines)
+		}
+	}
+}
+
+func TestMainLineIgnoresNodeOrder(t *testing.T) {
+	nodes := []lineNode{
+		{txID: "a", sequence: 0},
+		{txID: "c", parent: "a", sequence: 2},
+		{txID: "b", parent: "a", sequence: 1},
+		{txID: "d", parent: "c", sequence: 3},
+		{txID: "e", parent: "b", sequence: 4},
+	}
+
+	want := []string{"a", "b", "e"}
+	for _, order := range [][]lineNode{nodes, {nodes[4], nodes[3], nodes[2], nodes[1], nodes[0]}} {
+		got := mainLine(order)
+		if len(got) != len(want) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("expected %v, got %v", want, got)
+			}
+		}
+	}
+}
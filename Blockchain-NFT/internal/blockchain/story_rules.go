@@ -0,0 +1,157 @@
+package blockchain
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"storytelling-blockchain/internal/types"
+)
+
+var (
+	errInvalidStoryRules    = errors.New("blockchain: invalid story rules")
+	errTooManyConsecutive   = errors.New("blockchain: contributor reached the max consecutive lines")
+	errAlternationRequired  = errors.New("blockchain: story requires another author to write the next line")
+	errLineTooShort         = errors.New("blockchain: story line shorter than the story minimum")
+	errLineTooLong          = errors.New("blockchain: story line longer than the story maximum")
+	errTooManyContributors  = errors.New("blockchain: story reached its max contributors")
+	errContributionDeadline = errors.New("blockchain: story contribution deadline has passed")
+)
+
+// Exported errors for story rule violations.
+var (
+	ErrInvalidStoryRules    = errInvalidStoryRules
+	ErrTooManyConsecutive   = errTooManyConsecutive
+	ErrAlternationRequired  = errAlternationRequired
+	ErrLineTooShort         = errLineTooShort
+	ErrLineTooLong          = errLineTooLong
+	ErrTooManyContributors  = errTooManyContributors
+	ErrContributionDeadline = errContributionDeadline
+)
+
+// ValidateStoryRules checks that the rules of a story created at createdAt
+// are consistent.
+func ValidateStoryRules(rules types.StoryRules, createdAt int64) error {
+	if _, err := AuthorshipPolicyByName(rules.AuthorshipPolicy); err != nil {
+		return err
+	}
+
+	if rules.MaxConsecutiveLines < 0 || rules.MinLineLength < 0 || rules.MaxLineLength < 0 || rules.MaxContributors < 0 || rules.Deadline < 0 ||
+		rules.MintAtContributions < 0 || rules.MintAtAuthors < 0 || rules.MintThreshold < 0 || rules.MintThreshold > TotalShareUnits {
+		return errInvalidStoryRules
+	}
+
+	if rules.MaxLineLength > 0 && rules.MinLineLength > rules.MaxLineLength {
+		return errInvalidStoryRules
+	}
+
+	if (rules.MintAtContributions > 0 || rules.MintAtAuthors > 0) && !rules.MintWhileOpen {
+		return errInvalidStoryRules
+	}
+
+	if rules.Deadline > 0 && rules.Deadline <= createdAt {
+		return errInvalidStoryRules
+	}
+
+	return nil
+}
+
+// LineLength is the length of a story line as measured by the line rules:
+// characters after trimming surrounding whitespace.
+func LineLength(line string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(line))
+}
+
+// checkStoryRules reports the first rule the contribution would break given
+// the story's progress so far. The deadline is checked against the block
+// time rather than the contribution timestamp, which the signer chooses.
+func checkStoryRules(ctx TxContext, story types.StoryRecord, contribution types.Contribution) error {
+	rules := story.Rules
+	progress := story.Progress
+
+	if rules.Deadline > 0 && ctx.BlockTimestamp > rules.Deadline {
+		return errContributionDeadline
+	}
+
+	if err := CheckLineLength(rules, contribution.StoryLine); err != nil {
+		return err
+	}
+
+	if progress.LastContributorID == contribution.ContributorID {
+		if rules.RequireAlternation {
+			return errAlternationRequired
+		}
+		if rules.MaxConsecutiveLines > 0 && progress.ConsecutiveLines >= rules.MaxConsecutiveLines {
+			return errTooManyConsecutive
+		}
+	}
+
+	if rules.MaxContributors > 0 && !hasContributed(progress, contribution.ContributorID) && len(progress.Contributors) >= rules.MaxContributors {
+		return errTooManyContributors
+	}
+
+	return nil
+}
+
+// CheckLineLength applies the line length bounds of the rules.
+func CheckLineLength(rules types.StoryRules, line string) error {
+	length := LineLength(line)
+	if rules.MinLineLength > 0 && length < rules.MinLineLength {
+		return errLineTooShort
+	}
+	if rules.MaxLineLength > 0 && length > rules.MaxLineLength {
+		return errLineTooLong
+	}
+	return nil
+}
+
+func hasContributed(progress types.StoryProgress, userID string) bool {
+	i := sort.SearchStrings(progress.Contributors, userID)
+	return i < len(progress.Contributors) && progress.Contributors[i] == userID
+}
+
+// advanceProgress records an accepted contribution. The contributor list is
+// rebuilt rather than appended to because cloned states share it.
+func advanceProgress(progress types.StoryProgress, contributorID string) types.StoryProgress {
+	if !hasContributed(progress, contributorID) {
+		contributors := make([]string, 0, len(progress.Contributors)+1)
+		contributors = append(contributors, progress.Contributors...)
+		contributors = append(contributors, contributorID)
+		sort.Strings(contributors)
+		progress.Contributors = contributors
+	}
+
+	if progress.LastContributorID == contributorID {
+		progress.ConsecutiveLines++
+	} else {
+		progress.LastContributorID = contributorID
+		progress.ConsecutiveLines = 1
+	}
+
+	progress.LineCount++
+	return progress
+}
+
+// CheckContribution runs the story rules against the current state and time
+// so clients can reject a line before submitting it. It does not check the
+// signature or the wallet.
+func (bc *Blockchain) CheckContribution(contribution types.Contribution) error {
+	bc.mu.RLock()
+	defer bc.mu.RUnlock()
+
+	story, err := openStory(bc.state, contribution.StoryID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkParentContribution(bc.state, contribution); err != nil {
+		return err
+	}
+
+	if !canContribute(bc.state, story, contribution.ContributorID) {
+		return errNotStoryContributor
+	}
+
+	return checkStoryRules(TxContext{BlockIndex: len(bc.blocks), BlockTimestamp: types.NowUnix()}, story, contribution)
+}
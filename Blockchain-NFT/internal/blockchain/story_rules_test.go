@@ -0,0 +1,129 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestValidateStoryRules(t *testing.T) {
+	cases := []struct {
+		name  string
+		rules types.StoryRules
+		want  error
+	}{
+		{name: "empty", rules: types.StoryRules{}},
+		{name: "full", rules: types.StoryRules{MaxConsecutiveLines: 2, MinLineLength: 5, MaxLineLength: 80, MaxContributors: 4, Deadline: 200, RequireAlternation: true}},
+		{name: "negative cap", rules: types.StoryRules{MaxContributors: -1}, want: ErrInvalidStoryRules},
+		{name: "negative mint trigger", rules: types.StoryRules{MintAtAuthors: -1}, want: ErrInvalidStoryRules},
+		{name: "milestone trigger on a closed-only story", rules: types.StoryRules{MintAtContributions: 2}, want: ErrInvalidStoryRules},
+		{name: "milestone trigger while open", rules: types.StoryRules{MintAtContributions: 2, MintWhileOpen: true}},
+		{name: "mint threshold above the total", rules: types.StoryRules{MintThreshold: TotalShareUnits + 1}, want: ErrInvalidStoryRules},
+		{name: "min above max", rules: types.StoryRules{MinLineLength: 10, MaxLineLength: 5}, want: ErrInvalidStoryRules},
+		{name: "deadline before creation", rules: types.StoryRules{Deadline: 100}, want: ErrInvalidStoryRules},
+		{name: "unknown policy", rules: types.StoryRules{AuthorshipPolicy: "loudest"}, want: ErrUnknownAuthorshipPolicy},
+	}
+
+	for _, tc := range cases {
+		if err := ValidateStoryRules(tc.rules, 100); !errors.Is(err, tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+}
+
+func TestStoryRulesRejectContributions(t *testing.T) {
+	now := int64(50)
+	originalNow := types.NowUnix
+	types.NowUnix = func() int64 { return now }
+	t.Cleanup(func() { types.NowUnix = originalNow })
+
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	carol, carolPriv := newKeyedWallet(t, "carol")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10), newCreateWalletTx(t, carol, 10))
+
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Rules",
+		CreatorID: "alice",
+		Rules: types.StoryRules{
+			MaxConsecutiveLines: 2,
+			MinLineLength:       3,
+			MaxLineLength:       20,
+			MaxContributors:     2,
+			Deadline:            100,
+		},
+		CreatedAt: 20,
+	}}))
+
+	reject := func(tx types.Transaction, want error) {
+		t.Helper()
+		if _, err := bc.BuildBlock([]types.Transaction{tx}); !errors.Is(err, want) {
+			t.Fatalf("expected %v, got %v", want, err)
+		}
+	}
+
+	reject(newContributionTx(t, alicePriv, alice, "story-1", " hi ", 30), ErrLineTooShort)
+	reject(newContributionTx(t, alicePriv, alice, "story-1", "this line is far too long to fit", 30), ErrLineTooLong)
+
+	// Two lines in a row are allowed within one block; a third is not.
+	commitTransactions(t, bc,
+		newContributionTx(t, alicePriv, alice, "story-1", "First line", 30),
+		signTestTxWithNonce(t, alicePriv, bc.NextNonce("alice")+1, TxTypeContribution, 31, types.ContributionPayload{Contribution: types.Contribution{
+			ContributorID: "alice", WalletAddress: alice.Address, StoryID: "story-1", StoryLine: "Second line", Timestamp: 31,
+		}}),
+	)
+	reject(newContributionTx(t, alicePriv, alice, "story-1", "Third line", 32), ErrTooManyConsecutive)
+
+	commitTransactions(t, bc, newContributionTx(t, bobPriv, bob, "story-1", "Bob joins", 40))
+	reject(newContributionTx(t, carolPriv, carol, "story-1", "Carol too", 41), ErrTooManyContributors)
+
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Alice again", 50))
+
+	// The deadline follows the block time, so a backdated line is rejected too.
+	now = 101
+	reject(newContributionTx(t, bobPriv, bob, "story-1", "Too late", 101), ErrContributionDeadline)
+	reject(newContributionTx(t, bobPriv, bob, "story-1", "Backdated", 90), ErrContributionDeadline)
+	now = 60
+
+	story, _ := bc.GetStory("story-1")
+	progress := story.Progress
+	if progress.LineCount != 4 || progress.LastContributorID != "alice" || progress.ConsecutiveLines != 1 || len(progress.Contributors) != 2 {
+		t.Fatalf("unexpected progress: %+v", progress)
+	}
+
+	err := bc.CheckContribution(types.Contribution{StoryID: "story-1", ContributorID: "carol", StoryLine: "Hello", Timestamp: 60})
+	if !errors.Is(err, ErrTooManyContributors) {
+		t.Fatalf("expected pre-check to report the contributor cap, got %v", err)
+	}
+}
+
+func TestStoryRequiresAlternation(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+
+	commitTransactions(t, bc, signTestTx(t, alicePriv, TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Turns",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{RequireAlternation: true},
+		CreatedAt: 20,
+	}}))
+
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "Alice opens", 30))
+
+	if _, err := bc.BuildBlock([]types.Transaction{newContributionTx(t, alicePriv, alice, "story-1", "Alice again", 31)}); !errors.Is(err, ErrAlternationRequired) {
+		t.Fatalf("expected alternation violation, got %v", err)
+	}
+
+	commitTransactions(t, bc,
+		newContributionTx(t, bobPriv, bob, "story-1", "Bob answers", 32),
+		newContributionTx(t, alicePriv, alice, "story-1", "Alice replies", 33),
+	)
+}
@@ -0,0 +1,145 @@
+package blockchain
+
+import (
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+// signTestTx signs a transaction with the key's next nonce, see testNonces.
+func signTestTx(t *testing.T, priv string, txType string, timestamp int64, payload interface{}) types.Transaction {
+	t.Helper()
+
+	return signTestTxWithNonce(t, priv, testNonces.next(priv), txType, timestamp, payload)
+}
+
+func signTestTxWithNonce(t *testing.T, priv string, nonce int64, txType string, timestamp int64, payload interface{}) types.Transaction {
+	t.Helper()
+
+	tx, err := NewSignedTransaction(txType, timestamp, nonce, payload)
+	if err != nil {
+		t.Fatalf("build %s: %v", txType, err)
+	}
+
+	tx.Signature, err = utils.SignEd25519(priv, TransactionSigningBytes(DefaultChainID, tx))
+	if err != nil {
+		t.Fatalf("sign %s: %v", txType, err)
+	}
+	testNonces.signed(priv, tx)
+	return tx
+}
+
+func newCreateStoryTx(t *testing.T, priv, creatorID, storyID string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeCreateStory, timestamp, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        storyID,
+		Title:     "Story " + storyID,
+		CreatorID: creatorID,
+		CreatedAt: timestamp,
+	}})
+}
+
+// newOpenMintStoryTx creates a story whose rules allow minting before it closes.
+func newOpenMintStoryTx(t *testing.T, priv, creatorID, storyID string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeCreateStory, timestamp, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        storyID,
+		Title:     "Story " + storyID,
+		CreatorID: creatorID,
+		Rules:     types.StoryRules{MintWhileOpen: true},
+		CreatedAt: timestamp,
+	}})
+}
+
+func newCloseStoryTx(t *testing.T, priv, closedBy, storyID string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeCloseStory, timestamp, types.CloseStoryPayload{StoryID: storyID, ClosedBy: closedBy})
+}
+
+func newContributionTx(t *testing.T, priv string, wallet types.Wallet, storyID, line string, timestamp int64) types.Transaction {
+	t.Helper()
+
+	return signTestTx(t, priv, TxTypeContribution, timestamp, types.ContributionPayload{Contribution: types.Contribution{
+		ContributorID: wallet.SupabaseUserID,
+		WalletAddress: wallet.Address,
+		StoryID:       storyID,
+		StoryLine:     line,
+		Timestamp:     timestamp,
+	}})
+}
+
+func TestStoryLifecycle(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	bob, bobPriv := newKeyedWallet(t, "bob")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10), newCreateWalletTx(t, bob, 10))
+
+	if _, err := bc.BuildBlock([]types.Transaction{newContributionTx(t, bobPriv, bob, "story-1", "Hello", 20)}); !errors.Is(err, ErrUnknownStory) {
+		t.Fatalf("expected contribution to unknown story to fail, got %v", err)
+	}
+
+	forged := newCreateStoryTx(t, bobPriv, "alice", "story-1", 20)
+	if _, err := bc.BuildBlock([]types.Transaction{forged}); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected story signed by another wallet to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 20))
+
+	story, ok := bc.GetStory("story-1")
+	if !ok || story.Status != types.StoryStatusOpen || story.CreatorID != "alice" || story.BlockIndex != 2 {
+		t.Fatalf("unexpected story record: %+v", story)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{newCreateStoryTx(t, bobPriv, "bob", "story-1", 21)}); !errors.Is(err, errDuplicateStory) {
+		t.Fatalf("expected duplicate story to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, newContributionTx(t, bobPriv, bob, "story-1", "Hello", 30))
+
+	if _, err := bc.BuildBlock([]types.Transaction{newCloseStoryTx(t, bobPriv, "bob", "story-1", 40)}); !errors.Is(err, ErrNotStoryCreator) {
+		t.Fatalf("expected non-creator close to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, newCloseStoryTx(t, alicePriv, "alice", "story-1", 40))
+
+	story, _ = bc.GetStory("story-1")
+	if story.Status != types.StoryStatusClosed || story.ClosedAt != 40 {
+		t.Fatalf("expected story to be closed at 40, got %+v", story)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{newContributionTx(t, bobPriv, bob, "story-1", "Too late", 50)}); !errors.Is(err, ErrStoryClosed) {
+		t.Fatalf("expected contribution to closed story to fail, got %v", err)
+	}
+
+	if _, err := bc.BuildBlock([]types.Transaction{newCloseStoryTx(t, alicePriv, "alice", "story-1", 50)}); !errors.Is(err, ErrStoryClosed) {
+		t.Fatalf("expected closing twice to fail, got %v", err)
+	}
+}
+
+func TestMintNFTRequiresClosedStory(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+	commitTransactions(t, bc, newCreateStoryTx(t, alicePriv, "alice", "story-1", 20))
+	commitTransactions(t, bc, newContributionTx(t, alicePriv, alice, "story-1", "It was a dark night", 25))
+
+	_, open := newEditionTx(t, bc, alicePriv, "nft-1", 1, "", 30)
+	if _, err := bc.BuildBlock([]types.Transaction{open}); !errors.Is(err, ErrStoryNotClosed) {
+		t.Fatalf("expected mint of open story to fail, got %v", err)
+	}
+
+	commitTransactions(t, bc, newCloseStoryTx(t, alicePriv, "alice", "story-1", 35))
+
+	_, closed := newEditionTx(t, bc, alicePriv, "nft-1", 1, "", 40)
+	commitTransactions(t, bc, closed)
+	if _, ok := bc.State().NFTRegistry["nft-1"]; !ok {
+		t.Fatalf("expected closed story to be minted")
+	}
+}
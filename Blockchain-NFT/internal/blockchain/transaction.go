@@ -0,0 +1,114 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/canonical"
+)
+
+// TxVersion2 is the current transaction envelope version. Version 2 added
+// the wallet nonce; version 1 envelopes are no longer accepted.
+const TxVersion2 = 2
+
+var (
+	errMissingTxID         = errors.New("blockchain: transaction id required")
+	errTxIDMismatch        = errors.New("blockchain: transaction id mismatch")
+	errUnsupportedVersion  = errors.New("blockchain: unsupported transaction version")
+	errMissingPayload      = errors.New("blockchain: transaction payload required")
+	errInvalidPayloadShape = errors.New("blockchain: invalid transaction payload")
+	errNonCanonicalPayload = errors.New("blockchain: transaction payload is not canonical json")
+)
+
+// NewTransaction wraps payload in an envelope without a nonce, as used by
+// transactions that are not signed by a wallet, and derives its ID.
+func NewTransaction(txType string, timestamp int64, payload interface{}) (types.Transaction, error) {
+	return NewSignedTransaction(txType, timestamp, 0, payload)
+}
+
+// NewSignedTransaction wraps payload in an envelope carrying the signing
+// wallet's next nonce and derives its ID. The caller signs the result.
+func NewSignedTransaction(txType string, timestamp, nonce int64, payload interface{}) (types.Transaction, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return types.Transaction{}, fmt.Errorf("blockchain: encode payload failed: %w", err)
+	}
+
+	tx := types.Transaction{
+		Type:      txType,
+		Version:   TxVersion2,
+		Payload:   encoded,
+		Timestamp: timestamp,
+		Nonce:     nonce,
+	}
+	tx.TxID = TransactionID(tx)
+	return tx, nil
+}
+
+// TransactionID returns the ID of a transaction: the hex SHA-256 of the
+// canonical encoding of its envelope. The ID and signature are excluded, so
+// every transaction type shares one rule.
+func TransactionID(tx types.Transaction) string {
+	return txEncoder(tx).Hash()
+}
+
+// TransactionSigningBytes returns the bytes that transaction signatures are
+// computed over: the canonical envelope encoding bound to the chain ID, in a
+// signing domain of its own for each transaction type.
+func TransactionSigningBytes(chainID string, tx types.Transaction) []byte {
+	return canonical.SigningBytes(canonical.PurposeTransaction+"/"+tx.Type, chainID, txEncoder(tx).Encoded())
+}
+
+func txEncoder(tx types.Transaction) *canonical.Encoder {
+	return canonical.NewEncoder(canonical.TagTransaction).
+		String(tx.Type).
+		Int64(int64(tx.Version)).
+		Int64(tx.Timestamp).
+		Int64(tx.Nonce).
+		Bytes(tx.Payload)
+}
+
+// decodeTxPayload checks the envelope version and ID and strictly decodes the payload into dst.
+func decodeTxPayload(tx types.Transaction, dst interface{}) error {
+	if tx.Version != TxVersion2 {
+		return fmt.Errorf("%w: %d", errUnsupportedVersion, tx.Version)
+	}
+
+	if len(tx.Payload) == 0 {
+		return errMissingPayload
+	}
+
+	// Payload bytes are hashed as carried, so they must survive JSON re-encoding
+	// (for example when blocks are persisted) without changing.
+	reencoded, err := json.Marshal(tx.Payload)
+	if err != nil || !bytes.Equal(reencoded, tx.Payload) {
+		return errNonCanonicalPayload
+	}
+
+	if err := verifyTxID(tx); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(tx.Payload))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dst); err != nil {
+		return fmt.Errorf("%w: %v", errInvalidPayloadShape, err)
+	}
+
+	return nil
+}
+
+func verifyTxID(tx types.Transaction) error {
+	if tx.TxID == "" {
+		return errMissingTxID
+	}
+
+	if TransactionID(tx) != tx.TxID {
+		return errTxIDMismatch
+	}
+
+	return nil
+}
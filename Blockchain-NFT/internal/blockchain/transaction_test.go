@@ -0,0 +1,200 @@
+package blockchain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/canonical"
+	"storytelling-blockchain/pkg/utils"
+)
+
+func TestTransactionIDCoversEnvelope(t *testing.T) {
+	wallet := types.Wallet{Address: "0xabc", SupabaseUserID: "user-1", PublicKey: "pub", PrivateKeyEncrypted: "enc"}
+
+	tx, err := NewTransaction(TxTypeCreateWallet, 10, types.CreateWalletPayload{Wallet: wallet})
+	if err != nil {
+		t.Fatalf("build transaction: %v", err)
+	}
+
+	expected := utils.ComputeSHA256(txEncoder(tx).Encoded())
+	if tx.TxID != expected {
+		t.Fatalf("unexpected transaction id %s, want %s", tx.TxID, expected)
+	}
+
+	signed := tx
+	signed.Signature = "sig"
+	if TransactionID(signed) != tx.TxID {
+		t.Fatalf("signature must not affect the transaction id")
+	}
+
+	bumped := tx
+	bumped.Timestamp++
+	if TransactionID(bumped) == tx.TxID {
+		t.Fatalf("timestamp must affect the transaction id")
+	}
+
+	nonced := tx
+	nonced.Nonce++
+	if TransactionID(nonced) == tx.TxID {
+		t.Fatalf("nonce must affect the transaction id")
+	}
+}
+
+func TestTransactionIDVector(t *testing.T) {
+	tx := types.Transaction{
+		Type:      TxTypeContribution,
+		Version:   TxVersion2,
+		Timestamp: 1761609600,
+		Nonce:     1,
+		Payload:   json.RawMessage(`{"contribution":{"story_id":"story-1"}}`),
+	}
+
+	const want = "5da5f16dab7ad9f60ff7974d3f566bbcca1e24331b2b780b0451f8a4c8f3645b"
+	if got := TransactionID(tx); got != want {
+		t.Fatalf("unexpected transaction id %s, want %s", got, want)
+	}
+
+	const wantSigning = "51c51c77d0ec773cd2e345876429f18dcff0bb3f4b5406d71935cdb4d27c38eb"
+	if got := utils.ComputeSHA256(TransactionSigningBytes(DefaultChainID, tx)); got != wantSigning {
+		t.Fatalf("unexpected signing bytes hash %s, want %s", got, wantSigning)
+	}
+}
+
+func TestSignaturesBoundToChainID(t *testing.T) {
+	bc := NewBlockchain()
+
+	alice, alicePriv := newKeyedWallet(t, "alice")
+	commitTransactions(t, bc, newCreateWalletTx(t, alice, 10))
+
+	tx, err := NewSignedTransaction(TxTypeCreateStory, 20, bc.NextNonce("alice"), types.CreateStoryPayload{Story: types.StoryRecord{ID: "story-1", Title: "Story", CreatorID: "alice", CreatedAt: 20}})
+	if err != nil {
+		t.Fatalf("build transaction: %v", err)
+	}
+
+	if tx.Signature, err = utils.SignEd25519(alicePriv, TransactionSigningBytes("kahani-testnet", tx)); err != nil {
+		t.Fatalf("sign transaction: %v", err)
+	}
+	if _, err := bc.BuildBlock([]types.Transaction{tx}); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected a signature for another chain to be rejected, got %v", err)
+	}
+
+	if tx.Signature, err = utils.SignEd25519(alicePriv, TransactionSigningBytes(bc.ChainID(), tx)); err != nil {
+		t.Fatalf("sign transaction: %v", err)
+	}
+	commitTransactions(t, bc, tx)
+
+	want := canonical.SigningBytes("tx/create_story", DefaultChainID, txEncoder(tx).Encoded())
+	if !bytes.Equal(TransactionSigningBytes(bc.ChainID(), tx), want) {
+		t.Fatalf("expected the transaction type to name the signing domain")
+	}
+}
+
+func TestDecodeTxPayloadRejectsMalformedEnvelopes(t *testing.T) {
+	wallet := types.Wallet{Address: "0xabc", SupabaseUserID: "user-1", PublicKey: "pub", PrivateKeyEncrypted: "enc"}
+
+	valid, err := NewTransaction(TxTypeCreateWallet, 10, types.CreateWalletPayload{Wallet: wallet})
+	if err != nil {
+		t.Fatalf("build transaction: %v", err)
+	}
+
+	var payload types.CreateWalletPayload
+	if err := decodeTxPayload(valid, &payload); err != nil || payload.Wallet.Address != wallet.Address {
+		t.Fatalf("expected valid envelope to decode, got %v", err)
+	}
+
+	unversioned := valid
+	unversioned.Version = 1
+	unversioned.TxID = TransactionID(unversioned)
+	if err := decodeTxPayload(unversioned, &payload); !errors.Is(err, errUnsupportedVersion) {
+		t.Fatalf("expected unsupported version, got %v", err)
+	}
+
+	tampered := valid
+	tampered.Payload = json.RawMessage(`{"wallet":{"address":"0xforged"}}`)
+	if err := decodeTxPayload(tampered, &payload); !errors.Is(err, errTxIDMismatch) {
+		t.Fatalf("expected transaction id mismatch, got %v", err)
+	}
+
+	spaced := valid
+	spaced.Payload = json.RawMessage(`{"wallet": {}}`)
+	spaced.TxID = TransactionID(spaced)
+	if err := decodeTxPayload(spaced, &payload); !errors.Is(err, errNonCanonicalPayload) {
+		t.Fatalf("expected non-canonical payload, got %v", err)
+	}
+
+	unescaped := valid
+	unescaped.Payload = json.RawMessage(`{"wallet":{"address":"<b>"}}`)
+	unescaped.TxID = TransactionID(unescaped)
+	if err := decodeTxPayload(unescaped, &payload); !errors.Is(err, errNonCanonicalPayload) {
+		t.Fatalf("expected unescaped html characters to be rejected, got %v", err)
+	}
+
+	unknownField := valid
+	unknownField.Payload = json.RawMessage(`{"wallet":{},"extra":true}`)
+	unknownField.TxID = TransactionID(unknownField)
+	if err := decodeTxPayload(unknownField, &payload); !errors.Is(err, errInvalidPayloadShape) {
+		t.Fatalf("expected invalid payload shape, got %v", err)
+	}
+}
+
+func TestStoryContributionsIndexedOnLoad(t *testing.T) {
+	store, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+
+	bc := NewBlockchain()
+	if err := bc.WithStorage(store); err != nil {
+		t.Fatalf("attach storage failed: %v", err)
+	}
+
+	pub, priv, err := utils.GenerateEd25519Keypair()
+	if err != nil {
+		t.Fatalf("generate keypair: %v", err)
+	}
+
+	wallet := types.Wallet{Address: "0xabc", SupabaseUserID: "user-1", PublicKey: pub, PrivateKeyEncrypted: "enc"}
+	contribution := types.Contribution{ContributorID: "user-1", WalletAddress: "0xabc", StoryID: "story-1", StoryLine: "Once upon a time", Timestamp: 20}
+
+	contributionTx, err := NewSignedTransaction(TxTypeContribution, contribution.Timestamp, 2, types.ContributionPayload{Contribution: contribution})
+	if err != nil {
+		t.Fatalf("build transaction: %v", err)
+	}
+
+	contributionTx.Signature, err = utils.SignEd25519(priv, TransactionSigningBytes(DefaultChainID, contributionTx))
+	if err != nil {
+		t.Fatalf("sign contribution: %v", err)
+	}
+
+	for _, tx := range []types.Transaction{newCreateWalletTx(t, wallet, 10), newCreateStoryTx(t, priv, "user-1", "story-1", 15), contributionTx} {
+		block, err := bc.BuildBlock([]types.Transaction{tx})
+		if err != nil {
+			t.Fatalf("build block: %v", err)
+		}
+		if err := bc.AddBlock(block); err != nil {
+			t.Fatalf("add block: %v", err)
+		}
+	}
+
+	if got := bc.StoryContributions("story-1"); len(got) != 1 || got[0].StoryLine != contribution.StoryLine {
+		t.Fatalf("expected indexed contribution, got %+v", got)
+	}
+
+	restored, err := LoadBlockchain(store)
+	if err != nil {
+		t.Fatalf("load blockchain failed: %v", err)
+	}
+
+	if got := restored.StoryContributions("story-1"); len(got) != 1 || got[0].StoryLine != contribution.StoryLine {
+		t.Fatalf("expected contribution index to be rebuilt on load, got %+v", got)
+	}
+
+	if got := restored.StoryContributions("story-2"); got != nil {
+		t.Fatalf("expected no contributions for unknown story, got %+v", got)
+	}
+}
@@ -0,0 +1,388 @@
+package blockchain
+
+import (
+	"errors"
+
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+// Built-in transaction types.
+const (
+	TxTypeCreateWallet        = "create_wallet"
+	TxTypeContribution        = "contribution"
+	TxTypeMintNFT             = "mint_nft"
+	TxTypeTransferNFT         = "transfer_nft"
+	TxTypeTransferShares      = "transfer_shares"
+	TxTypeCreateStory         = "create_story"
+	TxTypeCloseStory          = "close_story"
+	TxTypeForkStory           = "fork_story"
+	TxTypeInviteContributor   = "invite_contributor"
+	TxTypeAcceptInvite        = "accept_invite"
+	TxTypeRevokeContributor   = "revoke_contributor"
+	TxTypeAmendContribution   = "amend_contribution"
+	TxTypeRetractContribution = "retract_contribution"
+	TxTypeProposeMint         = "propose_mint"
+	TxTypeApproveMint         = "approve_mint"
+	TxTypeBurnNFT             = "burn_nft"
+	TxTypeFreezeMetadata      = "freeze_metadata"
+	TxTypeUpdateMetadata      = "update_metadata"
+)
+
+var errMissingTimestamp = errors.New("blockchain: transaction timestamp required")
+
+// verifyWalletSignature checks that tx carries the wallet's Ed25519 signature
+// over the transaction signing bytes of the chain.
+func verifyWalletSignature(chainID string, wallet types.Wallet, tx types.Transaction) error {
+	okSig, err := utils.VerifyEd25519(wallet.PublicKey, TransactionSigningBytes(chainID, tx), tx.Signature)
+	if err != nil {
+		return err
+	}
+	if !okSig {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+func builtinTxHandlers() []TransactionHandler {
+	return []TransactionHandler{
+		createWalletHandler{},
+		contributionHandler{},
+		mintNFTHandler{},
+		proposeMintHandler{},
+		approveMintHandler{},
+		transferNFTHandler{},
+		transferSharesHandler{},
+		burnNFTHandler{},
+		freezeMetadataHandler{},
+		updateMetadataHandler{},
+		createStoryHandler{},
+		closeStoryHandler{},
+		forkStoryHandler{},
+		inviteContributorHandler{},
+		acceptInviteHandler{},
+		revokeContributorHandler{},
+		amendContributionHandler{},
+		retractContributionHandler{},
+	}
+}
+
+// createWalletHandler records a custodial wallet on-chain.
+type createWalletHandler struct{}
+
+func (createWalletHandler) Type() string { return TxTypeCreateWallet }
+
+func (createWalletHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.CreateWalletPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (createWalletHandler) Validate(_ TxContext, state types.State, tx types.Transaction, payload interface{}) error {
+	wallet := payload.(types.CreateWalletPayload).Wallet
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if wallet.SupabaseUserID == "" {
+		return errMissingWalletID
+	}
+	if wallet.Address == "" {
+		return errMissingWalletAddress
+	}
+	if wallet.PublicKey == "" || wallet.PrivateKeyEncrypted == "" {
+		return errMissingWalletKeys
+	}
+
+	if existing, exists := state.WalletRegistry[wallet.SupabaseUserID]; exists {
+		if existing.Address != wallet.Address || existing.PublicKey != wallet.PublicKey {
+			return errDuplicateWallet
+		}
+	}
+
+	return nil
+}
+
+func (createWalletHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, payload interface{}) error {
+	wallet := payload.(types.CreateWalletPayload).Wallet
+	wallet.BlockIndex = ctx.BlockIndex
+	SetStateEntry(ctx, StateKeyWallet, state.WalletRegistry, wallet.SupabaseUserID, wallet)
+	return nil
+}
+
+// contributionHandler accepts a story line signed by the contributor's wallet.
+type contributionHandler struct{}
+
+func (contributionHandler) Type() string { return TxTypeContribution }
+
+func (contributionHandler) Signer(payload interface{}) string {
+	return payload.(types.ContributionPayload).Contribution.ContributorID
+}
+
+func (contributionHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.ContributionPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (contributionHandler) Validate(ctx TxContext, state types.State, tx types.Transaction, decoded interface{}) error {
+	contribution := decoded.(types.ContributionPayload).Contribution
+
+	if tx.Signature == "" {
+		return errMissingSignature
+	}
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	if contribution.ContributorID == "" {
+		return errMissingWalletID
+	}
+
+	if contribution.TxID != "" {
+		return errUnexpectedContributionTxID
+	}
+
+	story, err := openStory(state, contribution.StoryID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkParentContribution(state, contribution); err != nil {
+		return err
+	}
+
+	if !canContribute(state, story, contribution.ContributorID) {
+		return errNotStoryContributor
+	}
+
+	if err := checkStoryRules(ctx, story, contribution); err != nil {
+		return err
+	}
+
+	wallet, ok := state.WalletRegistry[contribution.ContributorID]
+	if !ok {
+		return errMissingWallet
+	}
+
+	if wallet.Address != "" && wallet.Address != contribution.WalletAddress {
+		return errors.New("blockchain: contribution wallet mismatch")
+	}
+
+	if contribution.Timestamp != tx.Timestamp {
+		return errors.New("blockchain: contribution timestamp mismatch")
+	}
+
+	return verifyWalletSignature(state.ChainID, wallet, tx)
+}
+
+func (contributionHandler) Apply(ctx TxContext, state *types.State, tx types.Transaction, decoded interface{}) error {
+	// The state keeps the story graph and the current text of every line,
+	// which mints are checked against, along with the progress the story
+	// rules are checked against and who may revise each line.
+	contribution := decoded.(types.ContributionPayload).Contribution
+
+	story := state.StoryRegistry[contribution.StoryID]
+
+	parent := contribution.ParentTxID
+	if parent == "" {
+		parent = story.Progress.MainTipTxID
+	}
+
+	SetStateEntry(ctx, StateKeyContribution, state.Contributions, tx.TxID, types.ContributionRecord{
+		StoryID:       contribution.StoryID,
+		ParentTxID:    parent,
+		ContributorID: contribution.ContributorID,
+		StoryLine:     contribution.StoryLine,
+		Timestamp:     contribution.Timestamp,
+		Sequence:      story.Progress.LineCount,
+	})
+
+	story.Progress = advanceProgress(story.Progress, contribution.ContributorID)
+	// The first reply to a line stays on the main line, so a new line only
+	// extends the main line when it follows its last line.
+	if parent == story.Progress.MainTipTxID {
+		story.Progress.MainTipTxID = tx.TxID
+	}
+	SetStateEntry(ctx, StateKeyStory, state.StoryRegistry, contribution.StoryID, story)
+	return nil
+}
+
+// mintNFTHandler registers a minted story NFT.
+type mintNFTHandler struct{}
+
+func (mintNFTHandler) Type() string { return TxTypeMintNFT }
+
+func (mintNFTHandler) Decode(tx types.Transaction) (interface{}, error) {
+	var payload types.MintNFTPayload
+	if err := decodeTxPayload(tx, &payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+func (mintNFTHandler) Validate(ctx TxContext, state types.State, tx types.Transaction, payload interface{}) error {
+	mint := payload.(types.MintNFTPayload)
+
+	if tx.Timestamp <= 0 {
+		return errMissingTimestamp
+	}
+
+	// Without the authors' approval, only the story's own mint triggers can
+	// mint it.
+	if len(mint.Triggers) == 0 {
+		return errMintWithoutTrigger
+	}
+
+	if err := validateMint(state, mint.NFT); err != nil {
+		return err
+	}
+
+	if _, err := checkMintAuthors(ctx, state, tx, mint.NFT); err != nil {
+		return err
+	}
+
+	if err := checkNoPendingMint(ctx, state, mint.NFT.StoryID); err != nil {
+		return err
+	}
+
+	return checkMintTriggers(state.StoryRegistry[mint.NFT.StoryID], mint.Triggers)
+}
+
+// validateMint checks that nft can be registered as the next edition of its
+// story. It is shared by mint_nft and the approvals that finalize a
+// propose_mint.
+func validateMint(state types.State, nft types.NFT) error {
+	if nft.TokenID == "" {
+		return errMissingTokenID
+	}
+
+	if nft.OwnerID == "" {
+		return errMissingNFTOwner
+	}
+
+	owner, ok := state.WalletRegistry[nft.OwnerID]
+	if !ok {
+		return errMissingWallet
+	}
+	if owner.Address != nft.OwnerAddress {
+		return errOwnerAddressMismatch
+	}
+
+	// checkMintAuthors ties the main author to the story's lines, so the
+	// owner cannot be chosen by the submitter either.
+	if nft.OwnerID != nft.MainAuthor.SupabaseUserID {
+		return errMintOwner
+	}
+
+	if _, exists := state.NFTRegistry[nft.TokenID]; exists {
+		return errDuplicateToken
+	}
+
+	story, ok := state.StoryRegistry[nft.StoryID]
+	if !ok {
+		return errUnknownStory
+	}
+	if story.Status != types.StoryStatusClosed && !story.Rules.MintWhileOpen {
+		return errStoryNotClosed
+	}
+
+	if err := checkEdition(story, nft); err != nil {
+		return err
+	}
+
+	policy, err := AuthorshipPolicyByName(nft.AuthorshipPolicy)
+	if err != nil {
+		return err
+	}
+	storyPolicy, err := AuthorshipPolicyByName(story.Rules.AuthorshipPolicy)
+	if err != nil {
+		return err
+	}
+	if policy.Name() != storyPolicy.Name() {
+		return errAuthorshipPolicyMismatch
+	}
+
+	return validateShareholders(nftAuthors(nft))
+}
+
+// checkMintAuthors makes sure the authors and weights of nft are the ones the
+// chain derives from the story's committed lines under the story's policy and
+// lineage, so that share balances cannot be chosen by the submitter. The
+// lines are weighed as of the mint time, which is the transaction timestamp
+// and may not be later than the block. It returns the derived authors.
+func checkMintAuthors(ctx TxContext, state types.State, tx types.Transaction, nft types.NFT) ([]types.Author, error) {
+	if nft.MintedAt != tx.Timestamp || nft.MintedAt > ctx.BlockTimestamp {
+		return nil, errInvalidMintTime
+	}
+
+	expected, err := mintAuthors(state, nft.StoryID, nft.MintedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The main author comes first, so it must be the first derived author.
+	authors := nftAuthors(nft)
+	if len(authors) != len(expected) {
+		return nil, errMintAuthors
+	}
+
+	for i, author := range authors {
+		want := expected[i]
+		if author.SupabaseUserID != want.SupabaseUserID || author.Weight != want.Weight || author.ContributionCount != want.ContributionCount {
+			return nil, errMintAuthors
+		}
+	}
+
+	return expected, nil
+}
+
+// mintAuthors derives the authors of a story from its live lines in the
+// state, as MintNFT derives them from StoryContributions.
+func mintAuthors(state types.State, storyID string, asOf int64) ([]types.Author, error) {
+	record := state.StoryRegistry[storyID]
+
+	policy, err := AuthorshipPolicyByName(record.Rules.AuthorshipPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	var contributions []types.Contribution
+	for _, contribution := range stateStoryLines(state, storyID) {
+		if state.Contributions[contribution.TxID].Retracted {
+			continue
+		}
+		contributions = append(contributions, contribution)
+	}
+
+	return StoryAuthors(types.Story{ID: storyID, Contributions: contributions, Lineage: record.Lineage}, policy, asOf), nil
+}
+
+// Apply registers the NFT, credits its authors with their share balances,
+// makes it the story's latest edition and fires its triggers.
+func (mintNFTHandler) Apply(ctx TxContext, state *types.State, _ types.Transaction, payload interface{}) error {
+	mint := payload.(types.MintNFTPayload)
+	applyMint(ctx, state, mint.NFT)
+	SetStateEntry(ctx, StateKeyStory, state.StoryRegistry, mint.NFT.StoryID, fireMintTriggers(state.StoryRegistry[mint.NFT.StoryID], mint.Triggers))
+	return nil
+}
+
+func applyMint(ctx TxContext, state *types.State, nft types.NFT) {
+	nft.BlockIndex = ctx.BlockIndex
+	nft.Status = types.NFTStatusActive
+	nft.BurnedAt = 0
+	nft.BurnReason = ""
+	SetStateEntry(ctx, StateKeyNFT, state.NFTRegistry, nft.TokenID, nft)
+	SetStateEntry(ctx, StateKeyShares, state.ShareBalances, nft.TokenID, AllocateShares(nftAuthors(nft)))
+
+	story := state.StoryRegistry[nft.StoryID]
+	story.Edition = nft.Edition
+	story.EditionTokenID = nft.TokenID
+	SetStateEntry(ctx, StateKeyStory, state.StoryRegistry, nft.StoryID, story)
+}
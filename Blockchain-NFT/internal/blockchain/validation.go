@@ -1,12 +1,10 @@
 package blockchain
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
 	"storytelling-blockchain/internal/types"
-	"storytelling-blockchain/pkg/utils"
 )
 
 var (
@@ -19,200 +17,113 @@ var (
 	errMissingWallet        = errors.New("blockchain: wallet not registered")
 	errInvalidSignature     = errors.New("blockchain: signature verification failed")
 	errDuplicateToken       = errors.New("blockchain: nft token already exists")
+	errTxRootMismatch       = errors.New("blockchain: transaction root mismatch")
 )
 
-type contributionPayload struct {
-	Contribution types.Contribution `json:"contribution"`
-	Timestamp    int64              `json:"timestamp"`
+// ValidateBlock ensures the block links to its predecessor, that all
+// transactions are valid with respect to the provided chain state and that the
+// block commits to the resulting state root. A mutated copy of the resulting
+// state is returned for application by the caller. Transactions are checked
+// against the built-in transaction types.
+func ValidateBlock(block types.Block, prev types.Block, state types.State) (types.State, error) {
+	tree, err := newStateTree(state)
+	if err != nil {
+		return state, err
+	}
+
+	nextState := cloneState(state)
+	if _, _, _, err := validateBlock(defaultTxRegistry, block, prev, &nextState, tree); err != nil {
+		return state, err
+	}
+	return nextState, nil
 }
 
-// ValidateBlock ensures the block links to its predecessor and that all
-// transactions are valid with respect to the provided chain state. A mutated
-// copy of the resulting state is returned for application by the caller.
-func ValidateBlock(block types.Block, prev types.Block, state types.State) (types.State, error) {
+// validateBlock applies block to state in place and checks the state root
+// against tree, the state tree of state. It returns the tree of the resulting
+// state, the journal that undoes the block and the decoded payload of every
+// transaction in block order. On error state is left unchanged.
+func validateBlock(registry *TxRegistry, block types.Block, prev types.Block, state *types.State, tree *stateTree) (*stateTree, *stateJournal, []interface{}, error) {
 	if block.Index != prev.Index+1 {
-		return state, fmt.Errorf("blockchain: expected block index %d, got %d", prev.Index+1, block.Index)
+		return nil, nil, nil, fmt.Errorf("blockchain: expected block index %d, got %d", prev.Index+1, block.Index)
 	}
 
 	if block.PrevHash != prev.Hash {
-		return state, errors.New("blockchain: previous hash mismatch")
+		return nil, nil, nil, errors.New("blockchain: previous hash mismatch")
 	}
 
 	if CalculateHash(block) != block.Hash {
-		return state, errors.New("blockchain: block hash mismatch")
+		return nil, nil, nil, errors.New("blockchain: block hash mismatch")
 	}
 
 	if len(block.Transactions) == 0 {
-		return state, errors.New("blockchain: block must contain transactions")
+		return nil, nil, nil, errors.New("blockchain: block must contain transactions")
 	}
 
-	nextState := cloneState(state)
-
+	seen := make(map[string]struct{}, len(block.Transactions))
 	for _, tx := range block.Transactions {
-		if err := applyTransaction(&nextState, tx, block.Index); err != nil {
-			return state, fmt.Errorf("blockchain: transaction %s invalid: %w", tx.TxID, err)
+		if _, dup := seen[tx.TxID]; dup {
+			return nil, nil, nil, fmt.Errorf("blockchain: duplicate transaction %s in block", tx.TxID)
 		}
+		seen[tx.TxID] = struct{}{}
 	}
 
-	return nextState, nil
-}
-
-func applyTransaction(state *types.State, tx types.Transaction, blockIndex int) error {
-	if tx.Type == "" {
-		return errEmptyTransactionType
+	if CalculateTxRoot(block.Transactions) != block.TxRoot {
+		return nil, nil, nil, errTxRootMismatch
 	}
 
-	switch tx.Type {
-	case "create_wallet":
-		var wallet types.Wallet
-		if err := decodePayload(tx.Data, &wallet); err != nil {
-			return err
-		}
-
-		if tx.Timestamp <= 0 {
-			return errors.New("blockchain: transaction timestamp required")
-		}
-
-		if wallet.SupabaseUserID == "" {
-			return errMissingWalletID
-		}
-		if wallet.Address == "" {
-			return errMissingWalletAddress
-		}
-		if wallet.PublicKey == "" || wallet.PrivateKeyEncrypted == "" {
-			return errMissingWalletKeys
-		}
-
-		hashPayload := struct {
-			Wallet    types.Wallet `json:"wallet"`
-			Timestamp int64        `json:"timestamp"`
-		}{Wallet: wallet, Timestamp: tx.Timestamp}
-
-		if err := verifyTxID(tx.TxID, hashPayload); err != nil {
-			return err
-		}
-
-		existing, exists := state.WalletRegistry[wallet.SupabaseUserID]
-		if exists {
-			if existing.Address != wallet.Address || existing.PublicKey != wallet.PublicKey {
-				return errDuplicateWallet
-			}
-		}
-
-		wallet.BlockIndex = blockIndex
-		state.WalletRegistry[wallet.SupabaseUserID] = wallet
-
-	case "contribution":
-		if tx.Signature == "" {
-			return errMissingSignature
-		}
-
-		var payload contributionPayload
-		if err := decodePayload(tx.Data, &payload); err != nil {
-			return err
-		}
-
-		if tx.Timestamp <= 0 {
-			return errors.New("blockchain: transaction timestamp required")
-		}
-
-		if payload.Contribution.ContributorID == "" {
-			return errMissingWalletID
-		}
-
-		wallet, ok := state.WalletRegistry[payload.Contribution.ContributorID]
-		if !ok {
-			return errMissingWallet
-		}
-
-		if wallet.Address != "" && wallet.Address != payload.Contribution.WalletAddress {
-			return errors.New("blockchain: contribution wallet mismatch")
-		}
-
-		if payload.Timestamp != tx.Timestamp {
-			return errors.New("blockchain: contribution timestamp mismatch")
-		}
-
-		if err := verifyTxID(tx.TxID, payload); err != nil {
-			return err
-		}
-
-		signedBytes, err := json.Marshal(payload.Contribution)
-		if err != nil {
-			return err
-		}
-
-		okSig, err := utils.VerifyEd25519(wallet.PublicKey, signedBytes, tx.Signature)
-		if err != nil {
-			return err
-		}
-		if !okSig {
-			return errInvalidSignature
-		}
-
-	case "mint_nft":
-		var nft types.NFT
-		if err := decodePayload(tx.Data, &nft); err != nil {
-			return err
-		}
-
-		if tx.Timestamp <= 0 {
-			return errors.New("blockchain: transaction timestamp required")
-		}
-
-		if nft.TokenID == "" {
-			return errors.New("blockchain: nft token id required")
-		}
-
-		if err := verifyTxID(tx.TxID, nft); err != nil {
-			return err
-		}
-
-		if _, exists := state.NFTRegistry[nft.TokenID]; exists {
-			return errDuplicateToken
-		}
-
-		nft.BlockIndex = blockIndex
-		state.NFTRegistry[nft.TokenID] = nft
-
-	default:
-		// Unknown transaction types are accepted without additional validation.
+	journal, payloads, err := applyBlockTransactions(registry, block, state)
+	if err != nil {
+		return nil, nil, nil, err
 	}
 
-	return nil
-}
-
-func decodePayload(src interface{}, dst interface{}) error {
-	bytes, err := json.Marshal(src)
+	nextTree, err := tree.update(*state, journal.keys)
 	if err != nil {
-		return err
+		journal.revert()
+		return nil, nil, nil, err
 	}
 
-	return json.Unmarshal(bytes, dst)
-}
-
-func verifyTxID(txID string, payload interface{}) error {
-	if txID == "" {
-		return errors.New("blockchain: transaction id required")
+	if nextTree.Root() != block.StateRoot {
+		journal.revert()
+		return nil, nil, nil, errStateRootMismatch
 	}
 
-	bytes, err := json.Marshal(payload)
-	if err != nil {
-		return err
-	}
+	return nextTree, journal, payloads, nil
+}
 
-	expected := utils.ComputeSHA256(bytes)
-	if expected != txID {
-		return errors.New("blockchain: transaction id mismatch")
+// applyBlockTransactions applies the block's transactions to state in place
+// through the handlers registered for their types, then drops the mint
+// proposals that expired by the block's timestamp or went stale. The returned journal lists
+// the changed keys; on error the changes are already undone.
+func applyBlockTransactions(registry *TxRegistry, block types.Block, state *types.State) (*stateJournal, []interface{}, error) {
+	journal := newStateJournal()
+	ctx := TxContext{BlockIndex: block.Index, BlockTimestamp: block.Timestamp, journal: journal}
+	payloads := make([]interface{}, len(block.Transactions))
+
+	for i, tx := range block.Transactions {
+		payload, err := registry.apply(ctx, state, tx)
+		if err != nil {
+			journal.revert()
+			return nil, nil, fmt.Errorf("blockchain: transaction %s invalid: %w", tx.TxID, err)
+		}
+		payloads[i] = payload
 	}
 
-	return nil
+	settleMintProposals(ctx, state, block.Timestamp)
+	return journal, payloads, nil
 }
 
 func cloneState(state types.State) types.State {
 	cloned := types.State{
+		ChainID:        state.ChainID,
 		WalletRegistry: make(map[string]types.Wallet, len(state.WalletRegistry)),
 		NFTRegistry:    make(map[string]types.NFT, len(state.NFTRegistry)),
+		ShareBalances:  make(map[string]map[string]int64, len(state.ShareBalances)),
+		StoryRegistry:  make(map[string]types.StoryRecord, len(state.StoryRegistry)),
+		StoryMembers:   make(map[string]map[string]string, len(state.StoryMembers)),
+		Contributions:  make(map[string]types.ContributionRecord, len(state.Contributions)),
+		MintProposals:  make(map[string]types.MintProposal, len(state.MintProposals)),
+		Nonces:         make(map[string]int64, len(state.Nonces)),
+		Validators:     make(map[string]types.Validator, len(state.Validators)),
 	}
 
 	for k, v := range state.WalletRegistry {
@@ -223,5 +134,41 @@ func cloneState(state types.State) types.State {
 		cloned.NFTRegistry[k] = v
 	}
 
+	for tokenID, balances := range state.ShareBalances {
+		copied := make(map[string]int64, len(balances))
+		for holder, units := range balances {
+			copied[holder] = units
+		}
+		cloned.ShareBalances[tokenID] = copied
+	}
+
+	for k, v := range state.StoryRegistry {
+		cloned.StoryRegistry[k] = v
+	}
+
+	for storyID, members := range state.StoryMembers {
+		copied := make(map[string]string, len(members))
+		for userID, status := range members {
+			copied[userID] = status
+		}
+		cloned.StoryMembers[storyID] = copied
+	}
+
+	for k, v := range state.Contributions {
+		cloned.Contributions[k] = v
+	}
+
+	for k, v := range state.MintProposals {
+		cloned.MintProposals[k] = v
+	}
+
+	for k, v := range state.Nonces {
+		cloned.Nonces[k] = v
+	}
+
+	for k, v := range state.Validators {
+		cloned.Validators[k] = v
+	}
+
 	return cloned
 }
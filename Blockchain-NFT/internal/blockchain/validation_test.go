@@ -2,7 +2,7 @@ package blockchain
 
 import (
 	"encoding/base64"
-	"encoding/json"
+	"errors"
 	"testing"
 
 	"storytelling-blockchain/internal/types"
@@ -25,27 +25,15 @@ func TestValidateBlockAppliesCreateWallet(t *testing.T) {
 		CreatedAt:           4000,
 	}
 
-	payload := struct {
-		Wallet    types.Wallet `json:"wallet"`
-		Timestamp int64        `json:"timestamp"`
-	}{Wallet: wallet, Timestamp: 5000}
+	tx := newCreateWalletTx(t, wallet, 5000)
 
-	txBytes, err := json.Marshal(payload)
-	if err != nil {
-		t.Fatalf("marshal payload: %v", err)
-	}
+	state := types.State{WalletRegistry: map[string]types.Wallet{}, NFTRegistry: map[string]types.NFT{}}
 
-	tx := types.Transaction{
-		TxID:      utils.ComputeSHA256(txBytes),
-		Type:      "create_wallet",
-		Data:      wallet,
-		Timestamp: payload.Timestamp,
+	block, err := NewBlockWithState(prev, state, []types.Transaction{tx})
+	if err != nil {
+		t.Fatalf("build block: %v", err)
 	}
 
-	block := NewBlock(prev.Index+1, prev.Hash, []types.Transaction{tx})
-
-	state := types.State{WalletRegistry: map[string]types.Wallet{}, NFTRegistry: map[string]types.NFT{}}
-
 	updated, err := ValidateBlock(block, prev, state)
 	if err != nil {
 		t.Fatalf("validate block: %v", err)
@@ -80,25 +68,25 @@ func TestValidateBlockRejectsInvalidSignature(t *testing.T) {
 	state := types.State{
 		WalletRegistry: map[string]types.Wallet{"user-123": wallet},
 		NFTRegistry:    map[string]types.NFT{},
+		StoryRegistry: map[string]types.StoryRecord{
+			"story-1": {ID: "story-1", Title: "Story", CreatorID: "user-123", Status: types.StoryStatusOpen},
+		},
 	}
 
-	payload := contributionPayload{
-		Contribution: types.Contribution{
-			ContributorID: "user-123",
-			WalletAddress: "0xabc",
-			StoryID:       "story-1",
-			StoryLine:     "line",
-			Timestamp:     6000,
-		},
-		Timestamp: 6000,
+	contribution := types.Contribution{
+		ContributorID: "user-123",
+		WalletAddress: "0xabc",
+		StoryID:       "story-1",
+		StoryLine:     "line",
+		Timestamp:     6000,
 	}
 
-	payloadBytes, err := json.Marshal(payload)
+	tx, err := NewTransaction(TxTypeContribution, contribution.Timestamp, types.ContributionPayload{Contribution: contribution})
 	if err != nil {
-		t.Fatalf("marshal payload: %v", err)
+		t.Fatalf("build transaction: %v", err)
 	}
 
-	validSig, err := utils.SignEd25519(priv, payloadBytes)
+	validSig, err := utils.SignEd25519(priv, TransactionSigningBytes(DefaultChainID, tx))
 	if err != nil {
 		t.Fatalf("sign payload: %v", err)
 	}
@@ -111,19 +99,11 @@ func TestValidateBlockRejectsInvalidSignature(t *testing.T) {
 
 	decoded[0] ^= 0xFF
 
-	invalidSig := base64.StdEncoding.EncodeToString(decoded)
-
-	tx := types.Transaction{
-		TxID:      utils.ComputeSHA256(payloadBytes),
-		Type:      "contribution",
-		Data:      payload,
-		Timestamp: payload.Timestamp,
-		Signature: invalidSig,
-	}
+	tx.Signature = base64.StdEncoding.EncodeToString(decoded)
 
 	block := NewBlock(prev.Index+1, prev.Hash, []types.Transaction{tx})
 
-	if _, err := ValidateBlock(block, prev, state); err == nil {
-		t.Fatalf("expected validation to fail for invalid signature")
+	if _, err := ValidateBlock(block, prev, state); !errors.Is(err, errInvalidSignature) {
+		t.Fatalf("expected validation to fail for invalid signature, got %v", err)
 	}
 }
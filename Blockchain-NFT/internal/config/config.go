@@ -50,6 +50,10 @@ type FileConfig struct {
 		Port int    `yaml:"port"`
 	} `yaml:"node"`
 
+	Chain struct {
+		GenesisFile string `yaml:"genesis_file"`
+	} `yaml:"chain"`
+
 	Network struct {
 		BootstrapPeers []string `yaml:"bootstrap_peers"`
 		Consensus      string   `yaml:"consensus"`
@@ -71,6 +75,7 @@ type FileConfig struct {
 		EnableWebsocket bool     `yaml:"enable_websocket"`
 		RateLimit       int      `yaml:"rate_limit"`
 		AllowedOrigins  []string `yaml:"allowed_origins"`
+		MintProposalTTL Duration `yaml:"mint_proposal_ttl"`
 	} `yaml:"api"`
 }
 
@@ -10,6 +10,7 @@ import (
 // BootstrapOptions bundle parameters required to spin up consensus participants.
 type BootstrapOptions struct {
 	NodeID         string
+	ChainID        string
 	Peers          []string
 	FaultTolerance int
 	Transport      *network.Node
@@ -37,6 +38,7 @@ func BootstrapNode(opts BootstrapOptions) (*PBFTNode, network.GossipHandler, err
 
 	cfg := Config{
 		ID:             opts.NodeID,
+		ChainID:        opts.ChainID,
 		Peers:          opts.Peers,
 		FaultTolerance: opts.FaultTolerance,
 		Network:        gossipNet,
@@ -59,7 +61,7 @@ func BootstrapNode(opts BootstrapOptions) (*PBFTNode, network.GossipHandler, err
 }
 
 // BootstrapCluster assists in constructing a set of PBFT nodes over the network transport.
-func BootstrapCluster(transports map[string]*network.Node, peers []string, builder BlockBuilder, finalizers map[string]Finalizer, signer Signer, faultTolerance int) (map[string]*PBFTNode, map[string]network.GossipHandler, error) {
+func BootstrapCluster(transports map[string]*network.Node, peers []string, builder BlockBuilder, finalizers map[string]Finalizer, signer Signer, chainID string, faultTolerance int) (map[string]*PBFTNode, map[string]network.GossipHandler, error) {
 	if len(transports) == 0 {
 		return nil, nil, errors.New("consensus: transports required")
 	}
@@ -83,6 +85,7 @@ func BootstrapCluster(transports map[string]*network.Node, peers []string, build
 
 		node, handler, err := BootstrapNode(BootstrapOptions{
 			NodeID:         id,
+			ChainID:        chainID,
 			Peers:          peers,
 			FaultTolerance: faultTolerance,
 			Transport:      transport,
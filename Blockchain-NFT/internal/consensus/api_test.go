@@ -132,7 +132,7 @@ func TestBootstrapCluster(t *testing.T) {
 		"node-2": func(types.Block) {},
 	}
 
-	nodes, handlers, err := BootstrapCluster(transports, []string{"node-1", "node-2"}, mockBuilder{}, finalizers, mockSigner{}, 0)
+	nodes, handlers, err := BootstrapCluster(transports, []string{"node-1", "node-2"}, mockBuilder{}, finalizers, mockSigner{}, "test-chain", 0)
 	if err != nil {
 		t.Fatalf("bootstrap cluster failed: %v", err)
 	}
@@ -164,7 +164,7 @@ func TestStartClusterIntegration(t *testing.T) {
 		"node-2": finalizer.finalize("node-2"),
 	}
 
-	runtimes, err := StartCluster(context.Background(), transports, []string{"node-1", "node-2"}, mockBuilder{}, finalizers, mockSigner{}, 0)
+	runtimes, err := StartCluster(context.Background(), transports, []string{"node-1", "node-2"}, mockBuilder{}, finalizers, mockSigner{}, "test-chain", 0)
 	if err != nil {
 		t.Fatalf("start cluster failed: %v", err)
 	}
@@ -191,7 +191,7 @@ func TestStartClusterIntegration(t *testing.T) {
 }
 
 func TestStartClusterErrors(t *testing.T) {
-	_, err := StartCluster(context.Background(), map[string]*network.Node{}, nil, mockBuilder{}, map[string]Finalizer{}, mockSigner{}, 0)
+	_, err := StartCluster(context.Background(), map[string]*network.Node{}, nil, mockBuilder{}, map[string]Finalizer{}, mockSigner{}, "test-chain", 0)
 	if err == nil {
 		t.Fatal("expected error for missing transports")
 	}
@@ -200,12 +200,12 @@ func TestStartClusterErrors(t *testing.T) {
 		"node-1": network.NewNode("node-1", network.NewInMemoryTransport()),
 	}
 
-	_, err = StartCluster(context.Background(), transports, []string{"node-1"}, nil, map[string]Finalizer{"node-1": func(types.Block) {}}, mockSigner{}, 0)
+	_, err = StartCluster(context.Background(), transports, []string{"node-1"}, nil, map[string]Finalizer{"node-1": func(types.Block) {}}, mockSigner{}, "test-chain", 0)
 	if err == nil {
 		t.Fatal("expected error for missing builder")
 	}
 
-	_, err = StartCluster(context.Background(), transports, []string{"node-1"}, mockBuilder{}, map[string]Finalizer{}, mockSigner{}, 0)
+	_, err = StartCluster(context.Background(), transports, []string{"node-1"}, mockBuilder{}, map[string]Finalizer{}, mockSigner{}, "test-chain", 0)
 	if err == nil {
 		t.Fatal("expected error for missing finalizer")
 	}
@@ -259,7 +259,7 @@ func TestStartClusterIgnoresBadGossip(t *testing.T) {
 		"node-2": finalizer.finalize("node-2"),
 	}
 
-	runtimes, err := StartCluster(context.Background(), transports, []string{"node-1", "node-2"}, mockBuilder{}, finalizers, mockSigner{}, 0)
+	runtimes, err := StartCluster(context.Background(), transports, []string{"node-1", "node-2"}, mockBuilder{}, finalizers, mockSigner{}, "test-chain", 0)
 	if err != nil {
 		t.Fatalf("start cluster failed: %v", err)
 	}
@@ -24,12 +24,12 @@ func (b *ChainBlockBuilder) BuildBlock(transactions []types.Transaction) (types.
 		return types.Block{}, errors.New("consensus: transactions required to build block")
 	}
 
-	prev := b.Chain.LatestBlock()
-	block := blockchain.NewBlock(prev.Index+1, prev.Hash, transactions)
-	return block, nil
+	return b.Chain.BuildBlock(transactions)
 }
 
-// NewChainFinalizer returns a Finalizer that commits blocks to the blockchain and emits events.
+// NewChainFinalizer returns a Finalizer that commits blocks to the blockchain and emits events,
+// including a mint.proposed event for every pending mint proposal the block opened. When a
+// block fails to commit, its transactions that no longer apply are rejected with their reason.
 func NewChainFinalizer(chain *blockchain.Blockchain, bus *observer.Bus) Finalizer {
 	return func(block types.Block) {
 		if chain == nil {
@@ -44,6 +44,8 @@ func NewChainFinalizer(chain *blockchain.Blockchain, bus *observer.Bus) Finalize
 		}
 
 		if err := chain.AddBlock(block); err != nil {
+			// The chain announces each rejection as a transaction.rejected event.
+			chain.RejectFailedBlock(block)
 			if bus != nil {
 				bus.Publish(observer.Event{
 					Type:      observer.EventError,
@@ -56,8 +58,6 @@ func NewChainFinalizer(chain *blockchain.Blockchain, bus *observer.Bus) Finalize
 			return
 		}
 
-		chain.ClearPendingTransactions()
-
 		if bus != nil {
 			bus.Publish(observer.Event{
 				Type:      observer.EventBlockCommitted,
@@ -72,6 +72,21 @@ func NewChainFinalizer(chain *blockchain.Blockchain, bus *observer.Bus) Finalize
 					Data:      tx,
 				})
 			}
+
+			// Announce the mint proposals still waiting for approvals; those
+			// the proposer could finalize alone are already minted.
+			for _, tx := range block.Transactions {
+				if tx.Type != blockchain.TxTypeProposeMint {
+					continue
+				}
+				if proposal, ok := chain.MintProposal(tx.TxID); ok {
+					bus.Publish(observer.Event{
+						Type:      observer.EventMintProposed,
+						Timestamp: time.Now().UTC(),
+						Data:      proposal,
+					})
+				}
+			}
 		}
 	}
 }
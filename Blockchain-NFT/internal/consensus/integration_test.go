@@ -7,10 +7,43 @@ import (
 	"storytelling-blockchain/internal/blockchain"
 	"storytelling-blockchain/internal/observer"
 	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
 )
 
+// noopTxHandler accepts any transaction of its type without touching state.
+type noopTxHandler struct {
+	txType string
+}
+
+func (h noopTxHandler) Type() string { return h.txType }
+
+func (noopTxHandler) Decode(tx types.Transaction) (interface{}, error) { return tx.Payload, nil }
+
+func (noopTxHandler) Validate(blockchain.TxContext, types.State, types.Transaction, interface{}) error {
+	return nil
+}
+
+func (noopTxHandler) Apply(blockchain.TxContext, *types.State, types.Transaction, interface{}) error {
+	return nil
+}
+
+// newTestChain builds a chain that additionally accepts the "test" and "story"
+// transactions used throughout the consensus tests.
+func newTestChain(t *testing.T) *blockchain.Blockchain {
+	t.Helper()
+
+	registry := blockchain.DefaultTxRegistry()
+	for _, txType := range []string{"test", "story"} {
+		if err := registry.Register(noopTxHandler{txType: txType}); err != nil {
+			t.Fatalf("register %s handler: %v", txType, err)
+		}
+	}
+
+	return blockchain.NewBlockchain(blockchain.WithTxRegistry(registry))
+}
+
 func TestChainBlockBuilder(t *testing.T) {
-	chain := blockchain.NewBlockchain()
+	chain := newTestChain(t)
 	builder := &ChainBlockBuilder{Chain: chain}
 
 	txs := []types.Transaction{{TxID: "tx-1", Type: "test"}}
@@ -36,7 +69,7 @@ func TestChainBlockBuilderRequiresTransactions(t *testing.T) {
 }
 
 func TestChainFinalizerPublishesEvents(t *testing.T) {
-	chain := blockchain.NewBlockchain()
+	chain := newTestChain(t)
 	bus := observer.NewBus()
 
 	id, ch := bus.Subscribe(4)
@@ -78,3 +111,135 @@ func TestChainFinalizerPublishesEvents(t *testing.T) {
 		t.Fatalf("expected transaction committed event, got %s", received[1].Type)
 	}
 }
+
+func TestChainFinalizerRejectsFailedBlockTransactions(t *testing.T) {
+	chain := newTestChain(t)
+	bus := observer.NewBus()
+	chain.SetObserver(bus)
+
+	id, ch := bus.Subscribe(4)
+	t.Cleanup(func() { bus.Unsubscribe(id) })
+
+	block, err := chain.BuildBlock([]types.Transaction{{TxID: "tx-1", Type: "test"}})
+	if err != nil {
+		t.Fatalf("failed to build block: %v", err)
+	}
+	// The extra transaction breaks the block's tx root and applies to no state.
+	block.Transactions = append(block.Transactions, types.Transaction{TxID: "tx-bad", Type: "unknown"})
+
+	NewChainFinalizer(chain, bus)(block)
+
+	if len(chain.Blocks()) != 1 {
+		t.Fatalf("expected the block to be refused")
+	}
+
+	receipt, ok := chain.Receipt("tx-bad")
+	if !ok || receipt.Status != types.TxStatusRejected || receipt.Reason == "" {
+		t.Fatalf("expected tx-bad to be rejected with a reason, got %+v", receipt)
+	}
+	if _, ok := chain.Receipt("tx-1"); ok {
+		t.Fatalf("expected tx-1, which still applies, not to be rejected")
+	}
+
+	for _, want := range []observer.EventType{observer.EventTransactionRejected, observer.EventError} {
+		select {
+		case ev := <-ch:
+			if ev.Type != want {
+				t.Fatalf("expected %s event, got %s", want, ev.Type)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+}
+
+func TestChainFinalizerAnnouncesMintProposals(t *testing.T) {
+	chain := newTestChain(t)
+	bus := observer.NewBus()
+
+	id, ch := bus.Subscribe(16)
+	t.Cleanup(func() { bus.Unsubscribe(id) })
+
+	finalizer := NewChainFinalizer(chain, bus)
+	commit := func(txs ...types.Transaction) {
+		t.Helper()
+
+		block, err := chain.BuildBlock(txs)
+		if err != nil {
+			t.Fatalf("failed to build block: %v", err)
+		}
+		finalizer(block)
+	}
+
+	wallets := make(map[string]types.Wallet)
+	keys := make(map[string]string)
+	sign := func(userID, txType string, payload interface{}) types.Transaction {
+		t.Helper()
+
+		tx, err := blockchain.NewSignedTransaction(txType, 100, chain.NextNonce(userID), payload)
+		if err != nil {
+			t.Fatalf("failed to build %s: %v", txType, err)
+		}
+		if tx.Signature, err = utils.SignEd25519(keys[userID], blockchain.TransactionSigningBytes(chain.ChainID(), tx)); err != nil {
+			t.Fatalf("failed to sign %s: %v", txType, err)
+		}
+		return tx
+	}
+
+	var walletTxs []types.Transaction
+	for _, userID := range []string{"alice", "bob"} {
+		pub, priv, err := utils.GenerateEd25519Keypair()
+		if err != nil {
+			t.Fatalf("failed to generate keypair: %v", err)
+		}
+		wallets[userID] = types.Wallet{Address: "0x" + userID, SupabaseUserID: userID, PublicKey: pub, PrivateKeyEncrypted: "enc", CreatedAt: 1}
+		keys[userID] = priv
+		tx, err := blockchain.NewTransaction(blockchain.TxTypeCreateWallet, 100, types.CreateWalletPayload{Wallet: wallets[userID]})
+		if err != nil {
+			t.Fatalf("failed to build wallet tx: %v", err)
+		}
+		walletTxs = append(walletTxs, tx)
+	}
+	commit(walletTxs...)
+	commit(sign("alice", blockchain.TxTypeCreateStory, types.CreateStoryPayload{
+		Story: types.StoryRecord{ID: "story-1", Title: "Story", CreatorID: "alice", Rules: types.StoryRules{MintWhileOpen: true}, CreatedAt: 100},
+	}))
+	for _, userID := range []string{"alice", "bob", "alice"} {
+		commit(sign(userID, blockchain.TxTypeContribution, types.ContributionPayload{
+			Contribution: types.Contribution{ContributorID: userID, WalletAddress: wallets[userID].Address, StoryID: "story-1", StoryLine: "A line", Timestamp: 100},
+		}))
+	}
+
+	proposal := sign("bob", blockchain.TxTypeProposeMint, types.ProposeMintPayload{
+		NFT: types.NFT{
+			TokenID:      "nft-1",
+			StoryID:      "story-1",
+			MainAuthor:   types.Author{SupabaseUserID: "alice", WalletAddress: wallets["alice"].Address, ContributionCount: 2, Weight: 2},
+			CoAuthors:    []types.Author{{SupabaseUserID: "bob", WalletAddress: wallets["bob"].Address, ContributionCount: 1, Weight: 1}},
+			OwnerID:      "alice",
+			OwnerAddress: wallets["alice"].Address,
+			Edition:      1,
+			MintedAt:     100,
+		},
+		ProposerID: "bob",
+		ExpiresAt:  types.NowUnix() + 3600,
+	})
+	commit(proposal)
+
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case ev := <-ch:
+			if ev.Type != observer.EventMintProposed {
+				continue
+			}
+			announced, ok := ev.Data.(types.MintProposal)
+			if !ok || announced.ID != proposal.TxID || announced.ProposerID != "bob" {
+				t.Fatalf("unexpected mint proposal event: %+v", ev.Data)
+			}
+			return
+		case <-timeout:
+			t.Fatalf("timed out waiting for the mint proposal event")
+		}
+	}
+}
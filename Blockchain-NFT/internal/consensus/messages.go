@@ -1,9 +1,9 @@
 package consensus
 
 import (
-	"encoding/json"
-
+	"storytelling-blockchain/internal/blockchain"
 	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/canonical"
 )
 
 // MessageType represents the stage of the PBFT protocol.
@@ -26,13 +26,17 @@ type Message struct {
 	Signature string      `json:"signature"`
 }
 
-// Digest returns a deterministic hash of the message contents for signing.
+// Digest returns the SHA-256 of the message's canonical encoding for signing.
+// The block is covered through its header and hash; transactions are committed
+// to by the header's TxRoot.
 func (m Message) Digest() ([]byte, error) {
-	clone := m
-	clone.Signature = ""
-	payload, err := json.Marshal(clone)
-	if err != nil {
-		return nil, err
-	}
-	return payload, nil
+	sum := canonical.NewEncoder(canonical.TagPBFTMessage).
+		String(string(m.Type)).
+		Int64(int64(m.View)).
+		Int64(int64(m.Sequence)).
+		String(m.SenderID).
+		String(m.Block.Hash).
+		Bytes(blockchain.EncodeHeader(m.Block.Header())).
+		Sum()
+	return sum[:], nil
 }
@@ -0,0 +1,45 @@
+package consensus
+
+import (
+	"bytes"
+	"testing"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func TestMessageDigestCoversFields(t *testing.T) {
+	block := types.Block{Index: 1, Timestamp: 10, PrevHash: "prev", TxRoot: "root", Hash: "hash"}
+	msg := Message{Type: MessagePrepare, View: 0, Sequence: 1, Block: block, SenderID: "node-1"}
+
+	digest, err := msg.Digest()
+	if err != nil {
+		t.Fatalf("digest failed: %v", err)
+	}
+
+	signed := msg
+	signed.Signature = "sig"
+	if other, _ := signed.Digest(); !bytes.Equal(digest, other) {
+		t.Fatalf("signature must not affect the digest")
+	}
+
+	mutations := []func(*Message){
+		func(m *Message) { m.Type = MessageCommit },
+		func(m *Message) { m.View = 1 },
+		func(m *Message) { m.Sequence = 2 },
+		func(m *Message) { m.SenderID = "node-2" },
+		func(m *Message) { m.Block.Hash = "other" },
+		func(m *Message) { m.Block.TxRoot = "other" },
+	}
+
+	for i, mutate := range mutations {
+		changed := msg
+		mutate(&changed)
+		other, err := changed.Digest()
+		if err != nil {
+			t.Fatalf("digest failed: %v", err)
+		}
+		if bytes.Equal(digest, other) {
+			t.Fatalf("mutation %d did not change the digest", i)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/canonical"
 )
 
 // Network abstracts the underlying transport used by the PBFT node.
@@ -28,9 +29,11 @@ type BlockBuilder interface {
 	BuildBlock(transactions []types.Transaction) (types.Block, error)
 }
 
-// Config encapsulates the dependencies required by a PBFT node.
+// Config encapsulates the dependencies required by a PBFT node. ChainID binds
+// message signatures to one network.
 type Config struct {
 	ID             string
+	ChainID        string
 	Peers          []string
 	FaultTolerance int
 	Network        Network
@@ -42,6 +45,7 @@ type Config struct {
 // PBFTNode represents a single validator participating in PBFT consensus.
 type PBFTNode struct {
 	id             string
+	chainID        string
 	peers          []string
 	faultTolerance int
 	network        Network
@@ -90,6 +94,7 @@ func NewPBFTNode(cfg Config) (*PBFTNode, error) {
 
 	return &PBFTNode{
 		id:             cfg.ID,
+		chainID:        cfg.ChainID,
 		peers:          append([]string(nil), cfg.Peers...),
 		faultTolerance: cfg.FaultTolerance,
 		network:        cfg.Network,
@@ -291,7 +296,7 @@ func (n *PBFTNode) signMessage(msg *Message) error {
 		return err
 	}
 
-	signature, err := n.signer.Sign(digest)
+	signature, err := n.signer.Sign(n.signingBytes(digest))
 	if err != nil {
 		return err
 	}
@@ -310,7 +315,13 @@ func (n *PBFTNode) verifyMessage(msg Message) bool {
 		return false
 	}
 
-	return n.signer.Verify(msg.SenderID, digest, msg.Signature)
+	return n.signer.Verify(msg.SenderID, n.signingBytes(digest), msg.Signature)
+}
+
+// signingBytes binds a message digest to the consensus signing domain of
+// the node's chain.
+func (n *PBFTNode) signingBytes(digest []byte) []byte {
+	return canonical.SigningBytes(canonical.PurposeConsensus, n.chainID, digest)
 }
 
 func (n *PBFTNode) broadcast(msg Message) error {
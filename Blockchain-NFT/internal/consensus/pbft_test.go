@@ -1,6 +1,7 @@
 package consensus
 
 import (
+	"encoding/hex"
 	"errors"
 	"sync"
 	"testing"
@@ -97,11 +98,11 @@ func (f finalizerAdapter) call(block types.Block) {
 type mockSigner struct{}
 
 func (mockSigner) Sign(data []byte) (string, error) {
-	return string(data), nil
+	return hex.EncodeToString(data), nil
 }
 
 func (mockSigner) Verify(sender string, data []byte, signature string) bool {
-	return string(data) == signature
+	return hex.EncodeToString(data) == signature
 }
 
 type rejectingSigner struct{}
@@ -243,3 +244,34 @@ func TestPBFTIgnoresInvalidSignatures(t *testing.T) {
 		t.Fatalf("finalize should not run for invalid signatures")
 	}
 }
+
+func TestPBFTSignaturesBoundToChainID(t *testing.T) {
+	newNode := func(chainID string) *PBFTNode {
+		node, err := NewPBFTNode(Config{
+			ID:       "node-1",
+			ChainID:  chainID,
+			Peers:    []string{"node-1"},
+			Network:  newMockNetwork(),
+			Signer:   mockSigner{},
+			Builder:  mockBuilder{},
+			Finalize: func(types.Block) {},
+		})
+		if err != nil {
+			t.Fatalf("failed to create node: %v", err)
+		}
+		return node
+	}
+
+	devnet := newNode("kahani-devnet")
+	msg := Message{Type: MessagePrepare, Sequence: 1, SenderID: "node-1", Block: types.Block{Index: 1, Hash: "hash"}}
+	if err := devnet.signMessage(&msg); err != nil {
+		t.Fatalf("sign message failed: %v", err)
+	}
+
+	if !devnet.verifyMessage(msg) {
+		t.Fatalf("expected the signature to verify on its own chain")
+	}
+	if newNode("kahani-testnet").verifyMessage(msg) {
+		t.Fatalf("expected the signature to be rejected on another chain")
+	}
+}
@@ -59,7 +59,7 @@ func StartNode(ctx context.Context, opts BootstrapOptions) (*NodeRuntime, error)
 }
 
 // StartCluster bootstraps multiple PBFT nodes and launches their message pumps.
-func StartCluster(ctx context.Context, transports map[string]*network.Node, peers []string, builder BlockBuilder, finalizers map[string]Finalizer, signer Signer, faultTolerance int) (map[string]*NodeRuntime, error) {
+func StartCluster(ctx context.Context, transports map[string]*network.Node, peers []string, builder BlockBuilder, finalizers map[string]Finalizer, signer Signer, chainID string, faultTolerance int) (map[string]*NodeRuntime, error) {
 	if len(transports) == 0 {
 		return nil, errors.New("consensus: transports required")
 	}
@@ -94,6 +94,7 @@ func StartCluster(ctx context.Context, transports map[string]*network.Node, peer
 
 		runtime, err := StartNode(parent, BootstrapOptions{
 			NodeID:         id,
+			ChainID:        chainID,
 			Peers:          peers,
 			FaultTolerance: faultTolerance,
 			Transport:      transport,
@@ -35,7 +35,7 @@ func StartService(ctx context.Context, chain *blockchain.Blockchain, bus *observ
 		finals[id] = finalizer
 	}
 
-	runtimes, err := StartCluster(ctx, transports, peers, builder, finals, signer, faultTolerance)
+	runtimes, err := StartCluster(ctx, transports, peers, builder, finals, signer, chain.ChainID(), faultTolerance)
 	if err != nil {
 		return nil, err
 	}
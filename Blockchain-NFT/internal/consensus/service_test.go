@@ -5,7 +5,6 @@ import (
 	"testing"
 	"time"
 
-	"storytelling-blockchain/internal/blockchain"
 	"storytelling-blockchain/internal/network"
 	"storytelling-blockchain/internal/observer"
 	"storytelling-blockchain/internal/types"
@@ -19,7 +18,7 @@ func TestStartServiceLifecycle(t *testing.T) {
 	transport.Register(nodeA)
 	transport.Register(nodeB)
 
-	chain := blockchain.NewBlockchain()
+	chain := newTestChain(t)
 	bus := observer.NewBus()
 	chain.SetObserver(bus)
 
@@ -81,7 +80,7 @@ func TestStartServiceErrors(t *testing.T) {
 		t.Fatal("expected error when blockchain missing")
 	}
 
-	chain := blockchain.NewBlockchain()
+	chain := newTestChain(t)
 	if _, err := StartService(context.Background(), chain, nil, map[string]*network.Node{}, nil, nil, 0); err == nil {
 		t.Fatal("expected error when transports missing")
 	}
@@ -92,7 +91,7 @@ func TestServiceProposeValidation(t *testing.T) {
 	node := network.NewNode("node-1", transport)
 	transport.Register(node)
 
-	chain := blockchain.NewBlockchain()
+	chain := newTestChain(t)
 	service, err := StartService(context.Background(), chain, nil, map[string]*network.Node{"node-1": node}, []string{"node-1"}, mockSigner{}, 0)
 	if err != nil {
 		t.Fatalf("failed to start service: %v", err)
@@ -107,7 +106,7 @@ func TestServiceProposeValidation(t *testing.T) {
 		t.Fatal("expected error when no transactions available")
 	}
 
-	tx := types.Transaction{TxID: "tx-1"}
+	tx := types.Transaction{TxID: "tx-1", Type: "test"}
 	if err := service.Propose("node-1", []types.Transaction{tx}); err != nil {
 		t.Fatalf("propose with transactions should succeed: %v", err)
 	}
@@ -0,0 +1,347 @@
+// Package mempool holds the transactions waiting to be included in a block.
+// Transactions are indexed by ID and kept in admission order. The pool bounds
+// its size and each sender's share of it, and drops entries that wait longer
+// than their time to live. Checking a transaction against the chain state is
+// left to the caller, which passes the check to Add.
+package mempool
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"storytelling-blockchain/internal/types"
+)
+
+const (
+	// DefaultMaxSize is the number of transactions a pool holds by default.
+	DefaultMaxSize = 5000
+	// DefaultMaxPerSender is the number of pending transactions a single
+	// signer may have by default.
+	DefaultMaxPerSender = 64
+	// DefaultTTL is how long a transaction may wait for a block by default.
+	DefaultTTL = 10 * time.Minute
+)
+
+var (
+	errMissingTxID  = errors.New("mempool: transaction id required")
+	errDuplicateTx  = errors.New("mempool: transaction already pending")
+	errPoolFull     = errors.New("mempool: pool is full")
+	errSenderLimit  = errors.New("mempool: sender has too many pending transactions")
+	errMissingCheck = errors.New("mempool: admission check required")
+	errExpired      = errors.New("mempool: transaction waited past its time to live")
+)
+
+// Exported errors for mempool admission.
+var (
+	ErrMissingTxID = errMissingTxID
+	ErrDuplicateTx = errDuplicateTx
+	ErrPoolFull    = errPoolFull
+	ErrSenderLimit = errSenderLimit
+	ErrExpired     = errExpired
+)
+
+// Config bounds a pool. Zero values fall back to the defaults.
+type Config struct {
+	MaxSize      int
+	MaxPerSender int
+	TTL          time.Duration
+}
+
+// DefaultConfig returns the limits used when none are configured.
+func DefaultConfig() Config {
+	return Config{MaxSize: DefaultMaxSize, MaxPerSender: DefaultMaxPerSender, TTL: DefaultTTL}
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxSize <= 0 {
+		c.MaxSize = DefaultMaxSize
+	}
+	if c.MaxPerSender <= 0 {
+		c.MaxPerSender = DefaultMaxPerSender
+	}
+	if c.TTL <= 0 {
+		c.TTL = DefaultTTL
+	}
+	return c
+}
+
+// CheckFunc validates tx before admission given the transactions already
+// pending and returns the user that signed it. Unsigned transactions return an
+// empty sender and are not subject to the per-sender limit.
+type CheckFunc func(pending Pending, tx types.Transaction) (sender string, err error)
+
+// Pending is the pool as a CheckFunc sees it. It is only valid during the
+// check, while the pool is locked.
+type Pending struct {
+	pool *Pool
+}
+
+// Transactions returns the pending transactions in admission order.
+func (v Pending) Transactions() []types.Transaction {
+	return v.pool.transactionsLocked()
+}
+
+// Len returns the number of pending transactions.
+func (v Pending) Len() int {
+	return len(v.pool.order)
+}
+
+// Removed counts the transactions that ever left the pool. A caller that
+// keeps its own view of the pending transactions can tell from Len and
+// Removed whether the pool changed other than by the admissions it checked.
+func (v Pending) Removed() uint64 {
+	return v.pool.removed
+}
+
+// CheckSender fails with ErrSenderLimit when sender already has as many
+// pending transactions as the pool allows.
+func (v Pending) CheckSender(sender string) error {
+	if sender != "" && v.pool.senders[sender] >= v.pool.cfg.MaxPerSender {
+		return errSenderLimit
+	}
+	return nil
+}
+
+// Stats summarises the pool for health reporting. The counters cover the
+// lifetime of the pool.
+type Stats struct {
+	Size         int    `json:"size"`
+	Capacity     int    `json:"capacity"`
+	MaxPerSender int    `json:"max_per_sender"`
+	Senders      int    `json:"senders"`
+	Admitted     uint64 `json:"admitted"`
+	Rejected     uint64 `json:"rejected"`
+	Included     uint64 `json:"included"`
+	Expired      uint64 `json:"expired"`
+}
+
+type entry struct {
+	tx       types.Transaction
+	sender   string
+	admitted time.Time
+}
+
+// Pool is a bounded set of pending transactions. It is safe for concurrent use.
+type Pool struct {
+	mu      sync.Mutex
+	cfg     Config
+	now     func() time.Time
+	entries map[string]*entry
+	order   []*entry
+	senders map[string]int
+	removed uint64
+	stats   Stats
+}
+
+// New builds an empty pool with the provided limits.
+func New(cfg Config) *Pool {
+	return &Pool{
+		cfg:     cfg.withDefaults(),
+		now:     time.Now,
+		entries: make(map[string]*entry),
+		senders: make(map[string]int),
+	}
+}
+
+// Add admits tx once check accepts it. Stale entries are expired first so
+// that they do not count against the limits.
+func (p *Pool) Add(tx types.Transaction, check CheckFunc) error {
+	return p.AddAt(tx, p.now(), check)
+}
+
+// AddAt admits tx as if it had arrived at admitted, so that a transaction
+// reloaded after a restart keeps its age. A transaction already past its time
+// to live is refused with ErrExpired.
+func (p *Pool) AddAt(tx types.Transaction, admitted time.Time, check CheckFunc) error {
+	if check == nil {
+		return errMissingCheck
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	p.expireLocked(now)
+
+	if admitted.Before(now.Add(-p.cfg.TTL)) {
+		p.stats.Rejected++
+		return errExpired
+	}
+
+	sender, err := p.admitLocked(tx, check)
+	if err != nil {
+		p.stats.Rejected++
+		return err
+	}
+
+	e := &entry{tx: tx, sender: sender, admitted: admitted}
+	p.entries[tx.TxID] = e
+	p.order = append(p.order, e)
+	if sender != "" {
+		p.senders[sender]++
+	}
+	p.stats.Admitted++
+	return nil
+}
+
+func (p *Pool) admitLocked(tx types.Transaction, check CheckFunc) (string, error) {
+	if tx.TxID == "" {
+		return "", errMissingTxID
+	}
+
+	if _, exists := p.entries[tx.TxID]; exists {
+		return "", errDuplicateTx
+	}
+
+	if len(p.order) >= p.cfg.MaxSize {
+		return "", errPoolFull
+	}
+
+	pending := Pending{pool: p}
+	sender, err := check(pending, tx)
+	if err != nil {
+		return "", err
+	}
+
+	if err := pending.CheckSender(sender); err != nil {
+		return "", err
+	}
+
+	return sender, nil
+}
+
+// Get returns a pending transaction by ID.
+func (p *Pool) Get(txID string) (types.Transaction, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e, ok := p.entries[txID]
+	if !ok {
+		return types.Transaction{}, false
+	}
+	return e.tx, true
+}
+
+// Transactions returns the pending transactions in admission order.
+func (p *Pool) Transactions() []types.Transaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.expireLocked(p.now())
+	return p.transactionsLocked()
+}
+
+func (p *Pool) transactionsLocked() []types.Transaction {
+	txs := make([]types.Transaction, len(p.order))
+	for i, e := range p.order {
+		txs[i] = e.tx
+	}
+	return txs
+}
+
+// LastNonce returns the highest nonce among the pending transactions of sender.
+func (p *Pool) LastNonce(sender string) (int64, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var last int64
+	found := false
+	for _, e := range p.order {
+		if e.sender == sender && sender != "" && (!found || e.tx.Nonce > last) {
+			last, found = e.tx.Nonce, true
+		}
+	}
+	return last, found
+}
+
+// Len returns the number of pending transactions.
+func (p *Pool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.order)
+}
+
+// Remove drops a pending transaction that was not included in a block.
+func (p *Pool) Remove(txID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	removed := p.removeLocked(func(e *entry) bool { return e.tx.TxID == txID })
+	return len(removed) > 0
+}
+
+// RemoveIncluded drops the transactions of a committed block and returns
+// how many of them were pending. Other pending transactions are kept.
+func (p *Pool) RemoveIncluded(txs []types.Transaction) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	included := make(map[string]struct{}, len(txs))
+	for _, tx := range txs {
+		included[tx.TxID] = struct{}{}
+	}
+
+	removed := p.removeLocked(func(e *entry) bool {
+		_, ok := included[e.tx.TxID]
+		return ok
+	})
+	p.stats.Included += uint64(len(removed))
+	return len(removed)
+}
+
+// Expire drops the transactions that have waited longer than the pool's time
+// to live and returns them.
+func (p *Pool) Expire() []types.Transaction {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.expireLocked(p.now())
+}
+
+func (p *Pool) expireLocked(now time.Time) []types.Transaction {
+	cutoff := now.Add(-p.cfg.TTL)
+	expired := p.removeLocked(func(e *entry) bool { return e.admitted.Before(cutoff) })
+	p.stats.Expired += uint64(len(expired))
+	return expired
+}
+
+// removeLocked drops the entries matching drop, keeping the others in order.
+func (p *Pool) removeLocked(drop func(*entry) bool) []types.Transaction {
+	var removed []types.Transaction
+	kept := p.order[:0]
+	for _, e := range p.order {
+		if !drop(e) {
+			kept = append(kept, e)
+			continue
+		}
+
+		removed = append(removed, e.tx)
+		delete(p.entries, e.tx.TxID)
+		if e.sender != "" {
+			p.senders[e.sender]--
+			if p.senders[e.sender] == 0 {
+				delete(p.senders, e.sender)
+			}
+		}
+	}
+
+	for i := len(kept); i < len(p.order); i++ {
+		p.order[i] = nil
+	}
+	p.order = kept
+	p.removed += uint64(len(removed))
+	return removed
+}
+
+// Stats reports the pool's size, limits and lifetime counters.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Size = len(p.order)
+	stats.Capacity = p.cfg.MaxSize
+	stats.MaxPerSender = p.cfg.MaxPerSender
+	stats.Senders = len(p.senders)
+	return stats
+}
@@ -0,0 +1,161 @@
+package mempool
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"storytelling-blockchain/internal/types"
+)
+
+func acceptAs(sender string) CheckFunc {
+	return func(Pending, types.Transaction) (string, error) {
+		return sender, nil
+	}
+}
+
+func TestPoolAdmission(t *testing.T) {
+	pool := New(Config{MaxSize: 3, MaxPerSender: 2})
+
+	if err := pool.Add(types.Transaction{}, acceptAs("")); !errors.Is(err, ErrMissingTxID) {
+		t.Fatalf("expected a missing id to be rejected, got %v", err)
+	}
+
+	checkErr := errors.New("invalid")
+	if err := pool.Add(types.Transaction{TxID: "bad"}, func(Pending, types.Transaction) (string, error) { return "", checkErr }); !errors.Is(err, checkErr) {
+		t.Fatalf("expected the check error, got %v", err)
+	}
+
+	var seen []types.Transaction
+	record := func(pending Pending, _ types.Transaction) (string, error) {
+		seen = pending.Transactions()
+		return "alice", nil
+	}
+	for _, id := range []string{"tx-1", "tx-2"} {
+		if err := pool.Add(types.Transaction{TxID: id}, record); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+	}
+	if len(seen) != 1 || seen[0].TxID != "tx-1" {
+		t.Fatalf("expected the check to see the pending transactions, got %+v", seen)
+	}
+
+	if err := pool.Add(types.Transaction{TxID: "tx-1"}, acceptAs("bob")); !errors.Is(err, ErrDuplicateTx) {
+		t.Fatalf("expected a duplicate to be rejected, got %v", err)
+	}
+	if err := pool.Add(types.Transaction{TxID: "tx-3"}, acceptAs("alice")); !errors.Is(err, ErrSenderLimit) {
+		t.Fatalf("expected the sender limit to apply, got %v", err)
+	}
+	if err := pool.Add(types.Transaction{TxID: "tx-3"}, acceptAs("")); err != nil {
+		t.Fatalf("expected unsigned transactions to bypass the sender limit, got %v", err)
+	}
+	if err := pool.Add(types.Transaction{TxID: "tx-4"}, acceptAs("bob")); !errors.Is(err, ErrPoolFull) {
+		t.Fatalf("expected a full pool to reject, got %v", err)
+	}
+
+	if tx, ok := pool.Get("tx-2"); !ok || tx.TxID != "tx-2" {
+		t.Fatalf("expected tx-2 to be pending")
+	}
+
+	stats := pool.Stats()
+	if stats.Size != 3 || stats.Senders != 1 || stats.Admitted != 3 || stats.Rejected != 5 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestPoolRemoveIncludedKeepsOthers(t *testing.T) {
+	pool := New(DefaultConfig())
+	for _, id := range []string{"tx-1", "tx-2", "tx-3"} {
+		if err := pool.Add(types.Transaction{TxID: id}, acceptAs("alice")); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+	}
+
+	if removed := pool.RemoveIncluded([]types.Transaction{{TxID: "tx-2"}, {TxID: "other"}}); removed != 1 {
+		t.Fatalf("expected one pending transaction removed, got %d", removed)
+	}
+
+	pending := pool.Transactions()
+	if len(pending) != 2 || pending[0].TxID != "tx-1" || pending[1].TxID != "tx-3" {
+		t.Fatalf("expected the other transactions to stay in order, got %+v", pending)
+	}
+	if stats := pool.Stats(); stats.Included != 1 || stats.Senders != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestPendingReportsRemovals(t *testing.T) {
+	pool := New(Config{MaxPerSender: 2})
+
+	var size int
+	var removed uint64
+	var limitErr error
+	inspect := func(pending Pending, _ types.Transaction) (string, error) {
+		size, removed, limitErr = pending.Len(), pending.Removed(), pending.CheckSender("alice")
+		return "alice", nil
+	}
+	for _, id := range []string{"tx-1", "tx-2"} {
+		if err := pool.Add(types.Transaction{TxID: id}, inspect); err != nil {
+			t.Fatalf("add %s: %v", id, err)
+		}
+	}
+	if size != 1 || removed != 0 || limitErr != nil {
+		t.Fatalf("expected one pending, none removed and room for alice, got %d, %d, %v", size, removed, limitErr)
+	}
+
+	pool.Remove("tx-1")
+	if err := pool.Add(types.Transaction{TxID: "tx-3"}, inspect); err != nil {
+		t.Fatalf("add tx-3: %v", err)
+	}
+	if size != 1 || removed != 1 {
+		t.Fatalf("expected the removal to be counted, got %d and %d", size, removed)
+	}
+
+	if err := pool.Add(types.Transaction{TxID: "tx-4"}, inspect); !errors.Is(err, ErrSenderLimit) || !errors.Is(limitErr, ErrSenderLimit) {
+		t.Fatalf("expected alice to be at the sender limit, got %v and %v", err, limitErr)
+	}
+}
+
+func TestPoolExpiresStaleEntries(t *testing.T) {
+	now := time.Unix(1000, 0)
+	pool := New(Config{MaxSize: 1, TTL: time.Minute})
+	pool.now = func() time.Time { return now }
+
+	if err := pool.Add(types.Transaction{TxID: "tx-1"}, acceptAs("alice")); err != nil {
+		t.Fatalf("add tx-1: %v", err)
+	}
+	if err := pool.Add(types.Transaction{TxID: "tx-2"}, acceptAs("bob")); !errors.Is(err, ErrPoolFull) {
+		t.Fatalf("expected a full pool to reject, got %v", err)
+	}
+
+	// An expired entry frees its slot for the next admission.
+	now = now.Add(time.Minute + time.Second)
+	if err := pool.Add(types.Transaction{TxID: "tx-2"}, acceptAs("bob")); err != nil {
+		t.Fatalf("add tx-2 after expiry: %v", err)
+	}
+
+	pending := pool.Transactions()
+	if len(pending) != 1 || pending[0].TxID != "tx-2" {
+		t.Fatalf("expected only tx-2 to remain, got %+v", pending)
+	}
+	if stats := pool.Stats(); stats.Expired != 1 || stats.Senders != 1 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if expired := pool.Expire(); len(expired) != 1 || expired[0].TxID != "tx-2" {
+		t.Fatalf("expected tx-2 to expire, got %+v", expired)
+	}
+
+	// Reloaded transactions keep their age, and stale ones are refused.
+	if err := pool.AddAt(types.Transaction{TxID: "tx-3"}, now.Add(-2*time.Minute), acceptAs("alice")); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected a stale admission to be refused, got %v", err)
+	}
+	if err := pool.AddAt(types.Transaction{TxID: "tx-3"}, now.Add(-30*time.Second), acceptAs("alice")); err != nil {
+		t.Fatalf("add tx-3 with its original age: %v", err)
+	}
+	now = now.Add(31 * time.Second)
+	if expired := pool.Expire(); len(expired) != 1 || expired[0].TxID != "tx-3" {
+		t.Fatalf("expected tx-3 to expire on its original schedule, got %+v", expired)
+	}
+}
@@ -14,6 +14,8 @@ const (
 	EventBlockCommitted       EventType = "block.committed"
 	EventTransactionQueued    EventType = "transaction.queued"
 	EventTransactionCommitted EventType = "transaction.committed"
+	EventTransactionRejected  EventType = "transaction.rejected"
+	EventMintProposed         EventType = "mint.proposed"
 	EventError                EventType = "error"
 )
 
@@ -71,39 +73,31 @@ func (b *Bus) Unsubscribe(id string) {
 		return
 	}
 
-	var ch chan Event
-
 	b.mu.Lock()
-	if b.subs != nil {
-		ch = b.subs[id]
-		delete(b.subs, id)
-	}
-	b.mu.Unlock()
+	defer b.mu.Unlock()
 
-	if ch != nil {
+	if ch, ok := b.subs[id]; ok {
+		delete(b.subs, id)
 		close(ch)
 	}
 }
 
 // Publish fan-outs the event to all subscribers using best-effort delivery.
+// Sends never block, so they happen under the read lock; that keeps a
+// concurrent Unsubscribe from closing a channel mid-send.
 func (b *Bus) Publish(event Event) {
 	if b == nil {
 		return
 	}
 
 	b.mu.RLock()
+	defer b.mu.RUnlock()
+
 	if b.closed {
-		b.mu.RUnlock()
 		return
 	}
 
-	targets := make([]chan Event, 0, len(b.subs))
 	for _, ch := range b.subs {
-		targets = append(targets, ch)
-	}
-	b.mu.RUnlock()
-
-	for _, ch := range targets {
 		select {
 		case ch <- event:
 		default:
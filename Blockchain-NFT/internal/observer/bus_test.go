@@ -38,6 +38,25 @@ func TestBusUnsubscribeClosesChannel(t *testing.T) {
 	}
 }
 
+func TestBusUnsubscribeDuringPublish(t *testing.T) {
+	bus := NewBus()
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		for i := 0; i < 1000; i++ {
+			bus.Publish(Event{Type: EventTransactionCommitted})
+		}
+	}()
+
+	// Short-lived subscriptions must never be closed under a send.
+	for i := 0; i < 1000; i++ {
+		id, _ := bus.Subscribe(1)
+		bus.Unsubscribe(id)
+	}
+	<-done
+}
+
 func TestBusClose(t *testing.T) {
 	bus := NewBus()
 	var wg sync.WaitGroup
@@ -0,0 +1,225 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/consensus/sharding"
+	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+)
+
+// DefaultRetryBlocks is how many committed blocks the scheduler waits for a
+// submitted mint before submitting it again.
+const DefaultRetryBlocks = 3
+
+// Proposer submits transactions into consensus.
+type Proposer interface {
+	Propose(nodeID string, txs []types.Transaction) error
+}
+
+// MintSchedulerConfig bundles the dependencies of a MintScheduler.
+type MintSchedulerConfig struct {
+	Chain    *blockchain.Blockchain
+	Observer *observer.Bus
+	Proposer Proposer
+	IPFS     storage.IPFSClient
+	// NodeID is this node and Nodes the consensus nodes, this one included.
+	// A story is minted by the node sharding.SelectNode picks for it.
+	NodeID string
+	Nodes  []string
+	// RetryBlocks defaults to DefaultRetryBlocks.
+	RetryBlocks int
+}
+
+// MintScheduler mints stories automatically when they reach the mint
+// triggers of their rules. Triggers are read from the committed state and
+// fire once on-chain, so restarts and other nodes never mint twice.
+type MintScheduler struct {
+	chain       *blockchain.Blockchain
+	bus         *observer.Bus
+	proposer    Proposer
+	ipfs        storage.IPFSClient
+	nodeID      string
+	nodes       []string
+	retryBlocks int
+
+	mu       sync.Mutex
+	inflight map[string]submission
+}
+
+// submission is a mint waiting to be committed.
+type submission struct {
+	txID   string
+	height int
+}
+
+// NewMintScheduler validates the configuration and builds a scheduler.
+func NewMintScheduler(cfg MintSchedulerConfig) (*MintScheduler, error) {
+	if cfg.Chain == nil || cfg.Proposer == nil || cfg.IPFS == nil {
+		return nil, errors.New("scheduler: chain, proposer and ipfs are required")
+	}
+
+	if cfg.NodeID == "" {
+		return nil, errors.New("scheduler: node id is required")
+	}
+
+	// Every node must see the same order to agree on the shard owners.
+	nodes := append([]string{}, cfg.Nodes...)
+	sort.Strings(nodes)
+
+	retryBlocks := cfg.RetryBlocks
+	if retryBlocks <= 0 {
+		retryBlocks = DefaultRetryBlocks
+	}
+
+	return &MintScheduler{
+		chain:       cfg.Chain,
+		bus:         cfg.Observer,
+		proposer:    cfg.Proposer,
+		ipfs:        cfg.IPFS,
+		nodeID:      cfg.NodeID,
+		nodes:       nodes,
+		retryBlocks: retryBlocks,
+		inflight:    make(map[string]submission),
+	}, nil
+}
+
+// Run evaluates the triggers once and again after every committed block
+// until ctx is done. Failed mints are published on the bus as errors.
+func (s *MintScheduler) Run(ctx context.Context) {
+	id, events := s.bus.Subscribe(64)
+	defer s.bus.Unsubscribe(id)
+
+	s.evaluate()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if event.Type == observer.EventBlockCommitted {
+				s.evaluate()
+			}
+		}
+	}
+}
+
+func (s *MintScheduler) evaluate() {
+	if _, err := s.Evaluate(); err != nil {
+		s.bus.Publish(observer.Event{
+			Type:      observer.EventError,
+			Timestamp: time.Now().UTC(),
+			Data: map[string]string{
+				"message": err.Error(),
+			},
+		})
+	}
+}
+
+// Evaluate submits a mint_nft for every story this node owns whose triggers
+// are due, and returns the submitted transactions. A story is not submitted
+// again while its previous mint is waiting to be committed.
+func (s *MintScheduler) Evaluate() ([]types.Transaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	height := s.chain.LatestBlock().Index
+	dueMints := s.chain.DueMints()
+
+	// Stories that are no longer due were minted or are waiting on a proposal.
+	dueStories := make(map[string]bool, len(dueMints))
+	for _, due := range dueMints {
+		dueStories[due.StoryID] = true
+	}
+	for storyID := range s.inflight {
+		if !dueStories[storyID] {
+			delete(s.inflight, storyID)
+		}
+	}
+
+	var (
+		submitted []types.Transaction
+		errs      []error
+	)
+	for _, due := range dueMints {
+		if !s.owns(due.StoryID) || s.waiting(due.StoryID, height) {
+			continue
+		}
+
+		tx, err := s.mint(due)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("scheduler: mint story %s: %w", due.StoryID, err))
+			continue
+		}
+
+		s.inflight[due.StoryID] = submission{txID: tx.TxID, height: height}
+		submitted = append(submitted, tx)
+	}
+
+	return submitted, errors.Join(errs...)
+}
+
+func (s *MintScheduler) owns(storyID string) bool {
+	return len(s.nodes) == 0 || sharding.SelectNode(s.nodes, storyID) == s.nodeID
+}
+
+// waiting reports whether the story's last mint may still be committed.
+func (s *MintScheduler) waiting(storyID string, height int) bool {
+	pending, ok := s.inflight[storyID]
+	if !ok {
+		return false
+	}
+
+	if _, committed := s.chain.TransactionProof(pending.txID); committed || height-pending.height >= s.retryBlocks {
+		delete(s.inflight, storyID)
+		return false
+	}
+
+	return true
+}
+
+func (s *MintScheduler) mint(due blockchain.DueMint) (types.Transaction, error) {
+	record, _ := s.chain.GetStory(due.StoryID)
+	title := record.Title
+	if title == "" {
+		title = record.ID
+	}
+
+	summary := fmt.Sprintf("Minted automatically on reaching the %s milestone.", strings.Join(due.Triggers, " and "))
+	story, err := s.chain.NextEditionStory(due.StoryID, title, summary)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+
+	nft, err := blockchain.MintNFT(story, s.ipfs)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+
+	tx, err := blockchain.NewTransaction(blockchain.TxTypeMintNFT, nft.MintedAt, types.MintNFTPayload{NFT: nft, Triggers: due.Triggers})
+	if err != nil {
+		return types.Transaction{}, err
+	}
+
+	if err := s.chain.EnqueueTransaction(tx); err != nil {
+		return types.Transaction{}, err
+	}
+	if err := s.proposer.Propose(s.nodeID, []types.Transaction{tx}); err != nil {
+		// Withdraw the mint so a retry does not queue a second edition.
+		s.chain.WithdrawTransaction(tx.TxID)
+		return types.Transaction{}, err
+	}
+
+	return tx, nil
+}
@@ -0,0 +1,136 @@
+package scheduler
+
+import (
+	"testing"
+
+	"storytelling-blockchain/internal/blockchain"
+	"storytelling-blockchain/internal/consensus/sharding"
+	"storytelling-blockchain/internal/observer"
+	"storytelling-blockchain/internal/storage"
+	"storytelling-blockchain/internal/types"
+	"storytelling-blockchain/pkg/utils"
+)
+
+type proposerStub struct {
+	nodeIDs []string
+	txs     []types.Transaction
+}
+
+func (p *proposerStub) Propose(nodeID string, txs []types.Transaction) error {
+	p.nodeIDs = append(p.nodeIDs, nodeID)
+	p.txs = append(p.txs, txs...)
+	return nil
+}
+
+func commit(t *testing.T, chain *blockchain.Blockchain, txs ...types.Transaction) {
+	t.Helper()
+
+	block, err := chain.BuildBlock(txs)
+	if err != nil {
+		t.Fatalf("build block: %v", err)
+	}
+	if err := chain.AddBlock(block); err != nil {
+		t.Fatalf("add block: %v", err)
+	}
+}
+
+func signed(t *testing.T, chain *blockchain.Blockchain, userID, priv, txType string, timestamp int64, payload interface{}) types.Transaction {
+	t.Helper()
+
+	tx, err := blockchain.NewSignedTransaction(txType, timestamp, chain.NextNonce(userID), payload)
+	if err != nil {
+		t.Fatalf("build %s: %v", txType, err)
+	}
+	if tx.Signature, err = utils.SignEd25519(priv, blockchain.TransactionSigningBytes(chain.ChainID(), tx)); err != nil {
+		t.Fatalf("sign %s: %v", txType, err)
+	}
+	return tx
+}
+
+func TestMintSchedulerMintsOnMilestones(t *testing.T) {
+	originalNow := types.NowUnix
+	types.NowUnix = func() int64 { return 100 }
+	t.Cleanup(func() { types.NowUnix = originalNow })
+
+	chain := blockchain.NewBlockchain()
+	pub, priv, err := utils.GenerateEd25519Keypair()
+	if err != nil {
+		t.Fatalf("generate keypair: %v", err)
+	}
+	alice := types.Wallet{Address: "0xalice", SupabaseUserID: "alice", PublicKey: pub, PrivateKeyEncrypted: "enc", CreatedAt: 10}
+
+	walletTx, err := blockchain.NewTransaction(blockchain.TxTypeCreateWallet, 10, types.CreateWalletPayload{Wallet: alice})
+	if err != nil {
+		t.Fatalf("build wallet: %v", err)
+	}
+	commit(t, chain, walletTx)
+	commit(t, chain, signed(t, chain, "alice", priv, blockchain.TxTypeCreateStory, 20, types.CreateStoryPayload{Story: types.StoryRecord{
+		ID:        "story-1",
+		Title:     "Milestones",
+		CreatorID: "alice",
+		Rules:     types.StoryRules{MintAtContributions: 2, MintOnClose: true, MintWhileOpen: true},
+		CreatedAt: 20,
+	}}))
+	for i, line := range []string{"One", "Two"} {
+		commit(t, chain, signed(t, chain, "alice", priv, blockchain.TxTypeContribution, int64(30+i), types.ContributionPayload{Contribution: types.Contribution{
+			ContributorID: "alice",
+			WalletAddress: alice.Address,
+			StoryID:       "story-1",
+			StoryLine:     line,
+			Timestamp:     int64(30 + i),
+		}}))
+	}
+
+	nodes := []string{"node-b", "node-a"}
+	owner := sharding.SelectNode([]string{"node-a", "node-b"}, "story-1")
+	other := "node-a"
+	if owner == other {
+		other = "node-b"
+	}
+
+	newScheduler := func(nodeID string) (*MintScheduler, *proposerStub) {
+		proposer := &proposerStub{}
+		s, err := NewMintScheduler(MintSchedulerConfig{Chain: chain, Observer: observer.NewBus(), Proposer: proposer, IPFS: storage.NewMemoryIPFS(), NodeID: nodeID, Nodes: nodes})
+		if err != nil {
+			t.Fatalf("new scheduler: %v", err)
+		}
+		return s, proposer
+	}
+
+	bystander, _ := newScheduler(other)
+	if txs, err := bystander.Evaluate(); err != nil || len(txs) != 0 {
+		t.Fatalf("expected only the shard owner to mint, got %v %v", txs, err)
+	}
+
+	scheduler, proposer := newScheduler(owner)
+	txs, err := scheduler.Evaluate()
+	if err != nil || len(txs) != 1 || len(proposer.nodeIDs) != 1 || proposer.nodeIDs[0] != owner {
+		t.Fatalf("expected one mint proposed to the owner, got %v %v %v", txs, proposer.nodeIDs, err)
+	}
+	if again, _ := scheduler.Evaluate(); len(again) != 0 {
+		t.Fatalf("expected no resubmission while the mint is pending, got %v", again)
+	}
+
+	commit(t, chain, txs[0])
+	if editions := chain.StoryEditions("story-1"); len(editions) != 1 || editions[0].Title != "Milestones" {
+		t.Fatalf("expected the first edition, got %+v", editions)
+	}
+
+	// A restarted scheduler reads the fired triggers from the chain.
+	restarted, _ := newScheduler(owner)
+	if again, _ := restarted.Evaluate(); len(again) != 0 {
+		t.Fatalf("expected no mint after restart, got %v", again)
+	}
+
+	commit(t, chain, signed(t, chain, "alice", priv, blockchain.TxTypeCloseStory, 40, types.CloseStoryPayload{StoryID: "story-1", ClosedBy: "alice"}))
+	txs, err = scheduler.Evaluate()
+	if err != nil || len(txs) != 1 {
+		t.Fatalf("expected the close trigger to mint, got %v %v", txs, err)
+	}
+	commit(t, chain, txs[0])
+
+	story, _ := chain.GetStory("story-1")
+	if story.Edition != 2 || len(story.FiredMintTriggers) != 2 {
+		t.Fatalf("expected the second edition with both triggers fired, got %+v", story)
+	}
+}
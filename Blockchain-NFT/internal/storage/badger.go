@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/dgraph-io/badger/v4"
 
@@ -44,7 +45,8 @@ func (bs *BadgerStorage) Close() error {
 	return bs.db.Close()
 }
 
-// SaveBlock persists the block keyed by its index.
+// SaveBlock persists the block keyed by its index, together with the index
+// entries of its transactions.
 func (bs *BadgerStorage) SaveBlock(block types.Block) error {
 	payload, err := json.Marshal(block)
 	if err != nil {
@@ -52,8 +54,39 @@ func (bs *BadgerStorage) SaveBlock(block types.Block) error {
 	}
 
 	return bs.db.Update(func(txn *badger.Txn) error {
-		return txn.Set([]byte(blockKey(block.Index)), payload)
+		if err := txn.Set([]byte(blockKey(block.Index)), payload); err != nil {
+			return err
+		}
+		for _, tx := range block.Transactions {
+			if err := txn.Set([]byte(txIndexKey(tx.TxID)), []byte(strconv.Itoa(block.Index))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetTransactionBlockIndex returns the index of the block that committed the
+// transaction.
+func (bs *BadgerStorage) GetTransactionBlockIndex(txID string) (int, error) {
+	var index int
+
+	err := bs.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(txIndexKey(txID)))
+		if err != nil {
+			if errors.Is(err, badger.ErrKeyNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		return item.Value(func(val []byte) error {
+			index, err = strconv.Atoi(string(val))
+			return err
+		})
 	})
+
+	return index, err
 }
 
 // GetBlock retrieves the block for the provided index.
@@ -94,6 +127,13 @@ func (bs *BadgerStorage) GetState() (types.State, error) {
 	state := types.State{
 		WalletRegistry: make(map[string]types.Wallet),
 		NFTRegistry:    make(map[string]types.NFT),
+		ShareBalances:  make(map[string]map[string]int64),
+		StoryRegistry:  make(map[string]types.StoryRecord),
+		StoryMembers:   make(map[string]map[string]string),
+		Contributions:  make(map[string]types.ContributionRecord),
+		MintProposals:  make(map[string]types.MintProposal),
+		Nonces:         make(map[string]int64),
+		Validators:     make(map[string]types.Validator),
 	}
 
 	err := bs.db.View(func(txn *badger.Txn) error {
@@ -113,10 +153,136 @@ func (bs *BadgerStorage) GetState() (types.State, error) {
 	return state, err
 }
 
+// SavePendingTransaction persists a mempool transaction keyed by its ID.
+func (bs *BadgerStorage) SavePendingTransaction(pending types.PendingTransaction) error {
+	payload, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+
+	return bs.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(pendingKey(pending.Transaction.TxID)), payload)
+	})
+}
+
+// DeletePendingTransactions removes mempool transactions. IDs that are not
+// stored are ignored.
+func (bs *BadgerStorage) DeletePendingTransactions(txIDs ...string) error {
+	if len(txIDs) == 0 {
+		return nil
+	}
+
+	return bs.db.Update(func(txn *badger.Txn) error {
+		for _, txID := range txIDs {
+			if err := txn.Delete([]byte(pendingKey(txID))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PendingTransactions retrieves every persisted mempool transaction, ordered
+// by transaction ID.
+func (bs *BadgerStorage) PendingTransactions() ([]types.PendingTransaction, error) {
+	var pending []types.PendingTransaction
+
+	err := bs.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(pendingKeyPrefix)
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var entry types.PendingTransaction
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &entry)
+			}); err != nil {
+				return err
+			}
+			pending = append(pending, entry)
+		}
+		return nil
+	})
+
+	return pending, err
+}
+
+// SaveRejectedReceipt persists a rejection receipt keyed by its transaction ID.
+func (bs *BadgerStorage) SaveRejectedReceipt(rejected types.RejectedReceipt) error {
+	payload, err := json.Marshal(rejected)
+	if err != nil {
+		return err
+	}
+
+	return bs.db.Update(func(txn *badger.Txn) error {
+		return txn.Set([]byte(rejectedKey(rejected.Receipt.TxID)), payload)
+	})
+}
+
+// DeleteRejectedReceipts removes rejection receipts. IDs that are not stored
+// are ignored.
+func (bs *BadgerStorage) DeleteRejectedReceipts(txIDs ...string) error {
+	if len(txIDs) == 0 {
+		return nil
+	}
+
+	return bs.db.Update(func(txn *badger.Txn) error {
+		for _, txID := range txIDs {
+			if err := txn.Delete([]byte(rejectedKey(txID))); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// RejectedReceipts retrieves every persisted rejection receipt, ordered by
+// transaction ID.
+func (bs *BadgerStorage) RejectedReceipts() ([]types.RejectedReceipt, error) {
+	var rejected []types.RejectedReceipt
+
+	err := bs.db.View(func(txn *badger.Txn) error {
+		prefix := []byte(rejectedKeyPrefix)
+		it := txn.NewIterator(badger.IteratorOptions{Prefix: prefix})
+		defer it.Close()
+
+		for it.Rewind(); it.Valid(); it.Next() {
+			var entry types.RejectedReceipt
+			if err := it.Item().Value(func(val []byte) error {
+				return json.Unmarshal(val, &entry)
+			}); err != nil {
+				return err
+			}
+			rejected = append(rejected, entry)
+		}
+		return nil
+	})
+
+	return rejected, err
+}
+
 func blockKey(index int) string {
 	return fmt.Sprintf("block:%d", index)
 }
 
+func txIndexKey(txID string) string {
+	return "txindex:" + txID
+}
+
 func stateKey() string {
 	return "state:latest"
 }
+
+// pendingKeyPrefix keeps mempool entries apart from blocks and state.
+const pendingKeyPrefix = "mempool:"
+
+func pendingKey(txID string) string {
+	return pendingKeyPrefix + txID
+}
+
+// rejectedKeyPrefix keeps rejection receipts apart from the mempool.
+const rejectedKeyPrefix = "rejected:"
+
+func rejectedKey(txID string) string {
+	return rejectedKeyPrefix + txID
+}
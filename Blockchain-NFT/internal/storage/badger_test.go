@@ -78,3 +78,114 @@ func TestBadgerStorageStateRoundTrip(t *testing.T) {
 		t.Fatalf("unexpected state contents: %+v", restored)
 	}
 }
+
+func TestBadgerStoragePendingTransactions(t *testing.T) {
+	bs, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = bs.Close()
+	})
+
+	for i, id := range []string{"tx-b", "tx-a"} {
+		pending := types.PendingTransaction{Transaction: types.Transaction{TxID: id, Type: "create_wallet"}, QueuedAt: int64(i + 1)}
+		if err := bs.SavePendingTransaction(pending); err != nil {
+			t.Fatalf("save pending %s failed: %v", id, err)
+		}
+	}
+
+	// Pending transactions live under their own prefix, apart from blocks.
+	if err := bs.SaveBlock(blockchain.NewBlock(0, "", nil)); err != nil {
+		t.Fatalf("failed to save block: %v", err)
+	}
+
+	pending, err := bs.PendingTransactions()
+	if err != nil {
+		t.Fatalf("list pending failed: %v", err)
+	}
+	if len(pending) != 2 || pending[0].Transaction.TxID != "tx-a" || pending[1].QueuedAt != 1 {
+		t.Fatalf("unexpected pending transactions: %+v", pending)
+	}
+
+	if err := bs.DeletePendingTransactions("tx-a", "missing"); err != nil {
+		t.Fatalf("delete pending failed: %v", err)
+	}
+
+	pending, err = bs.PendingTransactions()
+	if err != nil {
+		t.Fatalf("list pending failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Transaction.TxID != "tx-b" {
+		t.Fatalf("expected only tx-b to remain, got %+v", pending)
+	}
+}
+
+func TestBadgerStorageTransactionIndex(t *testing.T) {
+	bs, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = bs.Close()
+	})
+
+	block := blockchain.NewBlock(3, "prev", []types.Transaction{{TxID: "tx-1"}, {TxID: "tx-2"}})
+	if err := bs.SaveBlock(block); err != nil {
+		t.Fatalf("failed to save block: %v", err)
+	}
+
+	index, err := bs.GetTransactionBlockIndex("tx-2")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if index != 3 {
+		t.Fatalf("expected block 3, got %d", index)
+	}
+
+	if _, err := bs.GetTransactionBlockIndex("missing"); !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestBadgerStorageRejectedReceipts(t *testing.T) {
+	bs, err := storage.NewBadgerStorage(storage.BadgerConfig{InMemory: true})
+	if err != nil {
+		t.Fatalf("failed to create storage: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = bs.Close()
+	})
+
+	for i, id := range []string{"tx-b", "tx-a"} {
+		rejected := types.RejectedReceipt{Receipt: types.Receipt{TxID: id, Status: types.TxStatusRejected, BlockIndex: -1, Reason: "invalid"}, RejectedAt: int64(i + 1)}
+		if err := bs.SaveRejectedReceipt(rejected); err != nil {
+			t.Fatalf("save receipt %s failed: %v", id, err)
+		}
+	}
+
+	// Rejection receipts live under their own prefix, apart from the mempool.
+	if err := bs.SavePendingTransaction(types.PendingTransaction{Transaction: types.Transaction{TxID: "tx-c"}}); err != nil {
+		t.Fatalf("save pending failed: %v", err)
+	}
+
+	rejected, err := bs.RejectedReceipts()
+	if err != nil {
+		t.Fatalf("list receipts failed: %v", err)
+	}
+	if len(rejected) != 2 || rejected[0].Receipt.TxID != "tx-a" || rejected[1].RejectedAt != 1 || rejected[1].Receipt.Reason != "invalid" {
+		t.Fatalf("unexpected rejection receipts: %+v", rejected)
+	}
+
+	if err := bs.DeleteRejectedReceipts("tx-a", "missing"); err != nil {
+		t.Fatalf("delete receipts failed: %v", err)
+	}
+
+	rejected, err = bs.RejectedReceipts()
+	if err != nil {
+		t.Fatalf("list receipts failed: %v", err)
+	}
+	if len(rejected) != 1 || rejected[0].Receipt.TxID != "tx-b" {
+		t.Fatalf("expected only tx-b to remain, got %+v", rejected)
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // Block represents a single block in the blockchain.
 type Block struct {
@@ -8,22 +11,240 @@ type Block struct {
 	Timestamp           int64             `json:"timestamp"`
 	Transactions        []Transaction     `json:"transactions"`
 	PrevHash            string            `json:"prev_hash"`
+	TxRoot              string            `json:"tx_root"`
+	StateRoot           string            `json:"state_root"`
 	Hash                string            `json:"hash"`
 	ValidatorSignatures map[string]string `json:"validator_signatures"`
 	Nonce               int               `json:"nonce"`
 }
 
-// Transaction captures the actions recorded on-chain.
+// BlockHeader holds the block fields covered by the block hash. Transactions
+// are committed to through TxRoot and the post-block state through StateRoot.
+type BlockHeader struct {
+	Index     int    `json:"index"`
+	Timestamp int64  `json:"timestamp"`
+	PrevHash  string `json:"prev_hash"`
+	TxRoot    string `json:"tx_root"`
+	StateRoot string `json:"state_root"`
+	Nonce     int    `json:"nonce"`
+}
+
+// Header returns the hashed header of the block.
+func (b Block) Header() BlockHeader {
+	return BlockHeader{
+		Index:     b.Index,
+		Timestamp: b.Timestamp,
+		PrevHash:  b.PrevHash,
+		TxRoot:    b.TxRoot,
+		StateRoot: b.StateRoot,
+		Nonce:     b.Nonce,
+	}
+}
+
+// Transaction is the versioned envelope recorded on-chain. Payload holds the
+// JSON encoding of the typed payload defined for Type at Version. Nonce is
+// the signing wallet's transaction counter, starting at 1; transactions not
+// signed by a wallet leave it zero.
 type Transaction struct {
-	TxID      string      `json:"tx_id"`
-	Type      string      `json:"type"`
-	Data      interface{} `json:"data"`
-	Timestamp int64       `json:"timestamp"`
-	Signature string      `json:"signature"`
+	TxID      string          `json:"tx_id"`
+	Type      string          `json:"type"`
+	Version   int             `json:"version"`
+	Payload   json.RawMessage `json:"payload"`
+	Timestamp int64           `json:"timestamp"`
+	Nonce     int64           `json:"nonce,omitempty"`
+	Signature string          `json:"signature"`
+}
+
+// PendingTransaction is a mempool transaction as kept in storage between
+// restarts. QueuedAt is the admission time in Unix nanoseconds, so that the
+// mempool order and time to live survive a restart.
+type PendingTransaction struct {
+	Transaction Transaction `json:"transaction"`
+	QueuedAt    int64       `json:"queued_at"`
+}
+
+// Receipt reports what became of a transaction: waiting in the mempool,
+// committed at BlockIndex, or rejected for Reason. BlockIndex is -1 until the
+// transaction is committed.
+type Receipt struct {
+	TxID       string `json:"tx_id"`
+	Type       string `json:"type,omitempty"`
+	Status     string `json:"status"`
+	BlockIndex int    `json:"block_index"`
+	BlockHash  string `json:"block_hash,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// RejectedReceipt is a rejection receipt as kept in storage between restarts.
+// RejectedAt is the rejection time in Unix nanoseconds, so that the oldest
+// receipts are still forgotten first after a restart.
+type RejectedReceipt struct {
+	Receipt    Receipt `json:"receipt"`
+	RejectedAt int64   `json:"rejected_at"`
+}
+
+// Transaction receipt states.
+const (
+	TxStatusPending   = "pending"
+	TxStatusCommitted = "committed"
+	TxStatusRejected  = "rejected"
+)
+
+// CreateWalletPayload is the payload of a create_wallet transaction.
+type CreateWalletPayload struct {
+	Wallet Wallet `json:"wallet"`
+}
+
+// ContributionPayload is the payload of a contribution transaction. The
+// transaction signature covers the contribution.
+type ContributionPayload struct {
+	Contribution Contribution `json:"contribution"`
+}
+
+// MintNFTPayload is the payload of a mint_nft transaction.
+type MintNFTPayload struct {
+	NFT NFT `json:"nft"`
+	// Triggers lists the story's mint triggers this mint fires. A mint_nft
+	// must fire at least one; authors mint through propose_mint.
+	Triggers []string `json:"triggers,omitempty"`
+}
+
+// ProposeMintPayload is the payload of a propose_mint transaction, which
+// puts NFT up for approval by its authors. The proposal is identified by the
+// transaction ID; the transaction must be signed by the wallet of
+// ProposerID, one of the authors. The approval threshold comes from the
+// story's rules.
+type ProposeMintPayload struct {
+	NFT        NFT    `json:"nft"`
+	ProposerID string `json:"proposer_id"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
+
+// ApproveMintPayload is the payload of an approve_mint transaction, signed
+// by the approving author.
+type ApproveMintPayload struct {
+	ProposalID string `json:"proposal_id"`
+	ApproverID string `json:"approver_id"`
+}
+
+// TransferNFTPayload is the payload of a transfer_nft transaction. The
+// transaction must be signed by the wallet of FromID, the current owner.
+type TransferNFTPayload struct {
+	TokenID   string `json:"token_id"`
+	FromID    string `json:"from_id"`
+	ToID      string `json:"to_id"`
+	ToAddress string `json:"to_address"`
+}
+
+// BurnNFTPayload is the payload of a burn_nft transaction, which retires a
+// token. An owner burn sets OwnerID and is signed by the owner's wallet. A
+// moderation burn leaves OwnerID empty and carries, keyed by validator ID,
+// the validators' signatures over the burn approval encoding of TokenID and
+// Reason instead.
+type BurnNFTPayload struct {
+	TokenID            string            `json:"token_id"`
+	OwnerID            string            `json:"owner_id,omitempty"`
+	Reason             string            `json:"reason,omitempty"`
+	ValidatorApprovals map[string]string `json:"validator_approvals,omitempty"`
+}
+
+// FreezeMetadataPayload is the payload of a freeze_metadata transaction,
+// after which the token's metadata CIDs can no longer change. The
+// transaction must be signed by the wallet of OwnerID, the current owner.
+type FreezeMetadataPayload struct {
+	TokenID string `json:"token_id"`
+	OwnerID string `json:"owner_id"`
+}
+
+// UpdateMetadataPayload is the payload of an update_metadata transaction,
+// which points the token at new IPFS content. The transaction must be signed
+// by the wallet of OwnerID, the current owner.
+type UpdateMetadataPayload struct {
+	TokenID         string `json:"token_id"`
+	OwnerID         string `json:"owner_id"`
+	ImageIPFSCID    string `json:"image_ipfs_cid"`
+	MetadataIPFSCID string `json:"metadata_ipfs_cid"`
+}
+
+// CreateStoryPayload is the payload of a create_story transaction. The
+// transaction must be signed by the wallet of Story.CreatorID.
+type CreateStoryPayload struct {
+	Story StoryRecord `json:"story"`
+}
+
+// AmendContributionPayload is the payload of an amend_contribution
+// transaction, which replaces the text of the contribution committed by
+// transaction TxID. It must be signed by the wallet of ContributorID, the
+// original contributor.
+type AmendContributionPayload struct {
+	TxID          string `json:"tx_id"`
+	ContributorID string `json:"contributor_id"`
+	StoryLine     string `json:"story_line"`
+}
+
+// RetractContributionPayload is the payload of a retract_contribution
+// transaction, signed by the original contributor.
+type RetractContributionPayload struct {
+	TxID          string `json:"tx_id"`
+	ContributorID string `json:"contributor_id"`
+}
+
+// ForkStoryPayload is the payload of a fork_story transaction. Story.Lineage
+// names the parent and branch point; the transaction must be signed by the
+// wallet of Story.CreatorID.
+type ForkStoryPayload struct {
+	Story StoryRecord `json:"story"`
+}
+
+// CloseStoryPayload is the payload of a close_story transaction. The
+// transaction must be signed by the wallet of ClosedBy, the story creator.
+type CloseStoryPayload struct {
+	StoryID  string `json:"story_id"`
+	ClosedBy string `json:"closed_by"`
+}
+
+// InviteContributorPayload is the payload of an invite_contributor
+// transaction, signed by the story creator named in InvitedBy.
+type InviteContributorPayload struct {
+	StoryID   string `json:"story_id"`
+	InviteeID string `json:"invitee_id"`
+	InvitedBy string `json:"invited_by"`
+}
+
+// AcceptInvitePayload is the payload of an accept_invite transaction, signed
+// by the invited user.
+type AcceptInvitePayload struct {
+	StoryID string `json:"story_id"`
+	UserID  string `json:"user_id"`
+}
+
+// RevokeContributorPayload is the payload of a revoke_contributor
+// transaction, signed by the story creator named in RevokedBy.
+type RevokeContributorPayload struct {
+	StoryID       string `json:"story_id"`
+	ContributorID string `json:"contributor_id"`
+	RevokedBy     string `json:"revoked_by"`
+}
+
+// TransferSharesPayload is the payload of a transfer_shares transaction. It
+// moves Amount share units of TokenID and must be signed by the wallet of FromID.
+type TransferSharesPayload struct {
+	TokenID   string `json:"token_id"`
+	FromID    string `json:"from_id"`
+	ToID      string `json:"to_id"`
+	ToAddress string `json:"to_address"`
+	Amount    int64  `json:"amount"`
 }
 
 // Contribution holds information for a single story contribution.
 type Contribution struct {
+	// TxID is the ID of the committed contribution transaction. It is filled
+	// in by chain queries and must be empty in the transaction payload.
+	TxID string `json:"tx_id,omitempty"`
+	// ParentTxID is the contribution this line follows. Left empty, the line
+	// follows the story's latest committed line; queries return the
+	// resolved parent.
+	ParentTxID    string `json:"parent_tx_id,omitempty"`
 	ContributorID string `json:"contributor_id"`
 	WalletAddress string `json:"wallet_address"`
 	StoryID       string `json:"story_id"`
@@ -41,8 +262,42 @@ type NFT struct {
 	CoAuthors       []Author `json:"co_authors"`
 	ImageIPFSCID    string   `json:"image_ipfs_cid"`
 	MetadataIPFSCID string   `json:"metadata_ipfs_cid"`
-	MintedAt        int64    `json:"minted_at"`
-	BlockIndex      int      `json:"block_index"`
+	OwnerID         string   `json:"owner_id"`
+	OwnerAddress    string   `json:"owner_address"`
+	// AuthorshipPolicy names the strategy that weighed the authors, so the
+	// split can be reproduced from the contributions and MintedAt.
+	AuthorshipPolicy string `json:"authorship_policy,omitempty"`
+	// Edition numbers the mints of a story from 1; Supersedes is the token ID
+	// of the previous edition, empty for the first.
+	Edition    int    `json:"edition"`
+	Supersedes string `json:"supersedes,omitempty"`
+	MintedAt   int64  `json:"minted_at"`
+	BlockIndex int    `json:"block_index"`
+	// Status is the lifecycle status, set when the NFT is minted. Burned
+	// tokens stay in the registry with BurnedAt and BurnReason.
+	Status     string `json:"status"`
+	BurnedAt   int64  `json:"burned_at,omitempty"`
+	BurnReason string `json:"burn_reason,omitempty"`
+	// MetadataFrozen pins ImageIPFSCID and MetadataIPFSCID for good.
+	MetadataFrozen bool `json:"metadata_frozen,omitempty"`
+}
+
+// NFT lifecycle states.
+const (
+	NFTStatusActive = "active"
+	NFTStatusBurned = "burned"
+)
+
+// NFTProvenance records one ownership change of an NFT, from mint onwards.
+type NFTProvenance struct {
+	TxID        string `json:"tx_id"`
+	Type        string `json:"type"`
+	FromID      string `json:"from_id,omitempty"`
+	FromAddress string `json:"from_address,omitempty"`
+	ToID        string `json:"to_id"`
+	ToAddress   string `json:"to_address"`
+	BlockIndex  int    `json:"block_index"`
+	Timestamp   int64  `json:"timestamp"`
 }
 
 // Author represents a collaborative writer on a story.
@@ -50,10 +305,19 @@ type Author struct {
 	SupabaseUserID                 string         `json:"supabase_user_id"`
 	WalletAddress                  string         `json:"wallet_address"`
 	ContributionCount              int            `json:"contribution_count"`
+	Weight                         int64          `json:"weight"`
 	OwnershipPercentage            float64        `json:"ownership_percentage"`
 	GithubCommitStyleContributions []Contribution `json:"github_commit_style_contributions"`
 }
 
+// ShareHolding is a live share balance of an NFT.
+type ShareHolding struct {
+	SupabaseUserID      string  `json:"supabase_user_id"`
+	WalletAddress       string  `json:"wallet_address"`
+	Shares              int64   `json:"shares"`
+	OwnershipPercentage float64 `json:"ownership_percentage"`
+}
+
 // Wallet encapsulates a Supabase user's wallet details.
 type Wallet struct {
 	Address             string `json:"address"`
@@ -64,18 +328,184 @@ type Wallet struct {
 	BlockIndex          int    `json:"block_index"`
 }
 
+// Validator identifies a consensus participant declared in the genesis file.
+type Validator struct {
+	ID        string `json:"id"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
 // Story represents the aggregate context used when minting NFTs.
 type Story struct {
 	ID            string         `json:"id"`
 	Title         string         `json:"title"`
 	Summary       string         `json:"summary"`
 	Contributions []Contribution `json:"contributions"`
+	// AuthorshipPolicy selects how contributions are weighed; empty means by count.
+	AuthorshipPolicy string `json:"authorship_policy,omitempty"`
+	// Lineage is set for forks; contributions with another StoryID are inherited.
+	Lineage *StoryLineage `json:"lineage,omitempty"`
+	// Edition and Supersedes are copied to the NFT minted from the story.
+	Edition    int    `json:"edition,omitempty"`
+	Supersedes string `json:"supersedes,omitempty"`
+}
+
+// Story lifecycle states.
+const (
+	StoryStatusOpen   = "open"
+	StoryStatusClosed = "closed"
+)
+
+// StoryRules configures how a story is played and credited. Rules are fixed
+// when the story is created.
+type StoryRules struct {
+	// AuthorshipPolicy selects how contributions are weighed; empty means by count.
+	AuthorshipPolicy string `json:"authorship_policy,omitempty"`
+	// InviteOnly restricts contributions to the creator and accepted invitees.
+	InviteOnly bool `json:"invite_only,omitempty"`
+	// MaxConsecutiveLines caps how many lines in a row one author may add; zero means no cap.
+	MaxConsecutiveLines int `json:"max_consecutive_lines,omitempty"`
+	// MinLineLength and MaxLineLength bound a line's length in characters; zero means no bound.
+	MinLineLength int `json:"min_line_length,omitempty"`
+	MaxLineLength int `json:"max_line_length,omitempty"`
+	// MaxContributors caps the number of distinct authors; zero means no cap.
+	MaxContributors int `json:"max_contributors,omitempty"`
+	// Deadline is the unix time after which contributions are rejected,
+	// compared with the block timestamp; zero means none.
+	Deadline int64 `json:"deadline,omitempty"`
+	// RequireAlternation forbids an author from adding two lines in a row.
+	RequireAlternation bool `json:"require_alternation,omitempty"`
+	// MintWhileOpen lets editions be minted before the story is closed; by
+	// default a story must be closed first.
+	MintWhileOpen bool `json:"mint_while_open,omitempty"`
+	// MintAtContributions and MintAtAuthors mint the story automatically once
+	// it has that many lines or distinct authors; zero disables the trigger.
+	// Both mint open stories, so they require MintWhileOpen.
+	MintAtContributions int `json:"mint_at_contributions,omitempty"`
+	MintAtAuthors       int `json:"mint_at_authors,omitempty"`
+	// MintOnClose mints the story automatically when it is closed.
+	MintOnClose bool `json:"mint_on_close,omitempty"`
+	// MintThreshold is the number of share units, out of 10000, whose
+	// holders must approve a mint proposal; zero means more than half.
+	MintThreshold int64 `json:"mint_threshold,omitempty"`
+}
+
+// StoryProgress summarises the contributions accepted so far, which is all
+// the story rules need to validate the next line.
+type StoryProgress struct {
+	LineCount         int      `json:"line_count"`
+	Contributors      []string `json:"contributors,omitempty"`
+	LastContributorID string   `json:"last_contributor_id,omitempty"`
+	MainTipTxID       string   `json:"main_tip_tx_id,omitempty"`
+	ConsecutiveLines  int      `json:"consecutive_lines,omitempty"`
+}
+
+// Story membership states held in the story ACL.
+const (
+	MemberStatusInvited = "invited"
+	MemberStatusActive  = "member"
+)
+
+// StoryRecord is the on-chain registration of a story. Contributions are
+// accepted while Status is open.
+type StoryRecord struct {
+	ID         string        `json:"id"`
+	Title      string        `json:"title"`
+	CreatorID  string        `json:"creator_id"`
+	Rules      StoryRules    `json:"rules"`
+	Status     string        `json:"status"`
+	CreatedAt  int64         `json:"created_at"`
+	ClosedAt   int64         `json:"closed_at,omitempty"`
+	BlockIndex int           `json:"block_index"`
+	Progress   StoryProgress `json:"progress"`
+	// Lineage is set for stories forked from another story.
+	Lineage *StoryLineage `json:"lineage,omitempty"`
+	// Edition and EditionTokenID identify the latest NFT minted from the
+	// story; zero before the first mint.
+	Edition        int    `json:"edition,omitempty"`
+	EditionTokenID string `json:"edition_token_id,omitempty"`
+	// FiredMintTriggers lists, sorted, the mint triggers of the rules that
+	// already minted an edition. Each trigger fires once.
+	FiredMintTriggers []string `json:"fired_mint_triggers,omitempty"`
+}
+
+// StoryLineage links a forked story to the story it branched from.
+type StoryLineage struct {
+	ParentID string `json:"parent_id"`
+	// BranchTxID is the contribution the fork continues from. The fork
+	// inherits that line and every line it follows, so the inherited prefix
+	// does not move when the parent's main line changes.
+	BranchTxID string `json:"branch_tx_id"`
+	// InheritanceRatio is the number of share units, out of 10000, credited
+	// to the authors of the inherited lines when the fork is minted.
+	InheritanceRatio int64 `json:"inheritance_ratio"`
 }
 
 // State aggregates the on-chain registries required for querying.
+// ShareBalances maps token IDs to the share units held by each Supabase user;
+// StoryMembers maps story IDs to the membership status of each invited user;
+// Contributions maps contribution transaction IDs to their current status;
+// MintProposals maps propose_mint transaction IDs to mints awaiting approval;
+// Nonces maps Supabase user IDs to the nonce of their last committed signed
+// transaction; Validators holds the genesis validators by ID and ChainID the
+// genesis chain ID that signatures are bound to.
 type State struct {
-	WalletRegistry map[string]Wallet `json:"wallet_registry"`
-	NFTRegistry    map[string]NFT    `json:"nft_registry"`
+	ChainID        string                        `json:"chain_id"`
+	WalletRegistry map[string]Wallet             `json:"wallet_registry"`
+	NFTRegistry    map[string]NFT                `json:"nft_registry"`
+	ShareBalances  map[string]map[string]int64   `json:"share_balances"`
+	StoryRegistry  map[string]StoryRecord        `json:"story_registry"`
+	StoryMembers   map[string]map[string]string  `json:"story_members"`
+	Contributions  map[string]ContributionRecord `json:"contributions"`
+	MintProposals  map[string]MintProposal       `json:"mint_proposals"`
+	Nonces         map[string]int64              `json:"nonces"`
+	Validators     map[string]Validator          `json:"validators"`
+}
+
+// MintProposal is a pending mint. It is removed from the state once the
+// approvals reach Threshold, which mints the NFT, or once it expires.
+type MintProposal struct {
+	ID         string `json:"id"`
+	NFT        NFT    `json:"nft"`
+	ProposerID string `json:"proposer_id"`
+	Threshold  int64  `json:"threshold"`
+	// Weights are the share units each author would receive, derived from
+	// the story's lines when the proposal was made. Approvals lists the
+	// approving authors in sorted order, the proposer included;
+	// ApprovedUnits is the sum of their weights.
+	Weights       map[string]int64 `json:"weights"`
+	Approvals     []string         `json:"approvals"`
+	ApprovedUnits int64            `json:"approved_units"`
+	CreatedAt     int64            `json:"created_at"`
+	ExpiresAt     int64            `json:"expires_at"`
+	BlockIndex    int              `json:"block_index"`
+}
+
+// ContributionRecord tracks a committed contribution: the line it follows,
+// which places it in the story graph, its current text, which mints are
+// weighed against, and its revision status. Revision counts the amendments
+// and retraction applied so far.
+type ContributionRecord struct {
+	StoryID       string `json:"story_id"`
+	ParentTxID    string `json:"parent_tx_id,omitempty"`
+	ContributorID string `json:"contributor_id"`
+	StoryLine     string `json:"story_line"`
+	Timestamp     int64  `json:"timestamp"`
+	// Sequence is the number of lines the story had when this one was
+	// committed; replies to the same line are ordered by it.
+	Sequence  int  `json:"sequence"`
+	Revision  int  `json:"revision"`
+	Retracted bool `json:"retracted,omitempty"`
+}
+
+// ContributionRevision is one entry of a contribution's history: the
+// original line, an amendment or the retraction.
+type ContributionRevision struct {
+	TxID       string `json:"tx_id"`
+	Type       string `json:"type"`
+	Revision   int    `json:"revision"`
+	StoryLine  string `json:"story_line,omitempty"`
+	BlockIndex int    `json:"block_index"`
+	Timestamp  int64  `json:"timestamp"`
 }
 
 // NowUnix returns the current unix timestamp to aid testing hooks.
@@ -1,7 +1,6 @@
 package wallet
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -35,8 +34,8 @@ func (m *Manager) GetWalletBySupabaseID(userID string) (types.Wallet, bool) {
 	return m.chain.GetWalletBySupabaseID(userID)
 }
 
-// SignContribution signs the contribution payload using the wallet's private key.
-func (m *Manager) SignContribution(wallet types.Wallet, contribution types.Contribution) (string, error) {
+// SignTransaction signs the signing bytes of tx on the manager's chain using the wallet's private key.
+func (m *Manager) SignTransaction(wallet types.Wallet, tx types.Transaction) (string, error) {
 	if wallet.PrivateKeyEncrypted == "" {
 		return "", errors.New("wallet: encrypted private key missing")
 	}
@@ -46,14 +45,9 @@ func (m *Manager) SignContribution(wallet types.Wallet, contribution types.Contr
 		return "", err
 	}
 
-	payload, err := json.Marshal(contribution)
+	signature, err := utils.SignEd25519(plainPrivKey, blockchain.TransactionSigningBytes(m.chain.ChainID(), tx))
 	if err != nil {
-		return "", fmt.Errorf("wallet: contribution marshal failed: %w", err)
-	}
-
-	signature, err := utils.SignEd25519(plainPrivKey, payload)
-	if err != nil {
-		return "", fmt.Errorf("wallet: sign contribution failed: %w", err)
+		return "", fmt.Errorf("wallet: sign transaction failed: %w", err)
 	}
 
 	return signature, nil
@@ -1,7 +1,6 @@
 package wallet
 
 import (
-	"encoding/json"
 	"testing"
 
 	"storytelling-blockchain/internal/blockchain"
@@ -45,13 +44,17 @@ func TestManagerGetAndSign(t *testing.T) {
 		Timestamp:     555,
 	}
 
-	signature, err := manager.SignContribution(retrieved, contribution)
+	tx, err := blockchain.NewTransaction(blockchain.TxTypeContribution, contribution.Timestamp, types.ContributionPayload{Contribution: contribution})
+	if err != nil {
+		t.Fatalf("build transaction failed: %v", err)
+	}
+
+	signature, err := manager.SignTransaction(retrieved, tx)
 	if err != nil {
 		t.Fatalf("signing failed: %v", err)
 	}
 
-	payload, _ := json.Marshal(contribution)
-	valid, err := utils.VerifyEd25519(retrieved.PublicKey, payload, signature)
+	valid, err := utils.VerifyEd25519(retrieved.PublicKey, blockchain.TransactionSigningBytes(chain.ChainID(), tx), signature)
 	if err != nil {
 		t.Fatalf("verification error: %v", err)
 	}
@@ -60,7 +63,7 @@ func TestManagerGetAndSign(t *testing.T) {
 		t.Fatalf("expected contribution signature to verify")
 	}
 
-	if _, err := manager.SignContribution(types.Wallet{}, contribution); err == nil {
+	if _, err := manager.SignTransaction(types.Wallet{}, tx); err == nil {
 		t.Fatalf("expected error when encrypted key missing")
 	}
 }
@@ -1,14 +1,12 @@
 package wallet
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 
 	"storytelling-blockchain/internal/blockchain"
 	"storytelling-blockchain/internal/consensus/sharding"
 	"storytelling-blockchain/internal/types"
-	"storytelling-blockchain/pkg/utils"
 )
 
 // Storage handles persisting wallet transactions onto the blockchain queue.
@@ -45,24 +43,15 @@ func (s *Storage) StoreWalletOnChain(wallet types.Wallet) (types.Transaction, er
 		return types.Transaction{}, errors.New("wallet: missing supabase user id")
 	}
 
-	timestamp := types.NowUnix()
-	payload, err := json.Marshal(struct {
-		Wallet    types.Wallet `json:"wallet"`
-		Timestamp int64        `json:"timestamp"`
-	}{Wallet: wallet, Timestamp: timestamp})
+	tx, err := blockchain.NewTransaction(blockchain.TxTypeCreateWallet, types.NowUnix(), types.CreateWalletPayload{Wallet: wallet})
 	if err != nil {
-		return types.Transaction{}, fmt.Errorf("wallet: marshal wallet failed: %w", err)
+		return types.Transaction{}, fmt.Errorf("wallet: build transaction failed: %w", err)
 	}
 
-	tx := types.Transaction{
-		Type:      "create_wallet",
-		Data:      wallet,
-		Timestamp: timestamp,
-	}
-	tx.TxID = utils.ComputeSHA256(payload)
-
 	s.chain.RegisterWallet(wallet)
-	s.chain.EnqueueTransaction(tx)
+	if err := s.chain.EnqueueTransaction(tx); err != nil {
+		return types.Transaction{}, fmt.Errorf("wallet: queue transaction failed: %w", err)
+	}
 
 	targetNode := s.selectConsensusNode(wallet.SupabaseUserID)
 
@@ -0,0 +1,60 @@
+package canonical
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestEncoderVector(t *testing.T) {
+	e := NewEncoder("tag").String("hi").Int64(-1).Bytes([]byte{0x01, 0x02})
+
+	const wantEncoding = "00000003746167000000026869ffffffffffffffff000000020102"
+	if got := hex.EncodeToString(e.Encoded()); got != wantEncoding {
+		t.Fatalf("unexpected encoding %s", got)
+	}
+
+	const wantHash = "3b1aab211daf0b0143bcde7f32ea3e17e249066b2f916f9d4327de1a89abc73b"
+	if got := e.Hash(); got != wantHash {
+		t.Fatalf("unexpected hash %s", got)
+	}
+}
+
+func TestEncoderLengthPrefixSeparatesFields(t *testing.T) {
+	a := NewEncoder("tag").String("ab").String("c").Hash()
+	b := NewEncoder("tag").String("a").String("bc").Hash()
+	if a == b {
+		t.Fatalf("field boundaries must affect the encoding")
+	}
+
+	if NewEncoder("tag-a").Hash() == NewEncoder("tag-b").Hash() {
+		t.Fatalf("domain tags must affect the encoding")
+	}
+}
+
+func TestEncodedReturnsCopy(t *testing.T) {
+	e := NewEncoder("tag")
+	encoded := e.Encoded()
+	encoded[0] = 0xFF
+
+	if e.Encoded()[0] == 0xFF {
+		t.Fatalf("Encoded must not expose the internal buffer")
+	}
+}
+
+func TestSigningBytesSeparateDomains(t *testing.T) {
+	message := NewEncoder("tag").String("hi").Encoded()
+
+	base := SigningBytes(PurposeConsensus, "kahani-devnet", message)
+	if bytes.Equal(base, SigningBytes(PurposeBurnApproval, "kahani-devnet", message)) {
+		t.Fatalf("the purpose must affect the signing bytes")
+	}
+	if bytes.Equal(base, SigningBytes(PurposeConsensus, "kahani-testnet", message)) {
+		t.Fatalf("the chain id must affect the signing bytes")
+	}
+
+	want := NewEncoder(TagSignature).String(PurposeConsensus).String("kahani-devnet").Bytes(message).Encoded()
+	if !bytes.Equal(base, want) {
+		t.Fatalf("unexpected signing bytes %x", base)
+	}
+}
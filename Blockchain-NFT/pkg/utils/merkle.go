@@ -0,0 +1,127 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// Merkle proof sibling positions relative to the running hash.
+const (
+	MerkleLeft  = "left"
+	MerkleRight = "right"
+)
+
+const (
+	merkleLeafPrefix byte = 0x00
+	merkleNodePrefix byte = 0x01
+)
+
+// MerkleProofStep is a single sibling hash on the path from a leaf to the root.
+type MerkleProofStep struct {
+	Hash     string `json:"hash"`
+	Position string `json:"position"`
+}
+
+// MerkleRoot computes an RFC 6962 style Merkle tree hash over the provided
+// leaves. Leaves are hashed as SHA-256(0x00 || leaf) and interior nodes as
+// SHA-256(0x01 || left || right); an unbalanced tree splits at the largest
+// power of two smaller than the leaf count. The empty tree hashes to SHA-256("").
+func MerkleRoot(leaves []string) string {
+	if len(leaves) == 0 {
+		sum := sha256.Sum256(nil)
+		return hex.EncodeToString(sum[:])
+	}
+
+	return hex.EncodeToString(merkleTreeHash(leaves))
+}
+
+// MerkleProof returns the audit path for the leaf at index.
+func MerkleProof(leaves []string, index int) ([]MerkleProofStep, error) {
+	if index < 0 || index >= len(leaves) {
+		return nil, errors.New("merkle: leaf index out of range")
+	}
+
+	return merklePath(leaves, index), nil
+}
+
+// VerifyMerkleProof recomputes the root from a leaf and its audit path.
+func VerifyMerkleProof(leaf string, proof []MerkleProofStep, root string) bool {
+	current := merkleLeafHash(leaf)
+
+	for _, step := range proof {
+		sibling, err := hex.DecodeString(step.Hash)
+		if err != nil {
+			return false
+		}
+
+		switch step.Position {
+		case MerkleLeft:
+			current = merkleNodeHash(sibling, current)
+		case MerkleRight:
+			current = merkleNodeHash(current, sibling)
+		default:
+			return false
+		}
+	}
+
+	return hex.EncodeToString(current) == root
+}
+
+// MerkleLeafHash returns SHA-256(0x00 || leaf), the hash of a single leaf.
+func MerkleLeafHash(leaf string) []byte {
+	return merkleLeafHash(leaf)
+}
+
+// MerkleNodeHash returns SHA-256(0x01 || left || right), the hash of an
+// interior node.
+func MerkleNodeHash(left, right []byte) []byte {
+	return merkleNodeHash(left, right)
+}
+
+func merkleTreeHash(leaves []string) []byte {
+	if len(leaves) == 1 {
+		return merkleLeafHash(leaves[0])
+	}
+
+	k := merkleSplit(len(leaves))
+	return merkleNodeHash(merkleTreeHash(leaves[:k]), merkleTreeHash(leaves[k:]))
+}
+
+func merklePath(leaves []string, index int) []MerkleProofStep {
+	if len(leaves) <= 1 {
+		return nil
+	}
+
+	k := merkleSplit(len(leaves))
+	if index < k {
+		path := merklePath(leaves[:k], index)
+		return append(path, MerkleProofStep{Hash: hex.EncodeToString(merkleTreeHash(leaves[k:])), Position: MerkleRight})
+	}
+
+	path := merklePath(leaves[k:], index-k)
+	return append(path, MerkleProofStep{Hash: hex.EncodeToString(merkleTreeHash(leaves[:k])), Position: MerkleLeft})
+}
+
+// merkleSplit returns the largest power of two strictly smaller than n.
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+func merkleLeafHash(leaf string) []byte {
+	sum := sha256.Sum256(append([]byte{merkleLeafPrefix}, leaf...))
+	return sum[:]
+}
+
+func merkleNodeHash(left, right []byte) []byte {
+	buf := make([]byte, 0, 1+len(left)+len(right))
+	buf = append(buf, merkleNodePrefix)
+	buf = append(buf, left...)
+	buf = append(buf, right...)
+	sum := sha256.Sum256(buf)
+	return sum[:]
+}
@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMerkleRootEmptyAndSingle(t *testing.T) {
+	const emptyRoot = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if got := MerkleRoot(nil); got != emptyRoot {
+		t.Fatalf("unexpected empty root: %s", got)
+	}
+
+	single := MerkleRoot([]string{"tx-1"})
+	if single == emptyRoot || single == ComputeSHA256([]byte("tx-1")) {
+		t.Fatalf("expected domain separated leaf hash, got %s", single)
+	}
+}
+
+func TestMerkleProofsVerifyForEveryLeaf(t *testing.T) {
+	for size := 1; size <= 9; size++ {
+		leaves := make([]string, size)
+		for i := range leaves {
+			leaves[i] = fmt.Sprintf("tx-%d", i)
+		}
+
+		root := MerkleRoot(leaves)
+
+		for i, leaf := range leaves {
+			proof, err := MerkleProof(leaves, i)
+			if err != nil {
+				t.Fatalf("size %d leaf %d: proof failed: %v", size, i, err)
+			}
+
+			if !VerifyMerkleProof(leaf, proof, root) {
+				t.Fatalf("size %d leaf %d: proof did not verify", size, i)
+			}
+
+			if VerifyMerkleProof("forged", proof, root) {
+				t.Fatalf("size %d leaf %d: forged leaf verified", size, i)
+			}
+		}
+	}
+}
+
+func TestMerkleProofRejectsOutOfRange(t *testing.T) {
+	if _, err := MerkleProof([]string{"a"}, 1); err == nil {
+		t.Fatalf("expected out of range error")
+	}
+}
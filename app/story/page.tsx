@@ -812,11 +812,12 @@ export default function CollaborativeStoryGame() {
                                                                 ))}
                                                             </div>
                                                         ) : null}
-                                                        {chainStory?.nfts?.length ? (
+                                                        {chainStory?.editions?.length ? (
                                                             <div className="mt-3 flex flex-wrap gap-2 text-xs text-purple-700">
-                                                                {chainStory.nfts.map((nft, index) => {
+                                                                {chainStory.editions.map((nft, index) => {
                                                                     const tokenIdValue = nft && typeof nft === "object" ? (nft["token_id"] as string | undefined) : undefined
-                                                                    const label = tokenIdValue ? `NFT #${tokenIdValue}` : `NFT draft ${index + 1}`
+                                                                    const editionValue = nft && typeof nft === "object" ? (nft["edition"] as number | undefined) : undefined
+                                                                    const label = tokenIdValue ? `Edition ${editionValue ?? index + 1} · NFT #${tokenIdValue}` : `NFT draft ${index + 1}`
                                                                     return (
                                                                         <Badge key={`${label}-${index}`} variant="outline">
                                                                             {label}
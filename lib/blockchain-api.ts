@@ -64,7 +64,8 @@ export interface ChainStoryResponse {
   summary: string | null
   contributions: ChainStoryContribution[]
   authors: string[]
-  nfts: Array<Record<string, unknown>>
+  edition: number
+  editions: Array<Record<string, unknown>>
 }
 
 export interface ChainWalletResponse {
@@ -91,7 +92,10 @@ export interface ChainTransaction<T = Record<string, unknown>> {
 }
 
 export interface ChainMintResponse {
+  proposal_id: string
   nft: Record<string, unknown>
+  threshold: number
+  expires_at: number
   transaction: Record<string, unknown>
 }
 
@@ -125,6 +129,14 @@ export const mintChainStory = (token: string, storyId: string, payload: ChainMin
     body: JSON.stringify(payload),
   })
 
+export const approveChainMint = (token: string, proposalId: string) =>
+  request<ChainTransaction>(`/api/mint/${proposalId}/approve`, {
+    method: "POST",
+    headers: {
+      Authorization: `Bearer ${token}`,
+    },
+  })
+
 export const createChainEventsSocket = () => {
   if (!CHAIN_API_BASE) {
     throw new Error("Chain API base URL is not configured.")